@@ -0,0 +1,105 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+	"text/tabwriter"
+)
+
+// RunAgainst runs the suite created by factory once per backend, as
+// subtests named after the backends in sorted order. Once all backends
+// have run, a conformance matrix showing the outcome of every test against
+// every backend is logged:
+//
+//	conformance matrix:
+//	             memory  postgres
+//	TestGet      pass    FAIL
+//	TestPut      pass    pass
+//
+// RunAgainst is typically used with contract suites (see RunContract) to
+// verify several implementations of the same interface.
+func RunAgainst[B any](t *testing.T, factory func(backend B) TestingSuite, backends map[string]B, opts ...Option) {
+	t.Helper()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	matrix, subtests := newConformanceMatrix(names), make(subtestNames)
+	for _, name := range names {
+		backend, collector := backends[name], &resultCollector{}
+		runOpts := append(append([]Option(nil), opts...), WithReporter(collector))
+		t.Run(subtests.unique(name), func(t *testing.T) {
+			Run(t, factory(backend), runOpts...)
+		})
+		matrix.add(name, collector.results)
+	}
+	t.Logf("suite: conformance matrix:\n%s", matrix)
+}
+
+// resultCollector is a Reporter that keeps the results of the suites it is
+// notified about.
+type resultCollector struct {
+	results []SuiteResult
+}
+
+func (c *resultCollector) SuiteStarted(suiteName string)          {}
+func (c *resultCollector) TestStarted(suiteName, testName string) {}
+func (c *resultCollector) TestFinished(result TestResult)         {}
+
+func (c *resultCollector) SuiteFinished(result SuiteResult) {
+	c.results = append(c.results, result)
+}
+
+// conformanceMatrix holds the status of every test against every backend.
+type conformanceMatrix struct {
+	backends []string
+	tests    []string
+	statuses map[string]map[string]Status
+}
+
+func newConformanceMatrix(backends []string) *conformanceMatrix {
+	return &conformanceMatrix{backends: backends, statuses: make(map[string]map[string]Status)}
+}
+
+func (m *conformanceMatrix) add(backend string, results []SuiteResult) {
+	for _, suite := range results {
+		for _, test := range suite.Tests {
+			if _, ok := m.statuses[test.Name]; !ok {
+				m.statuses[test.Name] = make(map[string]Status)
+				m.tests = append(m.tests, test.Name)
+			}
+			m.statuses[test.Name][backend] = test.Status
+		}
+	}
+}
+
+func (m *conformanceMatrix) String() string {
+	sort.Strings(m.tests)
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	for _, backend := range m.backends {
+		fmt.Fprintf(w, "\t%s", backend)
+	}
+	fmt.Fprintln(w)
+	for _, test := range m.tests {
+		fmt.Fprint(w, test)
+		for _, backend := range m.backends {
+			status, ok := m.statuses[test][backend]
+			switch {
+			case !ok:
+				fmt.Fprint(w, "\t-")
+			case status == StatusFailed:
+				fmt.Fprint(w, "\tFAIL")
+			default:
+				fmt.Fprintf(w, "\t%s", status)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+	return buf.String()
+}
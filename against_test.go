@@ -0,0 +1,36 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteBackendTester struct {
+	Suite
+
+	backend string
+}
+
+func (s *SuiteBackendTester) TestPortable() {}
+
+func (s *SuiteBackendTester) TestUnportable() {
+	if s.backend == "broken" {
+		s.T().Fail()
+	}
+}
+
+func TestRunAgainst(t *testing.T) {
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		RunAgainst(t, func(backend string) TestingSuite {
+			return &SuiteBackendTester{backend: backend}
+		}, map[string]string{"working": "working", "broken": "broken"})
+	})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/broken/TestUnportable")
+	assert.Contains(t, output, "conformance matrix:")
+	assert.Regexp(t, `TestPortable\s+pass\s+pass`, output)
+	assert.Regexp(t, `TestUnportable\s+FAIL\s+pass`, output)
+}
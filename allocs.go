@@ -0,0 +1,97 @@
+package suite
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Allocs counts the heap allocations made while a test method ran.
+type Allocs struct {
+	// Count is the number of heap objects allocated.
+	Count uint64
+	// Bytes is the number of bytes allocated.
+	Bytes uint64
+}
+
+// AllocBudget is the maximum number of allocations of a test method. Zero
+// fields are not limited.
+type AllocBudget struct {
+	Count uint64
+	Bytes uint64
+}
+
+// AllocBudgetSuite has an AllocBudgets method returning the allocation
+// budgets of its tests, keyed by the name of the test method. A test
+// allocating more than its budget fails.
+type AllocBudgetSuite interface {
+	AllocBudgets() map[string]AllocBudget
+}
+
+// SetAllocBudget sets the allocation budget of the test method named test,
+// overriding the budget returned by the suite's AllocBudgets method. It is
+// typically called in SetupSuite.
+func (suite *Suite) SetAllocBudget(test string, budget AllocBudget) {
+	if suite.allocBudgets == nil {
+		suite.allocBudgets = make(map[string]AllocBudget)
+	}
+	suite.allocBudgets[test] = budget
+}
+
+// allocBudget returns the allocation budget of the test method name.
+func (r *suiteRun) allocBudget(name string) (AllocBudget, bool) {
+	if b, ok := r.suite.(suiteBase); ok {
+		if budget, ok := b.base().allocBudgets[name]; ok {
+			return budget, true
+		}
+	}
+	if s, ok := r.suite.(AllocBudgetSuite); ok {
+		budget, ok := s.AllocBudgets()[name]
+		return budget, ok
+	}
+	return AllocBudget{}, false
+}
+
+// trackAllocs starts counting the allocations of the test method name.
+// The returned function stores them in allocs and fails the test if they
+// exceed the test's budget.
+//
+// The counts are read from the runtime's statistics, so they include the
+// allocations of other goroutines running at the same time. They aren't
+// counted for tests running in parallel, as they would mostly be those of
+// the other tests, and the tests with a budget fail then.
+func (r *suiteRun) trackAllocs(t *testing.T, name string, allocs *Allocs) func() {
+	if r.opts.parallel {
+		if _, ok := r.allocBudget(name); ok {
+			t.Errorf("suite: the allocation budget of %s can't be checked for tests running in parallel, whose allocations can't be told apart", name)
+		}
+		return func() {}
+	}
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	return func() {
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+		*allocs = Allocs{
+			Count: after.Mallocs - before.Mallocs,
+			Bytes: after.TotalAlloc - before.TotalAlloc,
+		}
+		budget, ok := r.allocBudget(name)
+		if !ok {
+			return
+		}
+		var exceeded []string
+		if budget.Count > 0 && allocs.Count > budget.Count {
+			exceeded = append(exceeded, fmt.Sprintf("%d allocations (budget %d, before %d, after %d)",
+				allocs.Count, budget.Count, before.Mallocs, after.Mallocs))
+		}
+		if budget.Bytes > 0 && allocs.Bytes > budget.Bytes {
+			exceeded = append(exceeded, fmt.Sprintf("%d bytes (budget %d, before %d, after %d)",
+				allocs.Bytes, budget.Bytes, before.TotalAlloc, after.TotalAlloc))
+		}
+		if len(exceeded) > 0 {
+			t.Errorf("suite: %s exceeded its allocation budget: %s", name, strings.Join(exceeded, ", "))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allocSink [][]byte
+
+type SuiteAllocBudgetTester struct {
+	Suite
+}
+
+func (s *SuiteAllocBudgetTester) AllocBudgets() map[string]AllocBudget {
+	return map[string]AllocBudget{
+		"TestWithinBudget": {Bytes: 1 << 20},
+		"TestOverBudget":   {Count: 10},
+	}
+}
+
+func (s *SuiteAllocBudgetTester) SetupSuite() {
+	s.SetAllocBudget("TestOverridden", AllocBudget{Bytes: 1 << 10})
+}
+
+func (s *SuiteAllocBudgetTester) TestWithinBudget() {
+	allocSink = append(allocSink[:0], make([]byte, 1<<10))
+}
+
+func (s *SuiteAllocBudgetTester) TestOverBudget() {
+	for i := 0; i < 100; i++ {
+		allocSink = append(allocSink, make([]byte, 64))
+	}
+}
+
+func (s *SuiteAllocBudgetTester) TestOverridden() {
+	allocSink = append(allocSink[:0], make([]byte, 1<<16))
+}
+
+func TestAllocBudget(t *testing.T) {
+	reporter := &recordingReporter{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAllocBudgetTester), WithReporter(reporter))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	statuses := map[string]Status{}
+	for _, result := range reporter.Finished {
+		statuses[result.Name] = result.Status
+		assert.NotZero(t, result.Allocs.Count, result.Name)
+		assert.NotZero(t, result.Allocs.Bytes, result.Name)
+	}
+	assert.Equal(t, map[string]Status{
+		"TestWithinBudget": StatusPassed,
+		"TestOverBudget":   StatusFailed,
+		"TestOverridden":   StatusFailed,
+	}, statuses)
+	assert.Contains(t, output, "suite: TestOverBudget exceeded its allocation budget: ")
+	assert.Contains(t, output, " allocations (budget 10, before ")
+	assert.Contains(t, output, " bytes (budget 1024, before ")
+}
+
+func TestAllocBudgetParallel(t *testing.T) {
+	reporter := &recordingReporter{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAllocBudgetTester), WithReporter(reporter), WithParallel())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	for _, result := range reporter.Finished {
+		assert.Zero(t, result.Allocs, result.Name)
+		assert.Equal(t, StatusFailed, result.Status, result.Name)
+	}
+	assert.Contains(t, output, "suite: the allocation budget of TestWithinBudget can't be checked for tests running in parallel")
+}
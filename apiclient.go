@@ -0,0 +1,157 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// defaultAPIExchanges is the default of APIClient.Keep.
+const defaultAPIExchanges = 5
+
+// APIClient is an HTTP client for the API under test, created with
+// Suite.NewAPIClient, typically in SetupSuite:
+//
+//	func (s *UsersAPISuite) SetupSuite() {
+//		s.api = s.NewAPIClient(s.server.URL, http.Header{
+//			"Authorization": {"Bearer " + token},
+//		})
+//	}
+//
+//	func (s *UsersAPISuite) TestList() {
+//		s.HTTP(s.api.Get("/users")).Status(200)
+//	}
+//
+// Every request is logged to the current test. When a test fails, its
+// last requests and responses are attached to its artifacts, see
+// Suite.ArtifactDir. Requests failing to be sent fail the test. The client
+// follows the current test of the suite that created it, so it can't be
+// used by suites run with WithParallel, whose tests run on copies of the
+// suite.
+type APIClient struct {
+	// BaseURL is prepended to the paths of the requests.
+	BaseURL string
+	// Header holds the headers set on every request, e.g. Authorization.
+	Header http.Header
+	// Client sends the requests, http.DefaultClient by default.
+	Client *http.Client
+	// Keep is the number of the last requests of a test attached to its
+	// artifacts when it fails, 5 by default.
+	Keep int
+
+	suite     *Suite
+	mu        sync.Mutex
+	exchanges map[*testing.T][]string
+}
+
+// NewAPIClient returns a client sending requests to baseURL with header.
+func (suite *Suite) NewAPIClient(baseURL string, header http.Header) *APIClient {
+	return &APIClient{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		Header:    header,
+		Keep:      defaultAPIExchanges,
+		suite:     suite,
+		exchanges: make(map[*testing.T][]string),
+	}
+}
+
+// NewRequest returns a request of the API at path, with the headers of
+// the client.
+func (c *APIClient) NewRequest(method, path string, body io.Reader) *http.Request {
+	t := c.suite.T()
+	t.Helper()
+	req, err := http.NewRequestWithContext(c.suite.Context(), method, c.BaseURL+path, body)
+	if err != nil {
+		t.Fatalf("suite: invalid API request: %v", err)
+	}
+	for name, values := range c.Header {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	return req
+}
+
+// Get sends a GET request of the API at path.
+func (c *APIClient) Get(path string) *http.Response {
+	c.suite.T().Helper()
+	return c.Do(c.NewRequest(http.MethodGet, path, nil))
+}
+
+// Post sends a POST request of the API at path with body.
+func (c *APIClient) Post(path, contentType string, body io.Reader) *http.Response {
+	c.suite.T().Helper()
+	req := c.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// Do sends req, logging it to the current test and keeping it and its
+// response to attach them to the artifacts of the test if it fails.
+func (c *APIClient) Do(req *http.Request) *http.Response {
+	t := c.suite.T()
+	t.Helper()
+	requestDump, _ := httputil.DumpRequestOut(req, true)
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		c.keep(t, string(requestDump)+"\n"+err.Error())
+		t.Fatal(c.suite.redact(fmt.Sprintf("suite: %s %s failed: %v", req.Method, req.URL, err)))
+	}
+	t.Log(c.suite.redact(fmt.Sprintf("suite: %s %s: %s (%v)", req.Method, req.URL, resp.Status, elapsed)))
+	responseDump, _ := httputil.DumpResponse(resp, true)
+	c.keep(t, string(requestDump)+"\n"+string(responseDump))
+	return resp
+}
+
+// keep keeps the exchange of the test t, registering the attachment of
+// its exchanges on its first one.
+func (c *APIClient) keep(t *testing.T, exchange string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exchanges, ok := c.exchanges[t]
+	if !ok {
+		t.Cleanup(func() {
+			c.mu.Lock()
+			exchanges := c.exchanges[t]
+			delete(c.exchanges, t)
+			c.mu.Unlock()
+			if t.Failed() {
+				c.attach(t, exchanges)
+			}
+		})
+	}
+	exchanges = append(exchanges, exchange)
+	if keep := c.Keep; keep > 0 && len(exchanges) > keep {
+		exchanges = exchanges[len(exchanges)-keep:]
+	}
+	c.exchanges[t] = exchanges
+}
+
+// attach writes the exchanges of the failed test t to its artifacts.
+func (c *APIClient) attach(t *testing.T, exchanges []string) {
+	var buf bytes.Buffer
+	for i, exchange := range exchanges {
+		if i > 0 {
+			buf.WriteString("\n\n")
+		}
+		fmt.Fprintf(&buf, "=== request %d of %d ===\n%s", i+1, len(exchanges), exchange)
+	}
+	path := filepath.Join(c.suite.artifactDirFor(t), "api-requests.txt")
+	if err := os.WriteFile(path, []byte(c.suite.redact(buf.String())), 0644); err != nil {
+		t.Logf("suite: failed to attach API requests: %v", err)
+		return
+	}
+	t.Logf("suite: attached the last %d API requests to %s", len(exchanges), path)
+}
@@ -0,0 +1,66 @@
+package suite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteAPIClientTester struct {
+	Suite
+	server *httptest.Server
+	api    *APIClient
+}
+
+func (s *SuiteAPIClientTester) SetupSuite() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("hello " + strings.TrimPrefix(r.URL.Path, "/users/")))
+	}))
+	s.api = s.NewAPIClient(s.server.URL+"/", http.Header{"Authorization": {"Bearer hunter2"}})
+	s.api.Keep = 2
+}
+
+func (s *SuiteAPIClientTester) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *SuiteAPIClientTester) TestPasses() {
+	s.HTTP(s.api.Get("/users/alice")).Status(200).BodyContains("hello alice")
+}
+
+func (s *SuiteAPIClientTester) TestFails() {
+	s.api.Get("/users/alice")
+	s.api.Get("/users/bob")
+	s.api.Post("/users/carol", "text/plain", strings.NewReader("carol"))
+	s.T().Fail()
+}
+
+func TestAPIClient(t *testing.T) {
+	dir := t.TempDir()
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAPIClientTester), WithArtifactDir(dir))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Regexp(t, `suite: POST http://127\.0\.0\.1:\d+/users/carol: 200 OK \(\d+m?s\)`, output)
+
+	path := filepath.Join(dir, "DetachedSuite", "TestFails", "api-requests.txt")
+	assert.Contains(t, output, "suite: attached the last 2 API requests to "+path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	attached := string(data)
+	assert.NotContains(t, attached, "alice")
+	assert.Contains(t, attached, "=== request 1 of 2 ===\nGET /users/bob HTTP/1.1")
+	assert.Contains(t, attached, "Authorization: Bearer hunter2")
+	assert.Contains(t, attached, "=== request 2 of 2 ===\nPOST /users/carol HTTP/1.1")
+	assert.Contains(t, attached, "hello carol")
+	assert.NoFileExists(t, filepath.Join(dir, "DetachedSuite", "TestPasses", "api-requests.txt"))
+}
@@ -0,0 +1,56 @@
+package suite
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var artifactDir = flag.String("testify.artifact-dir", "", "keep the artifacts of testify suites in this directory")
+
+// WithArtifactDir sets the directory in which the artifacts of the suite's
+// tests are kept, see Suite.ArtifactDir. It defaults to the
+// -testify.artifact-dir flag.
+func WithArtifactDir(dir string) Option {
+	return func(o *options) {
+		o.artifactDir = dir
+	}
+}
+
+// ArtifactDir returns a directory for the artifacts of the current test,
+// such as logs or screenshots, creating it if needed. The directory is
+// named after the test, within the directory set with WithArtifactDir or
+// the -testify.artifact-dir flag, where it is kept for inspection after
+// the test run, e.g. by CI, unless the retention policy of the suite is
+// RetainNever. When no artifact directory is set, a temporary directory
+// kept according to the retention policy is returned, see TempDir.
+func (suite *Suite) ArtifactDir() string {
+	t := suite.T()
+	t.Helper()
+	return suite.artifactDirFor(t)
+}
+
+// artifactDirFor returns the directory for the artifacts of the test t.
+func (suite *Suite) artifactDirFor(t *testing.T) string {
+	t.Helper()
+	if suite.artifactDir == "" {
+		return suite.tempDirFor(t)
+	}
+	dir := testArtifactDir(suite.artifactDir, t.Name())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("suite: failed to create artifact directory: %v", err)
+	}
+	if suite.retention == RetainNever {
+		suite.disposeFor(t, "artifact directory "+dir, RetainNever, func() {
+			os.RemoveAll(dir)
+		})
+	}
+	return dir
+}
+
+// testArtifactDir returns the directory within root for the artifacts of
+// the test named name.
+func testArtifactDir(root, name string) string {
+	return filepath.Join(root, filepath.FromSlash(name))
+}
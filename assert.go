@@ -0,0 +1,107 @@
+package suite
+
+import (
+	"flag"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var warnNoAssertions = flag.Bool("testify.warn-no-assertions", false, "warn about testify suite tests that made no assertions")
+
+// Assert returns assertions reporting their failures to the current test,
+// like the functions of github.com/stretchr/testify/assert:
+//
+//	s.Assert().Equal(200, resp.StatusCode)
+//
+// Every assertion made with them counts as an assertion of the test, see
+// TestResult.Assertions.
+func (suite *Suite) Assert() *assert.Assertions {
+	return assert.New(suite.assertT())
+}
+
+// Require returns assertions stopping the current test when they fail,
+// like the functions of github.com/stretchr/testify/require:
+//
+//	s.Require().NoError(err)
+//
+// Every assertion made with them counts as an assertion of the test, see
+// TestResult.Assertions.
+func (suite *Suite) Require() *require.Assertions {
+	return require.New(suite.assertT())
+}
+
+// countAssertion counts an assertion of the current test.
+func (suite *Suite) countAssertion() {
+	suite.test.assertions++
+}
+
+// testifyPrefix is the prefix of the names of the functions of testify.
+var testifyPrefix = strings.TrimSuffix(reflect.TypeOf(assert.Assertions{}).PkgPath(), "assert")
+
+// helperT is the TestingT with which markTestifyHelpers calls assertions,
+// discarding their failures.
+type helperT struct {
+	*testing.T
+}
+
+func (helperT) Errorf(format string, args ...interface{}) {}
+
+func (helperT) FailNow() {}
+
+// markTestifyHelpers marks the assertions of testify calling its caller as
+// helpers of t, so that t attributes their failures to the code calling
+// them. The Helper method of the TestingT of the suite's assertions counts
+// them instead, so they are marked by calling them again, with the zero
+// values of their arguments, with a TestingT whose Helper method is that
+// of t.
+func markTestifyHelpers(t *testing.T) {
+	h := helperT{T: t}
+	assertions := map[string]reflect.Value{
+		"assert":  reflect.ValueOf(assert.New(h)),
+		"require": reflect.ValueOf(require.New(h)),
+	}
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, testifyPrefix); ok {
+			// Both assert.Equal and assert.(*Assertions).Equal are marked
+			// by calling the latter.
+			pkg, name, _ := strings.Cut(name, ".")
+			if method := assertions[pkg].MethodByName(strings.TrimPrefix(name, "(*Assertions).")); method.IsValid() {
+				callWithZeroValues(method)
+			}
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// callWithZeroValues calls the variadic function fn with the zero values
+// of its arguments, recovering from its panic.
+func callWithZeroValues(fn reflect.Value) {
+	defer func() {
+		_ = recover()
+	}()
+	in := make([]reflect.Value, fn.Type().NumIn())
+	for i := range in {
+		in[i] = reflect.Zero(fn.Type().In(i))
+	}
+	fn.CallSlice(in)
+}
+
+// WithNoAssertionsWarning logs a warning for every test of the suite that
+// passed without making any assertion through the assertions of the suite,
+// which usually means that the test doesn't verify what it is meant to. It
+// is also enabled for all suites by the -testify.warn-no-assertions flag.
+func WithNoAssertionsWarning() Option {
+	return func(o *options) {
+		o.warnNoAssertions = true
+	}
+}
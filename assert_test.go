@@ -0,0 +1,70 @@
+package suite
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteAssertionCountTester struct {
+	Suite
+}
+
+func (s *SuiteAssertionCountTester) TestAssertions() {
+	s.Assert().Equal(1, 1)
+	s.Assert().Len([]int{1}, 1)
+	s.Require().NoError(nil)
+	s.Require().ElementsMatch([]int{1, 2}, []int{2, 1})
+	s.Equal("a", "a")
+	s.JSONEq(`{"a": 1}`, `{"a":1}`)
+	s.Eventually(func() bool { return true }, time.Second, time.Millisecond)
+}
+
+func (s *SuiteAssertionCountTester) TestFailingAssertion() {
+	s.Assert().Equal(1, 2)
+	s.Require().EqualError(errors.New("a"), "b")
+}
+
+func (s *SuiteAssertionCountTester) TestNothing() {}
+
+func (s *SuiteAssertionCountTester) TestUnusedAssertions() {
+	// Getting the assertions doesn't count, using them does.
+	s.Assert()
+	s.Require()
+}
+
+func (s *SuiteAssertionCountTester) TestReusedAssertions() {
+	require := s.Require()
+	for i := 0; i < 3; i++ {
+		require.True(true)
+	}
+}
+
+func TestAssertionCounts(t *testing.T) {
+	reporter := &recordingReporter{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAssertionCountTester), WithReporter(reporter), WithNoAssertionsWarning())
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	counts := map[string]int{}
+	for _, result := range reporter.Finished {
+		counts[result.Name] = result.Assertions
+	}
+	assert.Equal(t, map[string]int{
+		"TestAssertions":       7,
+		"TestFailingAssertion": 2,
+		"TestNothing":          0,
+		"TestUnusedAssertions": 0,
+		"TestReusedAssertions": 3,
+	}, counts)
+	if testing.Verbose() {
+		assert.Contains(t, output, "suite: warning: TestNothing made no assertions")
+		assert.Contains(t, output, "suite: warning: TestUnusedAssertions made no assertions")
+	}
+	assert.NotContains(t, output, "TestAssertions made no assertions")
+	assert.NotContains(t, output, "assertions.go:", "failures are attributed to the test")
+	assert.Regexp(t, `assert_test.go:\d+: \n\s+Error Trace:`, output)
+}
@@ -0,0 +1,41 @@
+package suite
+
+import "fmt"
+
+// errorf reports an assertion failure of one of the suite's helpers on
+// the current test.
+func (suite *Suite) errorf(msgAndArgs []interface{}, format string, args ...interface{}) {
+	t := suite.T()
+	t.Helper()
+	message := suite.prefix + fmt.Sprintf(format, args...)
+	if extra := messageFromMsgAndArgs(msgAndArgs...); extra != "" {
+		message += "\nMessages: " + extra
+	}
+	message = suite.redact(message)
+	if attempt := suite.test.setupAttempt; attempt != nil {
+		attempt.failures = append(attempt.failures, message)
+		return
+	}
+	suite.test.failures = append(suite.test.failures, message)
+	t.Error(message)
+	suite.dumpGoroutines()
+}
+
+// messageFromMsgAndArgs formats the optional message arguments of the
+// assertion helpers, following the conventions of testify: a single value
+// is printed as is, more values are treated as a format and its arguments.
+func messageFromMsgAndArgs(msgAndArgs ...interface{}) string {
+	switch len(msgAndArgs) {
+	case 0:
+		return ""
+	case 1:
+		if msg, ok := msgAndArgs[0].(string); ok {
+			return msg
+		}
+		return fmt.Sprintf("%+v", msgAndArgs[0])
+	}
+	if format, ok := msgAndArgs[0].(string); ok {
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return fmt.Sprint(msgAndArgs...)
+}
@@ -0,0 +1,258 @@
+package suite
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	benchBaseline  = flag.String("testify.bench-baseline", "", "compare the benchmarks of testify suites against the baseline in this file")
+	benchRecord    = flag.Bool("testify.bench-record", false, "record the benchmarks of testify suites to -testify.bench-baseline instead of comparing them")
+	benchThreshold = flag.Float64("testify.bench-threshold", 0.1, "slowdown of a testify benchmark, relative to its baseline, failing it")
+	benchWarn      = flag.Bool("testify.bench-warn", false, "warn about regressions of testify benchmarks instead of failing them")
+)
+
+// defaultBenchSamples is the default of BenchmarkGate.Samples.
+const defaultBenchSamples = 5
+
+// benchSignificance is the p-value below which a difference between a
+// benchmark and its baseline is considered significant.
+const benchSignificance = 0.05
+
+// BenchmarkGate compares the benchmarks run by Suite.Benchmark against a
+// baseline recorded by an earlier run, failing the benchmarks that
+// regressed. Like benchstat, it runs every benchmark several times, and a
+// benchmark regressed when its median time per operation grew beyond the
+// threshold and a Mann-Whitney U test finds the difference significant.
+type BenchmarkGate struct {
+	// Baseline is the path of the baseline file.
+	Baseline string
+	// Record records the benchmarks to the baseline file instead of
+	// comparing them with it.
+	Record bool
+	// Threshold is the growth of the median time per operation beyond
+	// which a benchmark regressed, e.g. 0.1 for 10%.
+	Threshold float64
+	// Samples is the number of times every benchmark runs, 5 by default.
+	Samples int
+	// WarnOnly logs regressions instead of failing the benchmarks.
+	WarnOnly bool
+
+	// mu guards the baseline file.
+	mu sync.Mutex
+}
+
+// WithBenchmarkGate compares the benchmarks of the suite against the
+// baseline of gate. The gate is also enabled for all suites by the
+// -testify.bench-baseline flag, configured by -testify.bench-record,
+// -testify.bench-threshold and -testify.bench-warn.
+func WithBenchmarkGate(gate *BenchmarkGate) Option {
+	return func(o *options) {
+		o.benchGate = gate
+	}
+}
+
+// benchGateFromFlags returns the gate enabled on the command line, if any,
+// shared by all suites so that they update the baseline file in turn.
+var benchGateFromFlags = sync.OnceValue(func() *BenchmarkGate {
+	if *benchBaseline == "" {
+		return nil
+	}
+	return &BenchmarkGate{
+		Baseline:  *benchBaseline,
+		Record:    *benchRecord,
+		Threshold: *benchThreshold,
+		WarnOnly:  *benchWarn,
+	}
+})
+
+// Benchmark runs the benchmark f as part of the current test, logging its
+// time per operation, e.g. to keep an eye on the performance of code
+// exercised by an integration suite. The benchmark runs for -test.benchtime
+// like those of go test -bench. With a BenchmarkGate, it runs several
+// times and is compared against, or recorded as, the baseline of the
+// benchmark, identified by the name of the test and name.
+func (suite *Suite) Benchmark(name string, f func(b *testing.B)) {
+	t := suite.T()
+	t.Helper()
+	samples := 1
+	if suite.benchGate != nil {
+		samples = suite.benchGate.samples()
+	}
+	var ns []float64
+	for i := 0; i < samples; i++ {
+		result := testing.Benchmark(f)
+		if result.N == 0 {
+			t.Errorf("suite: benchmark %s failed", name)
+			return
+		}
+		ns = append(ns, float64(result.T)/float64(result.N))
+	}
+	key := t.Name() + "/" + name
+	t.Logf("suite: benchmark %s: %s", name, benchStats(ns))
+	if suite.benchGate == nil {
+		return
+	}
+	if suite.benchGate.Record {
+		if err := suite.benchGate.record(key, ns); err != nil {
+			t.Errorf("suite: failed to record baseline of benchmark %s: %v", name, err)
+		}
+		return
+	}
+	if err := suite.benchGate.compare(key, ns); err != nil {
+		if suite.benchGate.WarnOnly {
+			t.Logf("suite: warning: benchmark %s %v", name, err)
+			return
+		}
+		t.Errorf("suite: benchmark %s %v", name, err)
+	}
+}
+
+func (g *BenchmarkGate) samples() int {
+	if g.Samples > 0 {
+		return g.Samples
+	}
+	return defaultBenchSamples
+}
+
+// load reads the baseline file, which maps the keys of the benchmarks to
+// their times per operation in nanoseconds.
+func (g *BenchmarkGate) load() (map[string][]float64, error) {
+	baseline := make(map[string][]float64)
+	data, err := os.ReadFile(g.Baseline)
+	if errors.Is(err, fs.ErrNotExist) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %v", g.Baseline, err)
+	}
+	return baseline, nil
+}
+
+// record sets the baseline of the benchmark key.
+func (g *BenchmarkGate) record(key string, ns []float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	baseline, err := g.load()
+	if err != nil {
+		return err
+	}
+	baseline[key] = ns
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(g.Baseline, append(data, '\n'), 0644)
+}
+
+// compare returns an error if the benchmark key regressed from its
+// baseline.
+func (g *BenchmarkGate) compare(key string, ns []float64) error {
+	g.mu.Lock()
+	baseline, err := g.load()
+	g.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	old, ok := baseline[key]
+	if !ok {
+		return nil
+	}
+	delta := median(ns)/median(old) - 1
+	p := mannWhitneyU(old, ns)
+	if delta <= g.Threshold || p >= benchSignificance {
+		return nil
+	}
+	return fmt.Errorf("regressed: %s → %s (%+.1f%%, p=%.3f n=%d+%d)",
+		benchStats(old), benchStats(ns), delta*100, p, len(old), len(ns))
+}
+
+// benchStats formats the times per operation of the samples of a
+// benchmark as their median and their largest deviation from it, e.g.
+// "1.2µs ± 3%".
+func benchStats(ns []float64) string {
+	m := median(ns)
+	var deviation float64
+	for _, x := range ns {
+		deviation = math.Max(deviation, math.Abs(x-m))
+	}
+	s := formatNS(m)
+	if len(ns) > 1 && m > 0 {
+		s += fmt.Sprintf(" ± %.0f%%", deviation/m*100)
+	}
+	return s
+}
+
+// formatNS formats a time per operation given in nanoseconds.
+func formatNS(ns float64) string {
+	if ns < 1000 {
+		return fmt.Sprintf("%.3gns", ns)
+	}
+	return time.Duration(ns).Round(time.Duration(math.Pow(10, math.Floor(math.Log10(ns))-2))).String()
+}
+
+func median(xs []float64) float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// mannWhitneyU returns the two-sided p-value of the Mann-Whitney U test
+// of the samples x and y, using the normal approximation with a
+// correction for ties.
+func mannWhitneyU(x, y []float64) float64 {
+	type sample struct {
+		value float64
+		inX   bool
+	}
+	var all []sample
+	for _, v := range x {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	n1, n2, n := float64(len(x)), float64(len(y)), float64(len(all))
+	var rankSum, ties float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		// Tied values share the average of their ranks.
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].inX {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	u := rankSum - n1*(n1+1)/2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (math.Abs(u-n1*n2/2) - 0.5) / sigma
+	return math.Erfc(math.Max(z, 0) / math.Sqrt2)
+}
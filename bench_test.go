@@ -0,0 +1,61 @@
+package suite
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteBenchmarkTester struct {
+	Suite
+	delay time.Duration
+}
+
+func (s *SuiteBenchmarkTester) TestEncode() {
+	s.Benchmark("encode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			time.Sleep(s.delay)
+		}
+	})
+}
+
+func TestBenchmarkGate(t *testing.T) {
+	benchtime := flag.Lookup("test.benchtime").Value
+	defer benchtime.Set(benchtime.String())
+	benchtime.Set("20x")
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	record := &BenchmarkGate{Baseline: path, Record: true, Threshold: 0.5}
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteBenchmarkTester{delay: time.Microsecond}, WithBenchmarkGate(record))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	baseline, err := record.load()
+	require.NoError(t, err)
+	assert.Len(t, baseline["DetachedSuite/TestEncode/encode"], defaultBenchSamples)
+
+	gate := &BenchmarkGate{Baseline: path, Threshold: 0.5}
+	ok, output, err = runDetachedSuiteWithOutputCapture(&SuiteBenchmarkTester{delay: 2 * time.Millisecond}, WithBenchmarkGate(gate))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Regexp(t, `suite: benchmark encode regressed: \S+ ± \d+% → \S+ ± \d+% \(\+\d+\.\d%, p=0\.0\d\d n=5\+5\)`, output)
+
+	gate.WarnOnly = true
+	ok, output, err = runDetachedSuiteWithOutputCapture(&SuiteBenchmarkTester{delay: 2 * time.Millisecond}, WithBenchmarkGate(gate))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	assert.InDelta(t, 0.0122, mannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}), 0.001)
+	assert.InDelta(t, 1, mannWhitneyU([]float64{1, 2, 3}, []float64{1, 2, 3}), 0.001)
+	assert.Equal(t, 1.0, mannWhitneyU([]float64{1, 1}, []float64{1, 1}))
+}
+
+func TestBenchStats(t *testing.T) {
+	assert.Equal(t, "1.2µs ± 8%", benchStats([]float64{1100, 1200, 1250}))
+	assert.Equal(t, "42.5ns", benchStats([]float64{42.5}))
+}
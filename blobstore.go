@@ -0,0 +1,169 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ErrBlobNotFound is returned by BlobStores for keys they don't hold.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore is a minimal object store, such as an S3 bucket. Code under
+// test depending on this interface, or on an adapter of it, can be tested
+// against the MemBlobStore of the suite, see Suite.BlobStore, while
+// adapters of real stores implement it for integration runs.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrBlobNotFound if there is no object at key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete deletes the object at key, if any.
+	Delete(ctx context.Context, key string) error
+	// List returns the sorted keys starting with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// MemBlobStore is an in-memory BlobStore. It is safe for concurrent use.
+type MemBlobStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// baseline are the objects the store is reset to before every test.
+	baseline map[string][]byte
+}
+
+// NewMemBlobStore returns an empty store.
+func NewMemBlobStore() *MemBlobStore {
+	return &MemBlobStore{objects: make(map[string][]byte)}
+}
+
+func (m *MemBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (m *MemBlobStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *MemBlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Snapshot writes the objects of the store as files named after their
+// keys into dir. Keys that aren't local paths, e.g. containing "..", are
+// escaped.
+func (m *MemBlobStore) Snapshot(dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, data := range m.objects {
+		name := filepath.FromSlash(key)
+		if !filepath.IsLocal(name) || strings.HasSuffix(key, "/") {
+			name = sanitizeName(fmt.Sprintf("%q", key))
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commit makes the current objects the baseline of the store.
+func (m *MemBlobStore) commit() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseline = copyObjects(m.objects)
+}
+
+// reset restores the baseline of the store.
+func (m *MemBlobStore) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects = copyObjects(m.baseline)
+}
+
+func copyObjects(objects map[string][]byte) map[string][]byte {
+	c := make(map[string][]byte, len(objects))
+	for key, data := range objects {
+		c[key] = data
+	}
+	return c
+}
+
+// BlobStore returns the in-memory object store of the suite, creating it
+// on the first call. Objects stored in SetupSuite are kept for all tests,
+// while the objects stored by a test are removed after it, so that tests
+// don't see each other's objects. When a test fails, the objects are
+// written to its artifacts, in the blobs directory, see Suite.ArtifactDir.
+//
+// When the tests run in parallel, see WithParallel, the tests calling
+// BlobStore are serialized from their first call until they finish, as
+// they would see each other's objects. Tests using the store through the
+// code under test must call BlobStore before that code uses it.
+func (suite *Suite) BlobStore() *MemBlobStore {
+	store := suite.blobStore
+	if store == nil {
+		store = NewMemBlobStore()
+		suite.BeforeAllTests(store.commit)
+		suite.AfterEach(func() {
+			if !suite.parallel {
+				suite.resetBlobStore(suite.T(), store)
+			}
+		})
+		suite.blobStore = store
+	}
+	if suite.lockSharedFixture(&suite.test.blobStoreLocked) {
+		t := suite.T()
+		t.Cleanup(func() {
+			suite.resetBlobStore(t, store)
+		})
+	}
+	return store
+}
+
+// resetBlobStore resets store after the test t, attaching its objects to
+// the artifacts of t if it failed.
+func (suite *Suite) resetBlobStore(t *testing.T, store *MemBlobStore) {
+	if t.Failed() {
+		dir := filepath.Join(suite.artifactDirFor(t), "blobs")
+		if err := store.Snapshot(dir); err != nil {
+			t.Logf("suite: failed to attach the blob store: %v", err)
+		} else {
+			t.Logf("suite: attached the blob store to %s", dir)
+		}
+	}
+	store.reset()
+}
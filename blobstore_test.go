@@ -0,0 +1,81 @@
+package suite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteBlobStoreTester struct {
+	Suite
+}
+
+func (s *SuiteBlobStoreTester) SetupSuite() {
+	s.Require().NoError(s.BlobStore().Put(s.Context(), "fixtures/users.csv", []byte("ada")))
+}
+
+func (s *SuiteBlobStoreTester) TestA() {
+	keys, err := s.BlobStore().List(s.Context(), "")
+	s.Require().NoError(err)
+	s.Equal([]string{"fixtures/users.csv"}, keys)
+	s.Require().NoError(s.BlobStore().Put(s.Context(), "exports/a.csv", []byte("a")))
+	s.Require().NoError(s.BlobStore().Put(s.Context(), "../escape", []byte("e")))
+	s.T().Fail()
+}
+
+func (s *SuiteBlobStoreTester) TestB() {
+	_, err := s.BlobStore().Get(s.Context(), "exports/a.csv")
+	s.Assert().ErrorIs(err, ErrBlobNotFound, "objects of other tests are removed")
+	data, err := s.BlobStore().Get(s.Context(), "fixtures/users.csv")
+	s.Require().NoError(err)
+	s.Equal("ada", string(data))
+}
+
+func TestBlobStore(t *testing.T) {
+	dir := t.TempDir()
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteBlobStoreTester), WithArtifactDir(dir))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestB")
+
+	blobs := filepath.Join(dir, "DetachedSuite", "TestA", "blobs")
+	assert.Contains(t, output, "suite: attached the blob store to "+blobs)
+	for name, content := range map[string]string{
+		"fixtures/users.csv": "ada",
+		"exports/a.csv":      "a",
+		`".._escape"`:        "e",
+	} {
+		data, err := os.ReadFile(filepath.Join(blobs, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+}
+
+func TestBlobStoreParallel(t *testing.T) {
+	// The tests using the store are serialized, so that TestB doesn't see
+	// the objects of TestA whichever runs first.
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteBlobStoreTester), WithArtifactDir(t.TempDir()), WithParallel())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/TestA")
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestB")
+}
+
+func TestMemBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemBlobStore()
+	var _ BlobStore = store
+	require.NoError(t, store.Put(ctx, "a/1", []byte("1")))
+	require.NoError(t, store.Put(ctx, "a/2", []byte("2")))
+	require.NoError(t, store.Put(ctx, "b/1", []byte("3")))
+	keys, err := store.List(ctx, "a/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/1", "a/2"}, keys)
+	require.NoError(t, store.Delete(ctx, "a/1"))
+	_, err = store.Get(ctx, "a/1")
+	assert.EqualError(t, err, "blob not found: a/1")
+}
@@ -0,0 +1,79 @@
+package suite
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testLog buffers the output of a test running in parallel with others,
+// logged through Suite.Log and Suite.Logf and by the runner, and flushes
+// it as a whole once the test has finished, so that the output of
+// parallel tests doesn't interleave line by line under go test -v. The
+// lines logged by the test keep the file and line they were logged at.
+type testLog struct {
+	mu    sync.Mutex
+	t     *testing.T
+	lines []string
+}
+
+// newTestLog returns the buffer of the output of the test t, or nil if the
+// tests of the suite don't run in parallel. The buffer is flushed when the
+// test finishes, and again once its cleanup functions have run, for the
+// lines they logged.
+func (r *suiteRun) newTestLog(t *testing.T) *testLog {
+	if !r.opts.parallel {
+		return nil
+	}
+	l := &testLog{t: t}
+	t.Cleanup(l.flush)
+	return l
+}
+
+// buffer adds line to the buffer if it is logged to the test of the
+// buffer, reporting whether it did. Lines logged to other tests, such as
+// subtests, aren't buffered. The line is attributed to the caller skip
+// frames above the caller of buffer, if skip isn't negative.
+func (l *testLog) buffer(t *testing.T, line string, skip int) bool {
+	if l == nil || t != l.t {
+		return false
+	}
+	if skip >= 0 {
+		if _, file, n, ok := runtime.Caller(skip + 2); ok {
+			line = fmt.Sprintf("%s:%d: %s", filepath.Base(file), n, line)
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+	return true
+}
+
+// flush logs the buffered output to the test.
+func (l *testLog) flush() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.lines) > 0 {
+		l.t.Log("\n" + strings.Join(l.lines, "\n"))
+		l.lines = nil
+	}
+}
+
+// logf logs a message of the runner to t, buffered if t is a test running
+// in parallel.
+func (r *suiteRun) logf(t *testing.T, format string, args ...interface{}) {
+	t.Helper()
+	line := r.redact(fmt.Sprintf(format, args...))
+	// The runner's lines aren't attributed, like its failures, which are
+	// reported at the call of Run.
+	if r.log.buffer(t, line, -1) {
+		return
+	}
+	t.Log(line)
+}
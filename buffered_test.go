@@ -0,0 +1,54 @@
+package suite
+
+import (
+	"flag"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteBufferedLogsTester struct {
+	Suite
+}
+
+func (s *SuiteBufferedLogsTester) logSlowly(name string) {
+	for i := 1; i <= 3; i++ {
+		s.Logf("%s line %d", name, i)
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (s *SuiteBufferedLogsTester) TestA() { s.logSlowly("a") }
+func (s *SuiteBufferedLogsTester) TestB() { s.logSlowly("b") }
+
+func TestBufferedLogs(t *testing.T) {
+	parallel := flag.Lookup("test.parallel").Value
+	defer parallel.Set(parallel.String())
+	parallel.Set("2")
+	verbose := flag.Lookup("test.v").Value
+	defer verbose.Set(verbose.String())
+	verbose.Set("true")
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteBufferedLogsTester), WithParallel())
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	for _, name := range []string{"a", "b"} {
+		// Every line is attributed to the call logging it.
+		contiguous := regexp.MustCompile(`buffered_test.go:18: ` + name + ` line 1\n\s+buffered_test.go:18: ` + name + ` line 2\n\s+buffered_test.go:18: ` + name + ` line 3\n`)
+		assert.Regexp(t, contiguous, output)
+	}
+}
+
+func TestBufferedLogsFlushedAfterCleanup(t *testing.T) {
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		r := &suiteRun{opts: &options{parallel: true}}
+		log := r.newTestLog(t)
+		t.Cleanup(func() { log.buffer(t, "logged by a cleanup", -1) })
+		t.Fail()
+	})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "logged by a cleanup")
+}
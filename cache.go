@@ -0,0 +1,115 @@
+package suite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var noCache = flag.Bool("testify.no-cache", false, "recreate the cached fixtures of testify suites instead of reusing them")
+
+// CachedFixture is a fixture persisted in a cache directory, so that later
+// runs of the tests reuse it instead of creating it again, e.g. a migrated
+// database dump or a downloaded dataset. It is declared at package level
+// and requested by suites, typically in SetupSuite:
+//
+//	var dataset = &suite.CachedFixture[string]{
+//		Name:   "dataset",
+//		Key:    datasetURL,
+//		Create: downloadDataset, // returns the path of the dataset
+//	}
+//
+//	func (s *ExampleTestSuite) SetupSuite() {
+//		s.dataset = dataset.Get(s)
+//	}
+//
+// A fixture is reused when it was cached with the same Name and Key, so
+// the key must change whenever the fixture would: it is e.g. the version
+// of the schema the database is migrated to. Fixtures are recreated, and
+// the cache refreshed, when the -testify.no-cache flag is set.
+type CachedFixture[F any] struct {
+	Name string
+	Key  string
+	// Create creates the fixture when it isn't cached.
+	Create func() (F, error)
+	// Encode and Decode serialize the fixture. It is serialized as JSON
+	// when they are nil. A fixture failing to decode is created again.
+	Encode func(F) ([]byte, error)
+	Decode func([]byte) (F, error)
+	// Dir is the cache directory, go-suite in the user's cache directory
+	// by default.
+	Dir string
+}
+
+// Get returns the fixture, from the cache or created and cached. A
+// failure to create or cache the fixture fails the current test of s.
+func (f *CachedFixture[F]) Get(s TestingSuite) F {
+	path, err := f.path()
+	if err != nil {
+		s.T().Fatalf("suite: failed to locate cached fixture %q: %v", f.Name, err)
+	}
+	if !*noCache {
+		if data, err := os.ReadFile(path); err == nil {
+			if value, err := f.decode(data); err == nil {
+				return value
+			}
+		}
+	}
+	value, err := f.Create()
+	if err != nil {
+		s.T().Fatalf("suite: failed to create cached fixture %q: %v", f.Name, err)
+	}
+	if err := f.store(path, value); err != nil {
+		s.T().Fatalf("suite: failed to cache fixture %q: %v", f.Name, err)
+	}
+	return value
+}
+
+// path returns the path of the cache file of the fixture.
+func (f *CachedFixture[F]) path() (string, error) {
+	dir := f.Dir
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(cache, "go-suite")
+	}
+	key := sha256.Sum256([]byte(f.Key))
+	return filepath.Join(dir, sanitizeName(f.Name), hex.EncodeToString(key[:16])), nil
+}
+
+func (f *CachedFixture[F]) decode(data []byte) (F, error) {
+	if f.Decode != nil {
+		return f.Decode(data)
+	}
+	var value F
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// store atomically writes value to the cache file at path.
+func (f *CachedFixture[F]) store(path string, value F) error {
+	var data []byte
+	var err error
+	if f.Encode != nil {
+		data, err = f.Encode(value)
+	} else {
+		data, err = json.Marshal(value)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,87 @@
+package suite
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cachedDataset struct {
+	Rows int
+}
+
+type SuiteCachedTester struct {
+	Suite
+	fixture *CachedFixture[cachedDataset]
+	dataset cachedDataset
+}
+
+func (s *SuiteCachedTester) SetupSuite() {
+	s.dataset = s.fixture.Get(s)
+}
+
+func (s *SuiteCachedTester) TestRows() {}
+
+func TestCachedFixture(t *testing.T) {
+	dir := t.TempDir()
+	created := 0
+	newFixture := func(key string) *CachedFixture[cachedDataset] {
+		return &CachedFixture[cachedDataset]{
+			Name: "dataset",
+			Key:  key,
+			Dir:  dir,
+			Create: func() (cachedDataset, error) {
+				created++
+				return cachedDataset{Rows: created}, nil
+			},
+		}
+	}
+	get := func(f *CachedFixture[cachedDataset]) cachedDataset {
+		s := &SuiteCachedTester{fixture: f}
+		Run(t, s)
+		return s.dataset
+	}
+
+	assert.Equal(t, cachedDataset{Rows: 1}, get(newFixture("v1")))
+	// Later runs reuse the fixture cached with the same key.
+	assert.Equal(t, cachedDataset{Rows: 1}, get(newFixture("v1")))
+	assert.Equal(t, 1, created)
+	assert.Equal(t, cachedDataset{Rows: 2}, get(newFixture("v2")))
+
+	defer func(old bool) { *noCache = old }(*noCache)
+	*noCache = true
+	assert.Equal(t, cachedDataset{Rows: 3}, get(newFixture("v1")))
+	*noCache = false
+	assert.Equal(t, cachedDataset{Rows: 3}, get(newFixture("v1")))
+}
+
+func TestCachedFixtureSerialization(t *testing.T) {
+	dir := t.TempDir()
+	decoded := 0
+	fixture := &CachedFixture[int]{
+		Name:   "answer",
+		Dir:    dir,
+		Create: func() (int, error) { return 42, nil },
+		Encode: func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil },
+		Decode: func(data []byte) (int, error) {
+			decoded++
+			return strconv.Atoi(string(data))
+		},
+	}
+	s := new(SuiteCachedTester)
+	assert.Equal(t, 42, fixture.Get(s))
+	assert.Equal(t, 42, fixture.Get(s))
+	assert.Equal(t, 1, decoded)
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteCachedTester{fixture: &CachedFixture[cachedDataset]{
+		Name:   "broken",
+		Dir:    dir,
+		Create: func() (cachedDataset, error) { return cachedDataset{}, errors.New("download failed") },
+	}})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, `suite: failed to create cached fixture "broken": download failed`)
+}
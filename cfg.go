@@ -0,0 +1,193 @@
+package suite
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cfgValues holds the settings given with the -testify.cfg flag.
+type cfgValues map[string]string
+
+func (v cfgValues) String() string {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + "=" + v[name]
+	}
+	return strings.Join(names, ",")
+}
+
+func (v cfgValues) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("%q is not of the form NAME=value", s)
+	}
+	v[name] = value
+	return nil
+}
+
+var cfgFlag = cfgValues{}
+
+func init() {
+	flag.Var(cfgFlag, "testify.cfg", "set the `NAME=value` setting of testify suites, overriding the environment variable NAME; can be repeated")
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// LoadConfig sets the exported fields of the struct pointed to by v that
+// are tagged with cfg. Suites don't have to call it: the fields of a suite
+// are loaded before SetupSuite, failing the suite if the configuration is
+// invalid, so that suites don't have to look up their settings in the
+// environment themselves:
+//
+//	type StoreSuite struct {
+//		suite.Suite
+//		DBURL   string        `cfg:"DB_URL,required"`
+//		Timeout time.Duration `cfg:"DB_TIMEOUT,default=5s"`
+//	}
+//
+// A setting is read from the -testify.cfg flag, e.g. -testify.cfg
+// DB_URL=postgres://localhost, or else from the environment variable of
+// the same name, or else from the default of the tag. Fields of type
+// string, bool, integers, floats, time.Duration, []string, split on commas,
+// and types implementing encoding.TextUnmarshaler are supported. The
+// returned error lists all the required settings that aren't set and all
+// the values that can't be converted.
+func LoadConfig(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can't load the configuration of %T, want a pointer to a struct", v)
+	}
+	return loadConfig(rv.Elem())
+}
+
+// loadConfig sets the fields of the struct v tagged with cfg.
+func loadConfig(v reflect.Value) error {
+	var problems []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, ok := field.Tag.Lookup("cfg")
+		if !ok {
+			continue
+		}
+		name, required, def, hasDefault, err := parseCfgTag(tag)
+		if err == nil && !field.IsExported() {
+			err = fmt.Errorf("settings can only be loaded into exported fields")
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("field %s: %v", field.Name, err))
+			continue
+		}
+		value, ok := cfgFlag[name]
+		if !ok {
+			value, ok = os.LookupEnv(name)
+		}
+		if !ok || value == "" {
+			value, ok = def, hasDefault
+		}
+		if !ok {
+			if required {
+				problems = append(problems, fmt.Sprintf("%s is required but not set (field %s)", name, field.Name))
+			}
+			continue
+		}
+		if err := setCfgField(v.Field(i), value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid value %q for field %s: %v", name, value, field.Name, err))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// parseCfgTag parses a cfg tag of the form NAME[,required][,default=value].
+// The default extends to the end of the tag, so that it can contain commas.
+func parseCfgTag(tag string) (name string, required bool, def string, hasDefault bool, err error) {
+	name, rest, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return "", false, "", false, fmt.Errorf("cfg tag %q has no name", tag)
+	}
+	for rest != "" {
+		if d, ok := strings.CutPrefix(rest, "default="); ok {
+			def, hasDefault = d, true
+			break
+		}
+		var option string
+		option, rest, _ = strings.Cut(rest, ",")
+		if option != "required" {
+			return "", false, "", false, fmt.Errorf("unknown cfg option %q", option)
+		}
+		required = true
+	}
+	return name, required, def, hasDefault, nil
+}
+
+// setCfgField sets f to the setting value converted to its type.
+func setCfgField(f reflect.Value, value string) error {
+	if f.CanAddr() && f.Addr().Type().Implements(textUnmarshalerType) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 0, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 0, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(x)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", f.Type())
+		}
+		parts := strings.Split(value, ",")
+		s := reflect.MakeSlice(f.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			s.Index(i).SetString(strings.TrimSpace(part))
+		}
+		f.Set(s)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package suite
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteCfgTester struct {
+	Suite
+	DBURL    string        `cfg:"SUITE_TEST_DB_URL,required"`
+	Timeout  time.Duration `cfg:"SUITE_TEST_TIMEOUT,default=5s"`
+	Workers  int           `cfg:"SUITE_TEST_WORKERS,default=2"`
+	Verbose  bool          `cfg:"SUITE_TEST_VERBOSE"`
+	Regions  []string      `cfg:"SUITE_TEST_REGIONS,default=eu,us"`
+	Addr     netip.Addr    `cfg:"SUITE_TEST_ADDR,required"`
+	setupURL string
+}
+
+func (s *SuiteCfgTester) SetupSuite() {
+	s.setupURL = s.DBURL
+}
+
+func (s *SuiteCfgTester) TestConfig() {}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	t.Setenv("SUITE_TEST_DB_URL", "postgres://localhost")
+	t.Setenv("SUITE_TEST_WORKERS", "8")
+	t.Setenv("SUITE_TEST_ADDR", "127.0.0.1")
+	s := new(SuiteCfgTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, "postgres://localhost", s.setupURL)
+	assert.Equal(t, 5*time.Second, s.Timeout)
+	assert.Equal(t, 8, s.Workers)
+	assert.False(t, s.Verbose)
+	assert.Equal(t, []string{"eu", "us"}, s.Regions)
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), s.Addr)
+}
+
+func TestConfigFromFlag(t *testing.T) {
+	t.Setenv("SUITE_TEST_DB_URL", "postgres://localhost")
+	t.Setenv("SUITE_TEST_ADDR", "127.0.0.1")
+	defer delete(cfgFlag, "SUITE_TEST_DB_URL")
+	require.NoError(t, cfgFlag.Set("SUITE_TEST_DB_URL=postgres://db:5432"))
+	s := new(SuiteCfgTester)
+	require.NoError(t, LoadConfig(s))
+	assert.Equal(t, "postgres://db:5432", s.DBURL)
+}
+
+func TestConfigInvalid(t *testing.T) {
+	t.Setenv("SUITE_TEST_DB_URL", "")
+	t.Setenv("SUITE_TEST_ADDR", "")
+	t.Setenv("SUITE_TEST_WORKERS", "many")
+	t.Setenv("SUITE_TEST_TIMEOUT", "5")
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteCfgTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: SuiteCfgTester: invalid configuration:\n"+
+		"        \tSUITE_TEST_DB_URL is required but not set (field DBURL)\n"+
+		"        \tSUITE_TEST_TIMEOUT: invalid value \"5\" for field Timeout: time: missing unit in duration \"5\"\n"+
+		"        \tSUITE_TEST_WORKERS: invalid value \"many\" for field Workers: strconv.ParseInt: parsing \"many\": invalid syntax\n"+
+		"        \tSUITE_TEST_ADDR is required but not set (field Addr)")
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestConfig")
+}
+
+func TestParseCfgTag(t *testing.T) {
+	name, required, def, hasDefault, err := parseCfgTag("HOSTS,required,default=a,b")
+	require.NoError(t, err)
+	assert.Equal(t, "HOSTS", name)
+	assert.True(t, required)
+	assert.Equal(t, "a,b", def)
+	assert.True(t, hasDefault)
+
+	_, _, _, _, err = parseCfgTag("HOSTS,mandatory")
+	assert.EqualError(t, err, `unknown cfg option "mandatory"`)
+	assert.Error(t, LoadConfig(SuiteCfgTester{}))
+}
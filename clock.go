@@ -0,0 +1,249 @@
+package suite
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time. Code under test that takes a Clock instead of
+// using the time package directly can be tested deterministically by
+// swapping in a FakeClock.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+}
+
+// RealClock is the Clock backed by the time package.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+// FakeClock is a Clock whose time only moves when it is advanced.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	// armed counts the calls of After, and armedChanged, if not nil, is
+	// closed by the next call, so that RunFor and BlockUntil can wait for
+	// them.
+	armed        int
+	armedChanged chan struct{}
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed on the clock since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns a channel that receives the clock's time once it has been
+// advanced by at least d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.armed++
+	if c.armedChanged != nil {
+		close(c.armedChanged)
+		c.armedChanged = nil
+	}
+	if d <= 0 {
+		w.c <- c.now
+		return w.c
+	}
+	c.waiters = append(c.waiters, w)
+	return w.c
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, waking up everything waiting for a
+// time up to the new time in order of their deadlines.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			remaining = append(remaining, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = remaining
+}
+
+// BlockUntil blocks until at least n goroutines are waiting on the clock,
+// e.g. so that a test starting a scheduler advances the clock only once
+// the scheduler waits for its first run.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			c.mu.Unlock()
+			return
+		}
+		changed := c.awaitArmed()
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// RunFor moves the clock forward by d one deadline at a time, so that
+// code waiting on the clock in a loop, such as a scheduler, runs as many
+// times as it would have in d, at the times it would have. At every
+// deadline, it wakes up what is waiting for it and waits for every woken
+// goroutine to wait on the clock again before moving on to the next
+// deadline, so it returns once the work of the last deadline is done.
+//
+// Only what waits on the clock when RunFor is called is run, so code
+// started by the test must be waited for with BlockUntil first, and every
+// goroutine woken by RunFor must wait on the clock again once done, or
+// RunFor blocks; code waiting on the clock only once is woken up with
+// Advance instead.
+func (c *FakeClock) RunFor(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		next, ok := c.nextDeadline()
+		if !ok || next.After(target) {
+			if target.After(c.now) {
+				c.now = target
+			}
+			c.mu.Unlock()
+			return
+		}
+		if next.After(c.now) {
+			c.now = next
+		}
+		remaining := c.waiters[:0]
+		woken := 0
+		for _, w := range c.waiters {
+			if w.deadline.After(c.now) {
+				remaining = append(remaining, w)
+				continue
+			}
+			w.c <- c.now
+			woken++
+		}
+		c.waiters = remaining
+		armed := c.armed
+		c.mu.Unlock()
+		c.rearmed(armed, woken)
+	}
+}
+
+// nextDeadline returns the earliest deadline waited for. c.mu must be
+// held.
+func (c *FakeClock) nextDeadline() (time.Time, bool) {
+	var next time.Time
+	for i, w := range c.waiters {
+		if i == 0 || w.deadline.Before(next) {
+			next = w.deadline
+		}
+	}
+	return next, len(c.waiters) > 0
+}
+
+// rearmed waits for woken goroutines to wait on the clock again since it
+// was armed times.
+func (c *FakeClock) rearmed(armed, woken int) {
+	for {
+		c.mu.Lock()
+		if c.armed-armed >= woken {
+			c.mu.Unlock()
+			return
+		}
+		changed := c.awaitArmed()
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// awaitArmed returns a channel closed by the next call of After. c.mu must
+// be held.
+func (c *FakeClock) awaitArmed() <-chan struct{} {
+	if c.armedChanged == nil {
+		c.armedChanged = make(chan struct{})
+	}
+	return c.armedChanged
+}
+
+// Clock returns the clock of the suite, RealClock unless SetClock was
+// called. The runner uses the same clock to time the suite's tests.
+func (suite *Suite) Clock() Clock {
+	if suite.clock == nil {
+		return RealClock
+	}
+	return suite.clock
+}
+
+// SetClock replaces the clock of the suite, e.g. with a FakeClock in
+// SetupSuite.
+func (suite *Suite) SetClock(clock Clock) {
+	suite.clock = clock
+}
+
+// AdvanceTime moves the FakeClock of the suite forward by d, running the
+// work scheduled on it in order, see FakeClock.RunFor, so that suites
+// testing schedulers don't have to sleep:
+//
+//	func (s *CronSuite) TestHourlyReport() {
+//		s.scheduler.Every(time.Hour, s.report)
+//		s.clock.BlockUntil(1)
+//		s.AdvanceTime(3 * time.Hour)
+//		s.Equal(3, s.reports())
+//	}
+//
+// The test fails if the clock of the suite isn't a FakeClock, see
+// SetClock.
+func (suite *Suite) AdvanceTime(d time.Duration) {
+	t := suite.T()
+	t.Helper()
+	clock, ok := suite.Clock().(*FakeClock)
+	if !ok {
+		t.Fatalf("suite: AdvanceTime needs a FakeClock, the clock of the suite is %T, see SetClock", suite.Clock())
+	}
+	clock.RunFor(d)
+}
+
+// clock returns the clock the runner times the suite with.
+func (r *suiteRun) clock() Clock {
+	if b, ok := r.suite.(suiteBase); ok {
+		return b.base().Clock()
+	}
+	return RealClock
+}
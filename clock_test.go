@@ -0,0 +1,148 @@
+package suite
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	short, long := clock.After(time.Second), clock.After(time.Minute)
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-short:
+		assert.Equal(t, start.Add(30*time.Second), now)
+	default:
+		t.Fatal("timer that expired was not fired")
+	}
+	select {
+	case <-long:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Second)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Second)
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	clock.Advance(time.Minute)
+	<-long
+	assert.True(t, clock.Since(start) > 90*time.Second)
+}
+
+type SuiteClockTester struct {
+	Suite
+
+	clock *FakeClock
+}
+
+func (s *SuiteClockTester) SetupSuite() {
+	s.clock = NewFakeClock(time.Now())
+	s.SetClock(s.clock)
+}
+
+func (s *SuiteClockTester) TestAdvance() {
+	s.clock.Advance(time.Hour)
+}
+
+func TestSuiteClock(t *testing.T) {
+	recorder := new(recordingReporter)
+	Run(t, new(SuiteClockTester), WithReporter(recorder))
+
+	require.Len(t, recorder.Finished, 1)
+	assert.Equal(t, time.Hour, recorder.Finished[0].Duration, "the runner times tests with the suite's clock")
+}
+
+func TestFakeClockRunFor(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	ticks := make(chan time.Time, 10)
+	go func() {
+		for i := 0; i < 5; i++ {
+			ticks <- <-clock.After(time.Minute)
+		}
+	}()
+	clock.BlockUntil(1)
+	clock.RunFor(3*time.Minute + 30*time.Second)
+	assert.Equal(t, start.Add(3*time.Minute+30*time.Second), clock.Now())
+	require.Len(t, ticks, 3)
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, start.Add(time.Duration(i)*time.Minute), <-ticks)
+	}
+}
+
+type SuiteAdvanceTimeTester struct {
+	Suite
+	clock *FakeClock
+	runs  []time.Time
+}
+
+func (s *SuiteAdvanceTimeTester) SetupTest() {
+	s.clock = NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.SetClock(s.clock)
+}
+
+func (s *SuiteAdvanceTimeTester) TestScheduler() {
+	var mu sync.Mutex
+	stop := make(chan struct{})
+	defer close(stop)
+	wait := s.clock.After(time.Hour)
+	go func() {
+		for {
+			var now time.Time
+			select {
+			case now = <-wait:
+			case <-stop:
+				return
+			}
+			mu.Lock()
+			s.runs = append(s.runs, now)
+			mu.Unlock()
+			wait = s.clock.After(time.Hour)
+		}
+	}()
+	s.AdvanceTime(3 * time.Hour)
+	mu.Lock()
+	defer mu.Unlock()
+	s.Equal(3, len(s.runs))
+}
+
+func TestSuiteAdvanceTime(t *testing.T) {
+	s := new(SuiteAdvanceTimeTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour), start.Add(3 * time.Hour)}, s.runs)
+}
+
+type SuiteAdvanceRealTimeTester struct {
+	Suite
+}
+
+func (s *SuiteAdvanceRealTimeTester) TestAdvance() {
+	s.AdvanceTime(time.Hour)
+}
+
+func TestSuiteAdvanceTimeNeedsFakeClock(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAdvanceRealTimeTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: AdvanceTime needs a FakeClock, the clock of the suite is suite.realClock, see SetClock")
+}
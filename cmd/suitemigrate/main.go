@@ -0,0 +1,460 @@
+// Command suitemigrate rewrites the plain tests of a package into the
+// tests of a suite, as a first step of migrating a legacy package to
+// suites.
+//
+// Usage:
+//
+//	suitemigrate [-name SuiteName] [-w] [dir]
+//
+// Each func TestX(t *testing.T) of the _test.go files in dir, which
+// defaults to the current directory, becomes a method TestX of the suite,
+// starting with t := s.T() so that its body keeps compiling. The
+// statements all tests start with, such as resetting global state, move
+// into SetupTest. Statements declaring variables and deferred calls stay
+// in the tests, while calls of t.Parallel are removed, as the tests of a
+// suite run one after the other unless the suite runs with
+// suite.WithParallel. The suite type and the test function running it are
+// added to the first file with tests.
+//
+// Only the tests of the package of that file are migrated: the tests of
+// an external test package, e.g. store_test next to package store, can't
+// be methods of a type of the other package and are left alone.
+//
+// The suite is named -name, by default after the package, e.g. StoreSuite
+// for package store. The rewritten files are printed, or written back
+// with -w.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const suiteImport = "github.com/mwitkow/go-suite"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "suitemigrate: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("suitemigrate", flag.ContinueOnError)
+	name := flags.String("name", "", "name of the suite, by default derived from the package name")
+	write := flags.Bool("w", false, "write the rewritten files instead of printing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	dir := "."
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		dir = flags.Arg(0)
+	default:
+		return errors.New("expected at most one directory")
+	}
+
+	files, err := migrate(dir, *name)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if *write {
+			if err := os.WriteFile(f.path, f.src, 0644); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Printf("// %s\n%s\n", f.path, f.src)
+	}
+	return nil
+}
+
+// source is a test file being rewritten.
+type source struct {
+	path  string
+	src   []byte
+	file  *ast.File
+	edits []edit
+}
+
+// edit replaces the bytes from start to end of a source.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// test is a test function being turned into a method of the suite.
+type test struct {
+	source *source
+	decl   *ast.FuncDecl
+	// param is the name of the *testing.T parameter.
+	param string
+}
+
+// migrate returns the rewritten test files of the package in dir, with
+// their suite named name.
+func migrate(dir, name string) ([]*source, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	fset := token.NewFileSet()
+	// packages groups the files by package, as the tests of the package
+	// and of its external test package can share a directory.
+	packages := make(map[string][]*source)
+	pkg := ""
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		s := &source{path: path, src: src, file: file}
+		packages[file.Name.Name] = append(packages[file.Name.Name], s)
+		if pkg == "" && len(testFuncs(file)) > 0 {
+			pkg = file.Name.Name
+		}
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("no tests to migrate in %s", dir)
+	}
+
+	// Only the package of the first file with tests is migrated, as the
+	// suite type is added to that file.
+	var sources []*source
+	var tests []*test
+	funcs := make(map[string]bool)
+	for _, s := range packages[pkg] {
+		for _, decl := range s.file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+				funcs[fn.Name.Name] = true
+			}
+		}
+		found := testFuncs(s.file)
+		for _, fn := range found {
+			param, _ := testParam(fn)
+			tests = append(tests, &test{source: s, decl: fn, param: param})
+		}
+		if len(found) > 0 {
+			sources = append(sources, s)
+		}
+	}
+
+	if name == "" {
+		name = suiteName(pkg)
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return nil, fmt.Errorf("suite name %q is not an exported identifier", name)
+	}
+	if funcs["Test"+name] {
+		return nil, fmt.Errorf("the package already has a function Test%s", name)
+	}
+
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	shared := sharedSetup(tests, offset)
+	recv := receiver(tests)
+	for _, t := range tests {
+		body := t.decl.Body.List[shared:]
+		var kept []ast.Stmt
+		for i, stmt := range body {
+			if !isCall(stmt, t.param, "Parallel") {
+				kept = append(kept, stmt)
+				continue
+			}
+			end := offset(t.decl.Body.Rbrace)
+			if i+1 < len(body) {
+				end = offset(body[i+1].Pos())
+			}
+			t.source.edits = append(t.source.edits, edit{offset(stmt.Pos()), end, ""})
+		}
+		header := fmt.Sprintf("func (%s *%s) %s() {", recv, name, t.decl.Name.Name)
+		if uses(kept, t.param) {
+			header += fmt.Sprintf("\n%s := %s.T()", t.param, recv)
+		}
+		t.source.edits = append(t.source.edits, edit{offset(t.decl.Type.Func), offset(t.decl.Body.Lbrace) + 1, header})
+		if shared > 0 {
+			end := offset(t.decl.Body.Rbrace)
+			if len(body) > 0 {
+				end = offset(body[0].Pos())
+			}
+			t.source.edits = append(t.source.edits, edit{offset(t.decl.Body.List[0].Pos()), end, ""})
+		}
+	}
+
+	first := sources[0]
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\n// %s holds the tests migrated from plain test functions.\ntype %s struct {\n\tsuite.Suite\n}\n", name, name)
+	fmt.Fprintf(&b, "\nfunc Test%s(t *testing.T) {\n\tsuite.Run(t, new(%s))\n}\n", name, name)
+	if shared > 0 {
+		setup := tests[0].decl.Body.List[:shared]
+		fmt.Fprintf(&b, "\n// SetupTest runs the setup shared by all tests.\nfunc (%s *%s) SetupTest() {\n", recv, name)
+		if uses(setup, tests[0].param) {
+			fmt.Fprintf(&b, "%s := %s.T()\n", tests[0].param, recv)
+		}
+		b.Write(tests[0].source.src[offset(setup[0].Pos()):offset(setup[shared-1].End())])
+		b.WriteString("\n}\n")
+	}
+	first.edits = append(first.edits, edit{len(first.src), len(first.src), b.String()})
+	first.edits = append(first.edits, addImport(first, suiteImport, offset))
+
+	for _, s := range sources {
+		if s != first && !usesTesting(s, tests, offset) {
+			s.edits = append(s.edits, removeImport(s, "testing", offset))
+		}
+		src, err := s.apply()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", s.path, err)
+		}
+		s.src = src
+	}
+	return sources, nil
+}
+
+// testFuncs returns the test functions of file.
+func testFuncs(file *ast.File) []*ast.FuncDecl {
+	var tests []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil {
+			continue
+		}
+		if _, ok := testParam(fn); ok {
+			tests = append(tests, fn)
+		}
+	}
+	return tests
+}
+
+// testParam returns the name of the *testing.T parameter of fn if it is a
+// test function.
+func testParam(fn *ast.FuncDecl) (string, bool) {
+	name := fn.Name.Name
+	if !strings.HasPrefix(name, "Test") || name == "TestMain" || fn.Type.Results != nil || fn.Type.TypeParams != nil {
+		return "", false
+	}
+	if rest := strings.TrimPrefix(name, "Test"); rest != "" && unicode.IsLower([]rune(rest)[0]) {
+		return "", false
+	}
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 {
+		return "", false
+	}
+	star, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "T" {
+		return "", false
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "testing" {
+		return "", false
+	}
+	return params[0].Names[0].Name, true
+}
+
+// sharedSetup returns the number of statements all tests start with that
+// can move into SetupTest.
+func sharedSetup(tests []*test, offset func(token.Pos) int) int {
+	if len(tests) < 2 {
+		return 0
+	}
+	text := func(t *test, stmt ast.Stmt) string {
+		return string(t.source.src[offset(stmt.Pos()):offset(stmt.End())])
+	}
+	n := 0
+	for {
+		first := tests[0]
+		if n >= len(first.decl.Body.List) || !movable(first.decl.Body.List[n], first.param) {
+			return n
+		}
+		want := text(first, first.decl.Body.List[n])
+		for _, t := range tests[1:] {
+			if t.param != first.param || n >= len(t.decl.Body.List) || text(t, t.decl.Body.List[n]) != want {
+				return n
+			}
+		}
+		n++
+	}
+}
+
+// movable reports whether stmt can move from the start of a test into
+// SetupTest: it must neither declare variables used by the rest of the
+// test nor depend on running in the test itself.
+func movable(stmt ast.Stmt, param string) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt:
+		if _, ok := stmt.X.(*ast.CallExpr); !ok {
+			return false
+		}
+		return !isCall(stmt, param, "Parallel") && !isCall(stmt, param, "Run")
+	case *ast.AssignStmt:
+		return stmt.Tok == token.ASSIGN
+	}
+	return false
+}
+
+// isCall reports whether stmt is a call of the method name of the variable
+// param, e.g. t.Parallel().
+func isCall(stmt ast.Stmt, param, name string) bool {
+	expr, ok := stmt.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == param
+}
+
+// receiver returns the name of the receiver of the suite's methods, which
+// must not clash with the identifiers of the tests.
+func receiver(tests []*test) string {
+	for _, name := range []string{"s", "ts", "suite_"} {
+		clash := false
+		for _, t := range tests {
+			if t.param == name || uses(t.decl.Body.List, name) {
+				clash = true
+				break
+			}
+		}
+		if !clash {
+			return name
+		}
+	}
+	return "suite_"
+}
+
+// uses reports whether stmts refer to the identifier name.
+func uses(stmts []ast.Stmt, name string) bool {
+	found := false
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && id.Name == name {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// usesTesting reports whether s still refers to package testing once the
+// signatures of its tests are rewritten.
+func usesTesting(s *source, tests []*test, offset func(token.Pos) int) bool {
+	signatures := make(map[ast.Node]bool)
+	for _, t := range tests {
+		if t.source == s {
+			signatures[t.decl.Type] = true
+		}
+	}
+	found := false
+	for _, decl := range s.file.Decls {
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if signatures[n] {
+				return false
+			}
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == "testing" {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// addImport returns the edit importing path into s.
+func addImport(s *source, path string, offset func(token.Pos) int) edit {
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if !gen.Lparen.IsValid() {
+			spec := s.src[offset(gen.Specs[0].Pos()):offset(gen.End())]
+			return edit{offset(gen.Pos()), offset(gen.End()), fmt.Sprintf("import (\n%s\n%s\n)", spec, strconv.Quote(path))}
+		}
+		at := offset(gen.Rparen)
+		return edit{at, at, "\t" + strconv.Quote(path) + "\n"}
+	}
+	at := offset(s.file.Name.End())
+	return edit{at, at, "\n\nimport " + strconv.Quote(path)}
+}
+
+// removeImport returns the edit removing the import of path from s.
+func removeImport(s *source, path string, offset func(token.Pos) int) edit {
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if spec.Path.Value != strconv.Quote(path) {
+				continue
+			}
+			if !gen.Lparen.IsValid() {
+				return edit{offset(gen.Pos()), offset(gen.End()), ""}
+			}
+			return edit{offset(spec.Pos()), offset(spec.End()), ""}
+		}
+	}
+	return edit{}
+}
+
+// apply applies the edits of s and formats the result.
+func (s *source) apply() ([]byte, error) {
+	edits := append([]edit(nil), s.edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	src := append([]byte(nil), s.src...)
+	for _, e := range edits {
+		src = append(src[:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+	return format.Source(bytes.TrimSpace(src))
+}
+
+// suiteName returns the default name of the suite of package pkg, e.g.
+// StoreSuite for store.
+func suiteName(pkg string) string {
+	pkg = strings.TrimSuffix(pkg, "_test")
+	var b strings.Builder
+	upper := true
+	for _, r := range pkg {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "Suite"
+}
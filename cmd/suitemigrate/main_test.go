@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const storeTest = `package store
+
+import (
+	"testing"
+)
+
+// TestGet gets a value.
+func TestGet(t *testing.T) {
+	reset()
+	counter = 0
+	s := New()
+	if s.Get("a") != "" {
+		t.Fatal("unexpected value")
+	}
+}
+
+func TestPut(t *testing.T) {
+	reset()
+	counter = 0
+	t.Parallel()
+	New().Put("a", "b")
+}
+
+func TestMain(m *testing.M) {}
+`
+
+const listTest = `package store
+
+import "testing"
+
+func TestList(t *testing.T) {
+	reset()
+	counter = 0
+}
+`
+
+const externalTest = `package store_test
+
+import "testing"
+
+func TestExternal(t *testing.T) {
+	reset()
+	counter = 0
+}
+`
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store_test.go"), []byte(storeTest), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "list_test.go"), []byte(listTest), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store_external_test.go"), []byte(externalTest), 0644))
+	require.NoError(t, run([]string{"-w", dir}))
+
+	// The tests of the external test package are left alone.
+	external, err := os.ReadFile(filepath.Join(dir, "store_external_test.go"))
+	require.NoError(t, err)
+	assert.Equal(t, externalTest, string(external))
+
+	list, err := os.ReadFile(filepath.Join(dir, "list_test.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package store
+
+import (
+	"github.com/mwitkow/go-suite"
+	"testing"
+)
+
+func (ts *StoreSuite) TestList() {
+}
+
+// StoreSuite holds the tests migrated from plain test functions.
+type StoreSuite struct {
+	suite.Suite
+}
+
+func TestStoreSuite(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
+
+// SetupTest runs the setup shared by all tests.
+func (ts *StoreSuite) SetupTest() {
+	reset()
+	counter = 0
+}
+`, string(list))
+
+	store, err := os.ReadFile(filepath.Join(dir, "store_test.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package store
+
+import (
+	"testing"
+)
+
+// TestGet gets a value.
+func (ts *StoreSuite) TestGet() {
+	t := ts.T()
+	s := New()
+	if s.Get("a") != "" {
+		t.Fatal("unexpected value")
+	}
+}
+
+func (ts *StoreSuite) TestPut() {
+	New().Put("a", "b")
+}
+
+func TestMain(m *testing.M) {}
+`, string(store))
+}
+
+func TestMigrateWithoutTests(t *testing.T) {
+	err := run([]string{t.TempDir()})
+	assert.ErrorContains(t, err, "no tests to migrate")
+}
@@ -0,0 +1,243 @@
+// Command suitenew creates the file of a new suite: the suite type, its
+// hooks, the test function running it and an example test.
+//
+// Usage:
+//
+//	suitenew [-dir dir] [-package name] [-fixtures db,server] [-o file] SuiteName
+//
+// The package defaults to the package of the Go files in dir, or to the
+// name of dir. Each of the comma-separated fixtures gets a setup and a
+// teardown stub called from SetupSuite and TearDownSuite. The file is named
+// after the suite, e.g. user_store_suite_test.go for UserStoreSuite, and
+// is never overwritten.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "suitenew: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("suitenew", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory to create the suite in")
+	pkg := flags.String("package", "", "package of the suite, by default that of the Go files in -dir")
+	fixtures := flags.String("fixtures", "", "comma-separated names of the fixtures to create stubs for")
+	out := flags.String("o", "", "name of the file to create, by default derived from the suite name")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: suitenew [flags] SuiteName")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected exactly one suite name")
+	}
+
+	s := spec{Name: flags.Arg(0), Package: *pkg}
+	if s.Package == "" {
+		var err error
+		if s.Package, err = packageName(*dir); err != nil {
+			return err
+		}
+	}
+	for _, name := range strings.Split(*fixtures, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			s.Fixtures = append(s.Fixtures, name)
+		}
+	}
+	src, err := generate(s)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		*out = fileName(s.Name)
+	}
+	path := filepath.Join(*dir, *out)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+// spec describes the suite to generate.
+type spec struct {
+	Package  string
+	Name     string
+	Fixtures []string
+}
+
+// Fixture returns the name of a fixture as used in the names of its stubs,
+// e.g. UserCache for user_cache.
+func (spec) Fixture(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' || r == '-' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var suiteTemplate = template.Must(template.New("suite").Parse(`package {{.Package}}
+
+import (
+	"testing"
+
+	"github.com/mwitkow/go-suite"
+)
+
+// {{.Name}} TODO: describe what the suite tests.
+type {{.Name}} struct {
+	suite.Suite
+}
+
+func Test{{.Name}}(t *testing.T) {
+	suite.Run(t, new({{.Name}}))
+}
+
+// SetupSuite runs once, before the tests of the suite.
+func (s *{{.Name}}) SetupSuite() {
+{{- range .Fixtures}}
+	s.setup{{$.Fixture .}}()
+{{- end}}
+}
+
+// TearDownSuite runs once, after the tests of the suite.
+func (s *{{.Name}}) TearDownSuite() {
+{{- range .Fixtures}}
+	s.tearDown{{$.Fixture .}}()
+{{- end}}
+}
+
+// SetupTest runs before each test.
+func (s *{{.Name}}) SetupTest() {}
+
+// TearDownTest runs after each test.
+func (s *{{.Name}}) TearDownTest() {}
+{{range .Fixtures}}
+// setup{{$.Fixture .}} creates the {{.}} fixture of the suite.
+func (s *{{$.Name}}) setup{{$.Fixture .}}() {
+	// TODO: create the fixture, e.g. with suite.SharedFixture.
+}
+
+// tearDown{{$.Fixture .}} releases the {{.}} fixture of the suite.
+func (s *{{$.Name}}) tearDown{{$.Fixture .}}() {
+	// TODO: release the fixture.
+}
+{{end}}
+func (s *{{.Name}}) TestExample() {
+	s.Equal(2, 1+1)
+}
+`))
+
+// generate returns the formatted source of the suite.
+func generate(s spec) ([]byte, error) {
+	if !token.IsIdentifier(s.Name) || !token.IsExported(s.Name) {
+		return nil, fmt.Errorf("suite name %q is not an exported identifier", s.Name)
+	}
+	if !token.IsIdentifier(s.Package) {
+		return nil, fmt.Errorf("package name %q is not an identifier", s.Package)
+	}
+	for _, name := range s.Fixtures {
+		if !token.IsIdentifier(s.Fixture(name)) {
+			return nil, fmt.Errorf("fixture name %q is not an identifier", name)
+		}
+	}
+	var b bytes.Buffer
+	if err := suiteTemplate.Execute(&b, s); err != nil {
+		return nil, err
+	}
+	return format.Source(b.Bytes())
+}
+
+// packageName returns the name of the package of the Go files in dir, or
+// the name of dir if it has none.
+func packageName(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	external := ""
+	for _, file := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		if name := f.Name.Name; !strings.HasSuffix(name, "_test") {
+			return name, nil
+		} else if external == "" {
+			external = name
+		}
+	}
+	if external != "" {
+		return external, nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, filepath.Base(abs))
+	return name, nil
+}
+
+// fileName returns the name of the file of the suite, e.g.
+// user_store_suite_test.go for UserStoreSuite.
+func fileName(suiteName string) string {
+	var b strings.Builder
+	runes := []rune(suiteName)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if !strings.HasSuffix(name, "suite") {
+		name += "_suite"
+	}
+	return name + "_test.go"
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(spec{Package: "store", Name: "UserStoreSuite", Fixtures: []string{"db", "user_cache"}})
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "", src, 0)
+	require.NoError(t, err, string(src))
+
+	assert.Contains(t, string(src), "package store\n")
+	assert.Contains(t, string(src), "type UserStoreSuite struct {\n\tsuite.Suite\n}")
+	assert.Contains(t, string(src), "suite.Run(t, new(UserStoreSuite))")
+	assert.Contains(t, string(src), "func (s *UserStoreSuite) SetupSuite() {\n\ts.setupDb()\n\ts.setupUserCache()\n}")
+	assert.Contains(t, string(src), "func (s *UserStoreSuite) tearDownUserCache() {")
+	assert.Contains(t, string(src), "func (s *UserStoreSuite) TestExample() {")
+
+	_, err = generate(spec{Package: "store", Name: "userStoreSuite"})
+	assert.EqualError(t, err, `suite name "userStoreSuite" is not an exported identifier`)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "store.go"), []byte("package store\n"), 0644))
+	require.NoError(t, run([]string{"-dir", dir, "HTTPStoreSuite"}))
+
+	src, err := os.ReadFile(filepath.Join(dir, "http_store_suite_test.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "package store\n")
+
+	assert.Error(t, run([]string{"-dir", dir, "HTTPStoreSuite"}), "existing files must not be overwritten")
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "user_store_suite_test.go", fileName("UserStoreSuite"))
+	assert.Equal(t, "http_store_suite_test.go", fileName("HTTPStore"))
+}
@@ -0,0 +1,83 @@
+// Command suitereap deletes the external resources registered with
+// Suite.Reap that were left behind by test runs that crashed, or that kept
+// them for inspection, once their TTL has expired.
+//
+// Usage:
+//
+//	suitereap [-dir dir] [-all] [-n] [-timeout 1m]
+//
+// The resources are read from dir, which defaults to the directory used by
+// tests when neither suite.WithReaperDir nor -testify.reaper-dir is set.
+// Each orphaned resource is deleted by running the command it was
+// registered with. Resources registered without a command, or recorded by
+// another user, are only reported. With -all, resources whose TTL hasn't expired are deleted too;
+// with -n, they are only listed.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	suite "github.com/mwitkow/go-suite"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "suitereap: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer, now time.Time) error {
+	flags := flag.NewFlagSet("suitereap", flag.ContinueOnError)
+	dir := flags.String("dir", suite.DefaultReaperDir(), "directory in which the resources are recorded")
+	all := flags.Bool("all", false, "also delete the resources whose TTL hasn't expired")
+	dryRun := flags.Bool("n", false, "list the resources to delete without deleting them")
+	timeout := flags.Duration("timeout", time.Minute, "timeout of each delete command")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: suitereap [flags]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return errors.New("unexpected arguments")
+	}
+
+	entries, err := suite.ReaperEntries(*dir)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for _, entry := range entries {
+		if !*all && !entry.Expired(now) {
+			continue
+		}
+		desc := fmt.Sprintf("%s (created by %s on %s at %s)", entry.Name, entry.Test, entry.Host, entry.Created.Format(time.RFC3339))
+		if *dryRun {
+			fmt.Fprintf(out, "would delete %s: %s\n", desc, strings.Join(entry.Command, " "))
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		err := entry.Reap(ctx)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(out, "%s: %v\n", desc, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "deleted %s\n", desc)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d resources", failed)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	suite "github.com/mwitkow/go-suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reapedSuite struct {
+	suite.Suite
+	marker string
+}
+
+func (s *reapedSuite) TestCreate() {
+	require.NoError(s.T(), os.WriteFile(s.marker, nil, 0644))
+	s.Reap(suite.Resource{
+		Name:    "marker",
+		Delete:  func(ctx context.Context) error { return nil },
+		Command: []string{"rm", s.marker},
+		TTL:     time.Minute,
+	})
+	s.Reap(suite.Resource{
+		Name:   "manual",
+		Delete: func(ctx context.Context) error { return nil },
+	})
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(t.TempDir(), "marker")
+	// Keeping the resources leaves them behind like a crashed run.
+	t.Run("Reaped", func(t *testing.T) {
+		suite.Run(t, &reapedSuite{marker: marker}, suite.WithReaperDir(dir), suite.WithRetention(suite.RetainAlways))
+	})
+	entries, err := suite.ReaperEntries(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"-dir", dir}, &out, time.Now()))
+	assert.Empty(t, out.String())
+
+	out.Reset()
+	require.NoError(t, run([]string{"-dir", dir, "-n"}, &out, time.Now().Add(2*time.Hour)))
+	assert.Contains(t, out.String(), "would delete marker (created by TestRun/Reaped/TestCreate on ")
+	assert.FileExists(t, marker)
+
+	out.Reset()
+	err = run([]string{"-dir", dir}, &out, time.Now().Add(2*time.Hour))
+	assert.EqualError(t, err, "failed to delete 1 resources")
+	assert.Contains(t, out.String(), "deleted marker (created by TestRun/Reaped/TestCreate")
+	assert.Contains(t, out.String(), "manual has no delete command, delete it manually")
+	assert.NoFileExists(t, marker)
+	entries, err = suite.ReaperEntries(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "manual", entries[0].Name)
+}
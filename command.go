@@ -0,0 +1,350 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// commandStubVariable is the environment variable naming the file from
+// which the test binary, started in place of a stubbed command, reads the
+// output and exit code of the command.
+const commandStubVariable = "GO_SUITE_COMMAND_STUB"
+
+func init() {
+	if path := os.Getenv(commandStubVariable); path != "" {
+		os.Exit(runCommandStub(path))
+	}
+}
+
+// runCommandStub writes the output of the stub in the file path and
+// returns its exit code.
+func runCommandStub(path string) int {
+	data, err := os.ReadFile(path)
+	var stub CommandStub
+	if err == nil {
+		err = json.Unmarshal(data, &stub)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "suite: failed to load command stub: %v\n", err)
+		return 127
+	}
+	os.Stdout.WriteString(stub.Stdout)
+	os.Stderr.WriteString(stub.Stderr)
+	return stub.ExitCode
+}
+
+// CommandStub is the output and exit code of a stubbed command, see
+// Suite.StubCommand.
+type CommandStub struct {
+	// Args restricts the stub to the invocations of the command with these
+	// arguments, if not nil.
+	Args     []string `json:"-"`
+	Stdout   string   `json:"stdout"`
+	Stderr   string   `json:"stderr"`
+	ExitCode int      `json:"exit_code"`
+}
+
+// matches reports whether the stub applies to the invocation with args.
+func (s CommandStub) matches(args []string) bool {
+	if s.Args == nil {
+		return true
+	}
+	if len(s.Args) != len(args) {
+		return false
+	}
+	for i := range args {
+		if s.Args[i] != args[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// commandStubsKey is the key of the *commandStubs of a test in its
+// context.
+type commandStubsKey struct{}
+
+// commandStubs holds the commands stubbed by a test, or by its suite,
+// whose stubs are inherited by its tests.
+type commandStubs struct {
+	mu     sync.Mutex
+	parent *commandStubs
+	stubs  map[string][]CommandStub
+}
+
+func (s *commandStubs) add(name string, stub CommandStub) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stubs == nil {
+		s.stubs = make(map[string][]CommandStub)
+	}
+	s.stubs[name] = append(s.stubs[name], stub)
+}
+
+// lookup returns the stub of the invocation of the command name with
+// args, the latest stub registered taking precedence.
+func (s *commandStubs) lookup(name string, args []string) (CommandStub, bool) {
+	if s == nil {
+		return CommandStub{}, false
+	}
+	s.mu.Lock()
+	stubs := s.stubs[name]
+	s.mu.Unlock()
+	for i := len(stubs) - 1; i >= 0; i-- {
+		if stubs[i].matches(args) {
+			return stubs[i], true
+		}
+	}
+	return s.parent.lookup(name, args)
+}
+
+// StubCommand stubs the command name for the rest of the current test, or
+// for all the tests of the suite when called in SetupSuite: the commands
+// created with Command run the test binary in its place, which writes the
+// output of the stub and exits with its exit code, so that the code under
+// test sees a real process:
+//
+//	s.StubCommand("git", suite.CommandStub{Args: []string{"rev-parse", "HEAD"}, Stdout: "4f2a9c1\n"})
+//	s.StubCommand("kubectl", suite.CommandStub{Stderr: "connection refused\n", ExitCode: 1})
+//
+// The stubs registered last take precedence.
+func (suite *Suite) StubCommand(name string, stub CommandStub) {
+	stubs, ok := ContextValue(suite.Context(), commandStubsKey{}).(*commandStubs)
+	if !ok {
+		suite.T().Fatalf("suite: StubCommand must be called in a suite run by Run")
+	}
+	stubs.add(name, stub)
+}
+
+// Cmd is an external command run by a test, see Suite.Command. Its Start,
+// Wait, Run, Output and CombinedOutput methods record it; the other
+// methods of the embedded *exec.Cmd don't.
+type Cmd struct {
+	*exec.Cmd
+	suite *Suite
+	t     *testing.T
+	// stub is the stub run in place of the command, if any.
+	stub *CommandStub
+	// stdout and stderr capture the output of the command.
+	stdout, stderr bytes.Buffer
+	start          time.Time
+}
+
+// Command returns the external command name with args, to be run in the
+// current test, for suites testing code that shells out, e.g. by passing
+// Command to the code under test in place of exec.Command. The command is
+// killed when the test finishes and gets the environment of the test, see
+// Env.
+//
+// Every command run is logged to the test with its exit code and, if the
+// test fails, attached to its artifacts with its output, see ArtifactDir.
+// Commands stubbed with StubCommand aren't run.
+func (suite *Suite) Command(name string, args ...string) *Cmd {
+	t := suite.T()
+	t.Helper()
+	cmd := &Cmd{Cmd: exec.CommandContext(suite.Context(), name, args...), suite: suite, t: t}
+	cmd.Env = suite.env().Environ()
+	stubs, _ := ContextValue(suite.Context(), commandStubsKey{}).(*commandStubs)
+	if stub, ok := stubs.lookup(name, args); ok {
+		cmd.stub = &stub
+	}
+	return cmd
+}
+
+// Start starts the command, or the stub of the command.
+func (c *Cmd) Start() error {
+	if c.stub != nil {
+		if err := c.startStub(); err != nil {
+			return err
+		}
+	}
+	if c.Stdout == nil {
+		c.Stdout = &c.stdout
+	} else {
+		c.Stdout = io.MultiWriter(c.Stdout, &c.stdout)
+	}
+	if c.Stderr == nil {
+		c.Stderr = &c.stderr
+	} else {
+		c.Stderr = io.MultiWriter(c.Stderr, &c.stderr)
+	}
+	c.start = time.Now()
+	if err := c.Cmd.Start(); err != nil {
+		c.record(err)
+		return err
+	}
+	return nil
+}
+
+// startStub makes the command run the test binary, writing the output of
+// the stub.
+func (c *Cmd) startStub() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("suite: can't stub %s: %v", c.Args[0], err)
+	}
+	data, err := json.Marshal(c.stub)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.suite.tempDirFor(c.t), "command-stub-*.json")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	c.Path = exe
+	c.Err = nil
+	c.Env = append(c.Environ(), commandStubVariable+"="+f.Name())
+	return nil
+}
+
+// Wait waits for the command to exit and records it.
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.record(err)
+	return err
+}
+
+// Run starts the command and waits for it to exit.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Output runs the command and returns its standard output, like the
+// Output method of exec.Cmd.
+func (c *Cmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	captureStderr := c.Stderr == nil
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = c.stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command and returns its standard output and
+// standard error combined, like the CombinedOutput method of exec.Cmd.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	if c.Stderr != nil {
+		return nil, errors.New("exec: Stderr already set")
+	}
+	var b syncBuffer
+	c.Stdout = &b
+	c.Stderr = &b
+	err := c.Run()
+	return b.Bytes(), err
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes, to which the
+// standard output and standard error of a command are combined.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+// record logs the command to the test and keeps it to attach it to the
+// artifacts of the test if it fails.
+func (c *Cmd) record(err error) {
+	t := c.t
+	line := strings.Join(c.Args, " ")
+	if c.stub != nil {
+		line += " (stubbed)"
+	}
+	var status string
+	switch {
+	case c.ProcessState != nil:
+		status = fmt.Sprintf("exited with %d", c.ProcessState.ExitCode())
+	case err != nil:
+		status = fmt.Sprintf("failed: %v", err)
+	}
+	elapsed := time.Since(c.start).Round(time.Millisecond)
+	t.Log(c.suite.redact(fmt.Sprintf("suite: $ %s: %s (%v)", line, status, elapsed)))
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ %s\n%s (%v)\n", line, status, elapsed)
+	if c.stdout.Len() > 0 {
+		fmt.Fprintf(&b, "--- stdout ---\n%s\n", strings.TrimSuffix(c.stdout.String(), "\n"))
+	}
+	if c.stderr.Len() > 0 {
+		fmt.Fprintf(&b, "--- stderr ---\n%s\n", strings.TrimSuffix(c.stderr.String(), "\n"))
+	}
+	if c.suite.commandLog != nil {
+		c.suite.commandLog.keep(t, c.suite, b.String())
+	}
+}
+
+// commandLog keeps the commands run by the tests of a suite until they
+// finish.
+type commandLog struct {
+	mu   sync.Mutex
+	runs map[*testing.T][]string
+}
+
+// keep keeps the command run by the test t, registering the attachment of
+// its commands on its first one.
+func (l *commandLog) keep(t *testing.T, suite *Suite, run string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	runs, ok := l.runs[t]
+	if !ok {
+		t.Cleanup(func() {
+			l.mu.Lock()
+			runs := l.runs[t]
+			delete(l.runs, t)
+			l.mu.Unlock()
+			if t.Failed() {
+				attachCommands(t, suite, runs)
+			}
+		})
+	}
+	l.runs[t] = append(runs, run)
+}
+
+// attachCommands writes the commands run by the failed test t to its
+// artifacts.
+func attachCommands(t *testing.T, suite *Suite, runs []string) {
+	path := filepath.Join(suite.artifactDirFor(t), "commands.txt")
+	if err := os.WriteFile(path, []byte(suite.redact(strings.Join(runs, "\n"))), 0644); err != nil {
+		t.Logf("suite: failed to attach commands: %v", err)
+		return
+	}
+	t.Logf("suite: attached %d commands to %s", len(runs), path)
+}
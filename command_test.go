@@ -0,0 +1,94 @@
+package suite
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteCommandTester struct {
+	Suite
+	fail bool
+}
+
+func (s *SuiteCommandTester) SetupSuite() {
+	s.StubCommand("no-such-command-git", CommandStub{Stdout: "main\n"})
+}
+
+func (s *SuiteCommandTester) TestStubs() {
+	s.StubCommand("no-such-command-git", CommandStub{Args: []string{"rev-parse", "HEAD"}, Stdout: "4f2a9c1\n"})
+	s.StubCommand("no-such-command-kubectl", CommandStub{Stderr: "connection refused\n", ExitCode: 3})
+
+	out, err := s.Command("no-such-command-git", "rev-parse", "HEAD").Output()
+	s.Require().NoError(err)
+	s.Equal("4f2a9c1\n", string(out))
+
+	out, err = s.Command("no-such-command-git", "branch", "--show-current").Output()
+	s.Require().NoError(err)
+	s.Equal("main\n", string(out))
+
+	_, err = s.Command("no-such-command-kubectl", "get", "pods").Output()
+	var exitErr *exec.ExitError
+	s.Require().True(errors.As(err, &exitErr), "%v", err)
+	s.Equal(3, exitErr.ExitCode())
+	s.Equal("connection refused\n", string(exitErr.Stderr))
+
+	if s.fail {
+		s.T().Error("failing to attach the commands")
+	}
+}
+
+func (s *SuiteCommandTester) TestSuiteStub() {
+	out, err := s.Command("no-such-command-git", "rev-parse", "HEAD").CombinedOutput()
+	s.Require().NoError(err)
+	s.Equal("main\n", string(out))
+}
+
+func TestCommand(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteCommandTester))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+}
+
+func TestCommandAttachedToFailedTests(t *testing.T) {
+	dir := t.TempDir()
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteCommandTester{fail: true}, WithArtifactDir(dir))
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Contains(t, output, "suite: $ no-such-command-kubectl get pods (stubbed): exited with 3")
+	data, err := os.ReadFile(filepath.Join(dir, "DetachedSuite", "TestStubs", "commands.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "$ no-such-command-git rev-parse HEAD (stubbed)\nexited with 0")
+	assert.Contains(t, string(data), "--- stdout ---\n4f2a9c1\n")
+	assert.Contains(t, string(data), "--- stderr ---\nconnection refused\n")
+	_, err = os.Stat(filepath.Join(dir, "DetachedSuite", "TestSuiteStub", "commands.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCommandEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	s := new(SuiteCommandEnvTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, "staging\n", s.out)
+}
+
+type SuiteCommandEnvTester struct {
+	Suite
+	out string
+}
+
+func (s *SuiteCommandEnvTester) TestEnv() {
+	s.Env().Set("SUITE_TEST_COMMAND_STAGE", "staging")
+	out, err := s.Command("sh", "-c", "echo $SUITE_TEST_COMMAND_STAGE").Output()
+	s.Require().NoError(err)
+	s.out = string(out)
+}
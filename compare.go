@@ -0,0 +1,108 @@
+package suite
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// comparison holds the comparers and formatters registered on a suite.
+type comparison struct {
+	comparers  map[reflect.Type]cmp.Option
+	formatters map[reflect.Type]cmp.Option
+}
+
+// RegisterComparer registers a function of the form func(a, b T) bool
+// that the assertion helpers of the suite use to compare values of type T,
+// wherever they appear within the compared values. A typical use is
+// registering a tolerance for time.Time in SetupSuite:
+//
+//	s.RegisterComparer(func(a, b time.Time) bool {
+//		return a.Sub(b).Abs() < time.Second
+//	})
+func (suite *Suite) RegisterComparer(comparer interface{}) {
+	typ := signatureOf(comparer, "RegisterComparer", "func(T, T) bool", func(f reflect.Type) bool {
+		return f.NumIn() == 2 && f.In(0) == f.In(1) && f.NumOut() == 1 && f.Out(0).Kind() == reflect.Bool
+	})
+	if suite.comparison.comparers == nil {
+		suite.comparison.comparers = make(map[reflect.Type]cmp.Option)
+	}
+	suite.comparison.comparers[typ] = cmp.Comparer(comparer)
+}
+
+// RegisterFormatter registers a function of the form func(T) string that
+// is used to show values of type T in the diffs reported by failed
+// assertions of the suite, e.g. to show decimals in their usual notation
+// instead of their internal representation.
+func (suite *Suite) RegisterFormatter(formatter interface{}) {
+	typ := signatureOf(formatter, "RegisterFormatter", "func(T) string", func(f reflect.Type) bool {
+		return f.NumIn() == 1 && f.NumOut() == 1 && f.Out(0).Kind() == reflect.String
+	})
+	if suite.comparison.formatters == nil {
+		suite.comparison.formatters = make(map[reflect.Type]cmp.Option)
+	}
+	suite.comparison.formatters[typ] = cmp.Transformer("format", formatter)
+}
+
+// signatureOf checks that fn is a function matching the described
+// signature and returns the type of its first argument.
+func signatureOf(fn interface{}, method, signature string, ok func(reflect.Type) bool) reflect.Type {
+	f := reflect.TypeOf(fn)
+	if f == nil || f.Kind() != reflect.Func || !ok(f) {
+		panic(fmt.Sprintf("suite: %s needs a function of the form %s, got %T", method, signature, fn))
+	}
+	return f.In(0)
+}
+
+// Equal asserts that two values are equal. Values of types for which a
+// comparer was registered are compared with it, other values are compared
+// like reflect.DeepEqual does, except that Equal methods of the form
+// (T) Equal(T) bool are used.
+func (suite *Suite) Equal(expected, actual interface{}, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	if suite.comparison.equal(expected, actual) {
+		return true
+	}
+	suite.errorf(msgAndArgs, "Not equal (-expected +actual):\n%s", suite.comparison.diff(expected, actual))
+	return false
+}
+
+// NotEqual asserts that two values are not equal, as defined by Equal.
+func (suite *Suite) NotEqual(expected, actual interface{}, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	if !suite.comparison.equal(expected, actual) {
+		return true
+	}
+	suite.errorf(msgAndArgs, "Should not be equal: %#v", actual)
+	return false
+}
+
+// allFieldsExported makes cmp compare unexported fields as well, as
+// reflect.DeepEqual does.
+var allFieldsExported = cmp.Exporter(func(reflect.Type) bool { return true })
+
+func (c *comparison) equal(expected, actual interface{}) bool {
+	opts := []cmp.Option{allFieldsExported}
+	for _, comparer := range c.comparers {
+		opts = append(opts, comparer)
+	}
+	return cmp.Equal(expected, actual, opts...)
+}
+
+func (c *comparison) diff(expected, actual interface{}) string {
+	opts := []cmp.Option{allFieldsExported}
+	for _, formatter := range c.formatters {
+		opts = append(opts, formatter)
+	}
+	for typ, comparer := range c.comparers {
+		// A comparer for a type that also has a formatter would make the
+		// options ambiguous, the formatted values are diffed instead.
+		if _, ok := c.formatters[typ]; !ok {
+			opts = append(opts, comparer)
+		}
+	}
+	return cmp.Diff(expected, actual, opts...)
+}
@@ -0,0 +1,58 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cents int64
+
+type invoice struct {
+	Issued time.Time
+	Total  cents
+}
+
+type SuiteCompareTester struct {
+	Suite
+}
+
+func (s *SuiteCompareTester) SetupSuite() {
+	s.RegisterComparer(func(a, b time.Time) bool {
+		d := a.Sub(b)
+		return d < time.Second && d > -time.Second
+	})
+	s.RegisterFormatter(func(c cents) string {
+		return fmt.Sprintf("$%d.%02d", c/100, c%100)
+	})
+}
+
+func (s *SuiteCompareTester) TestWithinTolerance() {
+	now := time.Now()
+	s.Equal(invoice{Issued: now, Total: 100}, invoice{Issued: now.Add(time.Millisecond), Total: 100})
+	s.NotEqual(invoice{Issued: now}, invoice{Issued: now.Add(time.Minute)})
+}
+
+func (s *SuiteCompareTester) TestFormattedDiff() {
+	s.Equal(invoice{Total: 1050}, invoice{Total: 1999})
+}
+
+func TestSuiteComparers(t *testing.T) {
+	recorder := new(recordingReporter)
+	_, output, err := runDetachedSuiteWithOutputCapture(new(SuiteCompareTester), WithReporter(recorder))
+	require.NoError(t, err)
+	require.Len(t, recorder.Finished, 2)
+	assert.Equal(t, StatusFailed, recorder.Finished[0].Status)
+	assert.Equal(t, StatusPassed, recorder.Finished[1].Status, output)
+	assert.Contains(t, output, `"$10.50"`)
+	assert.Contains(t, output, `"$19.99"`)
+}
+
+func TestRegisterComparerRejectsInvalidSignatures(t *testing.T) {
+	s := new(Suite)
+	assert.Panics(t, func() { s.RegisterComparer(func(a, b int) string { return "" }) })
+	assert.Panics(t, func() { s.RegisterFormatter("not a function") })
+}
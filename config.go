@@ -0,0 +1,75 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Configured is a Suite with an immutable configuration of type C, supplied
+// when the suite is run by RunWith or RunWithConfigs. It separates what is
+// fixed for the whole run, such as the address of a backend, from the
+// mutable state of the suite's tests:
+//
+//	type StoreSuite struct {
+//		suite.Configured[StoreConfig]
+//		store *Store
+//	}
+//
+//	func (s *StoreSuite) SetupTest() {
+//		s.store = Connect(s.Config().Addr)
+//	}
+type Configured[C any] struct {
+	Suite
+	config C
+}
+
+// Config returns the configuration the suite was run with.
+func (s *Configured[C]) Config() C {
+	return s.config
+}
+
+func (s *Configured[C]) setConfig(config C) {
+	s.config = config
+}
+
+// configurable is implemented by suites embedding Configured[C].
+type configurable[C any] interface {
+	setConfig(config C)
+}
+
+// configure sets the configuration of suite.
+func configure[C any](suite TestingSuite, config C) error {
+	c, ok := suite.(configurable[C])
+	if !ok {
+		var zero C
+		return fmt.Errorf("%T does not embed suite.Configured[%T]", suite, zero)
+	}
+	c.setConfig(config)
+	return nil
+}
+
+// RunWith runs suite like Run, with config as the configuration returned by
+// the Config method of the suite, which must embed Configured[C].
+func RunWith[C any](t *testing.T, suite TestingSuite, config C, opts ...Option) {
+	t.Helper()
+	if err := configure(suite, config); err != nil {
+		t.Fatalf("suite: %v", err)
+	}
+	Run(t, suite, opts...)
+}
+
+// RunWithConfigs runs the suite created by newSuite once per configuration,
+// like RunAgainst runs a suite against several backends, and logs the
+// conformance matrix of all configurations.
+func RunWithConfigs[C any](t *testing.T, newSuite func() TestingSuite, configs map[string]C, opts ...Option) {
+	t.Helper()
+	var zero C
+	if err := configure(newSuite(), zero); err != nil {
+		t.Fatalf("suite: %v", err)
+	}
+	RunAgainst(t, func(config C) TestingSuite {
+		suite := newSuite()
+		configure(suite, config)
+		return suite
+	}, configs, opts...)
+}
@@ -0,0 +1,49 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storeConfig struct {
+	Addr     string
+	Replicas int
+}
+
+type SuiteConfigTester struct {
+	Configured[storeConfig]
+	seen *[]storeConfig
+}
+
+func (s *SuiteConfigTester) SetupSuite() {
+	*s.seen = append(*s.seen, s.Config())
+}
+
+func (s *SuiteConfigTester) TestReplicas() {
+	assert.NotZero(s.T(), s.Config().Replicas)
+}
+
+func TestRunWith(t *testing.T) {
+	var seen []storeConfig
+	RunWith(t, &SuiteConfigTester{seen: &seen}, storeConfig{Addr: "localhost:1234", Replicas: 3})
+	assert.Equal(t, []storeConfig{{Addr: "localhost:1234", Replicas: 3}}, seen)
+}
+
+func TestRunWithConfigs(t *testing.T) {
+	var seen []storeConfig
+	RunWithConfigs(t, func() TestingSuite { return &SuiteConfigTester{seen: &seen} }, map[string]storeConfig{
+		"single":     {Addr: "a", Replicas: 1},
+		"replicated": {Addr: "b", Replicas: 3},
+	})
+	assert.Equal(t, []storeConfig{{Addr: "b", Replicas: 3}, {Addr: "a", Replicas: 1}}, seen)
+}
+
+func TestRunWithUnconfigurable(t *testing.T) {
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		RunWith(t, new(SuiteIndependentTester), storeConfig{})
+	})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: *suite.SuiteIndependentTester does not embed suite.Configured[suite.storeConfig]")
+}
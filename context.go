@@ -0,0 +1,174 @@
+package suite
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Context returns the context of the current test. It is canceled when
+// the test has finished, and expires at the deadline of the test binary
+// (see -test.timeout). Outside of tests, e.g. in SetupSuite, it is the
+// context of the whole suite.
+func (suite *Suite) Context() context.Context {
+	if suite.ctx == nil {
+		return context.Background()
+	}
+	return suite.ctx
+}
+
+// newContext returns a context derived from parent that expires at the
+// deadline of t, if it has one.
+func newContext(parent context.Context, t *testing.T) (context.Context, context.CancelFunc) {
+	if deadline, ok := t.Deadline(); ok {
+		return context.WithDeadline(parent, deadline)
+	}
+	return context.WithCancel(parent)
+}
+
+// Keys of the values attached to the context of every test, see
+// ContextValue.
+const (
+	// TestNameKey is the key of the full name of the test, a string.
+	TestNameKey contextKey = "test-name"
+	// ArtifactDirKey is the key of the artifact directory of the test, a
+	// string, created when first looked up, see Suite.ArtifactDir. It is
+	// only attached to the context of suites embedding Suite.
+	ArtifactDirKey contextKey = "artifact-dir"
+	// LoggerKey is the key of a *slog.Logger writing to the log of the
+	// test.
+	LoggerKey contextKey = "logger"
+)
+
+// contextKey is the type of the keys of the values attached by the suite.
+type contextKey string
+
+func (k contextKey) String() string {
+	return "suite." + string(k)
+}
+
+// testValuesKey is the context key of the values of a test.
+type testValuesKey struct{}
+
+// testValues holds the values attached to the context of a test, or of a
+// suite, whose values are inherited by its tests.
+type testValues struct {
+	mu     sync.Mutex
+	parent *testValues
+	values map[interface{}]interface{}
+	// lazy computes values when they are first looked up.
+	lazy map[interface{}]func() interface{}
+}
+
+func (v *testValues) set(key, value interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.values == nil {
+		v.values = make(map[interface{}]interface{})
+	}
+	v.values[key] = value
+	delete(v.lazy, key)
+}
+
+func (v *testValues) setLazy(key interface{}, value func() interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.lazy == nil {
+		v.lazy = make(map[interface{}]func() interface{})
+	}
+	v.lazy[key] = value
+}
+
+func (v *testValues) get(key interface{}) (interface{}, bool) {
+	v.mu.Lock()
+	value, ok := v.values[key]
+	lazy := v.lazy[key]
+	v.mu.Unlock()
+	if ok {
+		return value, true
+	}
+	if lazy != nil {
+		value = lazy()
+		v.set(key, value)
+		return value, true
+	}
+	if v.parent != nil {
+		return v.parent.get(key)
+	}
+	return nil, false
+}
+
+// withTestValues returns ctx carrying new values inheriting from those of
+// ctx, if any.
+func withTestValues(ctx context.Context) (context.Context, *testValues) {
+	parent, _ := ctx.Value(testValuesKey{}).(*testValues)
+	values := &testValues{parent: parent}
+	return context.WithValue(ctx, testValuesKey{}, values), values
+}
+
+// ContextValue returns the value attached to key in the context of a test,
+// or of its suite, by SetContextValue or by the suite itself, such as
+// TestNameKey, or nil if there is none. It lets libraries under test
+// emit test-aware diagnostics from the context they are passed, without
+// global state:
+//
+//	if logger, ok := suite.ContextValue(ctx, suite.LoggerKey).(*slog.Logger); ok {
+//		logger.Debug("retrying", "attempt", attempt)
+//	}
+//
+// Values attached with context.WithValue take precedence.
+func ContextValue(ctx context.Context, key interface{}) interface{} {
+	if value := ctx.Value(key); value != nil {
+		return value
+	}
+	if values, ok := ctx.Value(testValuesKey{}).(*testValues); ok {
+		value, _ := values.get(key)
+		return value
+	}
+	return nil
+}
+
+// SetContextValue attaches value to key in the context of the current
+// test, returned by Context, for the rest of the test, e.g. so that a
+// fixture can expose itself to the libraries under test. Called outside of
+// tests, e.g. in SetupSuite, it attaches the value to the context of the
+// suite, inherited by all of its tests. See ContextValue.
+func (suite *Suite) SetContextValue(key, value interface{}) {
+	values, ok := suite.Context().Value(testValuesKey{}).(*testValues)
+	if !ok {
+		suite.T().Fatalf("suite: SetContextValue must be called in a suite run by Run")
+	}
+	values.set(key, value)
+}
+
+// attachTestValues attaches the values of the test t to ctx.
+func (r *suiteRun) attachTestValues(ctx context.Context, t *testing.T) context.Context {
+	env := &Env{parent: EnvFrom(ctx)}
+	parentStubs, _ := ContextValue(ctx, commandStubsKey{}).(*commandStubs)
+	ctx, values := withTestValues(ctx)
+	values.set(TestNameKey, t.Name())
+	values.set(EnvKey, env)
+	values.set(commandStubsKey{}, &commandStubs{parent: parentStubs})
+	values.setLazy(LoggerKey, func() interface{} {
+		return slog.New(slog.NewTextHandler(&testLogWriter{r: r, t: t}, nil))
+	})
+	if b, ok := r.suite.(suiteBase); ok {
+		values.setLazy(ArtifactDirKey, func() interface{} {
+			return b.base().artifactDirFor(t)
+		})
+	}
+	return ctx
+}
+
+// testLogWriter writes lines to the log of a test.
+type testLogWriter struct {
+	r *suiteRun
+	t *testing.T
+}
+
+func (w *testLogWriter) Write(p []byte) (int, error) {
+	w.r.logf(w.t, "%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
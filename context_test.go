@@ -0,0 +1,68 @@
+package suite
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dbKey struct{}
+
+type SuiteContextValuesTester struct {
+	Suite
+	observed map[string]interface{}
+}
+
+func (s *SuiteContextValuesTester) SetupSuite() {
+	s.SetContextValue(dbKey{}, "suite-db")
+}
+
+func (s *SuiteContextValuesTester) TestA_Values() {
+	ctx := s.Context()
+	s.observed = map[string]interface{}{
+		"name": ContextValue(ctx, TestNameKey),
+		"db":   ContextValue(ctx, dbKey{}),
+		"dir":  ContextValue(ctx, ArtifactDirKey),
+	}
+	s.SetContextValue(dbKey{}, "test-db")
+	s.observed["overridden"] = ContextValue(ctx, dbKey{})
+	s.observed["derived"] = ContextValue(context.WithValue(ctx, dbKey{}, "explicit"), dbKey{})
+	logger, _ := ContextValue(ctx, LoggerKey).(*slog.Logger)
+	s.observed["logger"] = logger != nil
+}
+
+func (s *SuiteContextValuesTester) TestB_Isolated() {
+	s.observed["next"] = ContextValue(s.Context(), dbKey{})
+}
+
+func TestContextValues(t *testing.T) {
+	s := new(SuiteContextValuesTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithArtifactDir(t.TempDir()))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, "DetachedSuite/TestA_Values", s.observed["name"])
+	assert.Equal(t, "suite-db", s.observed["db"])
+	assert.DirExists(t, s.observed["dir"].(string))
+	assert.Equal(t, "test-db", s.observed["overridden"])
+	assert.Equal(t, "explicit", s.observed["derived"])
+	assert.Equal(t, "suite-db", s.observed["next"])
+	assert.Equal(t, true, s.observed["logger"])
+	assert.Nil(t, ContextValue(context.Background(), TestNameKey))
+}
+
+type SuiteContextLoggerTester struct{ Suite }
+
+func (s *SuiteContextLoggerTester) TestLog() {
+	ContextValue(s.Context(), LoggerKey).(*slog.Logger).Info("hello from the library", "attempt", 2)
+	s.T().Fail()
+}
+
+func TestContextLogger(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteContextLoggerTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Regexp(t, `level=INFO msg="hello from the library" attempt=2`, output)
+}
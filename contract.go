@@ -0,0 +1,132 @@
+package suite
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// RunContract runs a contract suite: a suite exported by a library to
+// verify implementations of one of its interfaces, e.g. a storagetest.Suite
+// with a Factory field creating the storage implementation under test.
+//
+// Exported fields of the contract tagged `contract:"required"` must be set,
+// otherwise the test fails before any hook has run:
+//
+//	type Suite struct {
+//		suite.Suite
+//		Factory func() storage.Store `contract:"required"`
+//	}
+//
+// A downstream package either runs a configured contract directly:
+//
+//	suite.RunContract(t, &storagetest.Suite{Factory: newFileStore})
+//
+// or embeds it into its own suite to add tests and hooks. In that case Go's
+// rules for promoting methods apply to the hooks as well: a hook declared by
+// the downstream suite replaces the contract's hook of the same name, which
+// then only runs if it is called explicitly, e.g. s.Suite.SetupTest(),
+// unless the downstream suite embeds ExtendsHooks. RunContract logs every
+// hook of a contract that is replaced this way. The
+// downstream suite must not embed Suite a second time next to the contract,
+// as only the shallower one would receive the *testing.T; RunContract fails
+// if it does.
+func RunContract(t *testing.T, contract TestingSuite, opts ...Option) {
+	t.Helper()
+	value := reflect.ValueOf(contract)
+	if missing := missingRequiredFields(value.Elem(), value.Elem().Type().Name()); len(missing) > 0 {
+		t.Fatalf("suite: contract %T is missing required fields: %s", contract, strings.Join(missing, ", "))
+	}
+	if paths := suitePaths(value.Type(), ""); len(paths) > 1 {
+		t.Fatalf("suite: %T embeds suite.Suite more than once (%s), only the shallowest receives the *testing.T", contract, strings.Join(paths, ", "))
+	}
+	for _, override := range hookOverrides(value.Type()) {
+		t.Logf("suite: %s", override)
+	}
+	Run(t, contract, opts...)
+}
+
+// missingRequiredFields returns the paths of the fields tagged
+// `contract:"required"` that are not set in v or its embedded structs.
+func missingRequiredFields(v reflect.Value, path string) []string {
+	var missing []string
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.Tag.Get("contract") == "required" && value.IsZero() {
+			missing = append(missing, path+"."+field.Name)
+		}
+		if !field.Anonymous {
+			continue
+		}
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Struct {
+			missing = append(missing, missingRequiredFields(value, path+"."+field.Name)...)
+		}
+	}
+	return missing
+}
+
+var suiteType = reflect.TypeOf(Suite{})
+
+// suitePaths returns the paths through embedded fields at which typ
+// embeds Suite.
+func suitePaths(typ reflect.Type, path string) []string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var paths []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType == suiteType {
+			paths = append(paths, strings.TrimPrefix(path+"."+field.Name, "."))
+			continue
+		}
+		paths = append(paths, suitePaths(fieldType, path+"."+field.Name)...)
+	}
+	return paths
+}
+
+// hookOverrides describes the hooks declared by embedded fields of typ that
+// are replaced by hooks declared closer to typ.
+func hookOverrides(typ reflect.Type) []string {
+	var overrides []string
+	for _, name := range hookNames {
+		owner := hookOwner(typ, name)
+		if owner == nil || extendsHooks(owner) {
+			continue
+		}
+		for _, shadowed := range shadowedHooks(owner, typ, name) {
+			overrides = append(overrides, fmt.Sprintf("%v.%s replaces %v.%s, which only runs if called explicitly", owner.Elem(), name, shadowed.Elem(), name))
+		}
+	}
+	return overrides
+}
+
+// shadowedHooks returns the types embedded into typ, at any depth, that
+// declare the hook name but are not its owner.
+func shadowedHooks(owner, typ reflect.Type, name string) []reflect.Type {
+	var shadowed []reflect.Type
+	for _, field := range embeddedFields(typ) {
+		if field != owner && declaresMethod(field, name) {
+			shadowed = append(shadowed, field)
+		}
+		shadowed = append(shadowed, shadowedHooks(owner, field, name)...)
+	}
+	return shadowed
+}
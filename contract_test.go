@@ -0,0 +1,74 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// CounterContract is an example of a contract suite verifying
+// implementations of a counter.
+type CounterContract struct {
+	Suite
+
+	Factory func() *int `contract:"required"`
+
+	counter *int
+}
+
+func (s *CounterContract) SetupTest() {
+	s.counter = s.Factory()
+}
+
+func (s *CounterContract) TestIncrement() {
+	*s.counter++
+	assert.Equal(s.T(), 1, *s.counter)
+}
+
+// ExtendedCounterSuite embeds the contract and extends its SetupTest.
+type ExtendedCounterSuite struct {
+	CounterContract
+
+	SetupTestRunCount int
+}
+
+func (s *ExtendedCounterSuite) SetupTest() {
+	s.CounterContract.SetupTest()
+	s.SetupTestRunCount++
+}
+
+// DoublyEmbeddedSuite embeds Suite next to the contract.
+type DoublyEmbeddedSuite struct {
+	Suite
+	CounterContract
+}
+
+func TestRunContract(t *testing.T) {
+	newCounter := func() *int { return new(int) }
+	RunContract(t, &CounterContract{Factory: newCounter})
+
+	extended := &ExtendedCounterSuite{CounterContract: CounterContract{Factory: newCounter}}
+	_, output, err := runDetachedContractWithOutputCapture(extended)
+	require.NoError(t, err)
+	assert.Equal(t, 1, extended.SetupTestRunCount)
+	if testing.Verbose() {
+		assert.Contains(t, output, "suite.ExtendedCounterSuite.SetupTest replaces suite.CounterContract.SetupTest")
+	}
+
+	ok, output, err := runDetachedContractWithOutputCapture(new(CounterContract))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "contract *suite.CounterContract is missing required fields: CounterContract.Factory")
+
+	ok, output, err = runDetachedContractWithOutputCapture(&DoublyEmbeddedSuite{CounterContract: CounterContract{Factory: newCounter}})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "embeds suite.Suite more than once (Suite, CounterContract.Suite)")
+}
+
+func runDetachedContractWithOutputCapture(s TestingSuite) (bool, string, error) {
+	return runDetachedWithOutputCapture(func(t *testing.T) {
+		RunContract(t, s)
+	})
+}
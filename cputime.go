@@ -0,0 +1,45 @@
+package suite
+
+import "time"
+
+// CPUTime is the CPU time the process spent while a test ran. Compared to
+// the wall time of the test, it tells tests doing expensive work from tests
+// that merely sleep or wait.
+type CPUTime struct {
+	// User is the time spent executing user code.
+	User time.Duration
+	// System is the time spent in the kernel on behalf of the process.
+	System time.Duration
+}
+
+// Total returns the user and system CPU time.
+func (c CPUTime) Total() time.Duration {
+	return c.User + c.System
+}
+
+// trackCPU starts measuring the CPU time of a test. The returned function
+// stores it in cpu. It is left zero on systems where the CPU time of the
+// process isn't available.
+//
+// The CPU time is that of the whole process, so it includes the work of
+// other goroutines running at the same time. It isn't measured for tests
+// running in parallel, as it would mostly be the work of the other tests.
+func (r *suiteRun) trackCPU(cpu *CPUTime) func() {
+	if r.opts.parallel {
+		return func() {}
+	}
+	before, ok := processCPUTime()
+	if !ok {
+		return func() {}
+	}
+	return func() {
+		after, ok := processCPUTime()
+		if !ok {
+			return
+		}
+		*cpu = CPUTime{
+			User:   after.User - before.User,
+			System: after.System - before.System,
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package suite
+
+// processCPUTime returns the CPU time spent by the process so far, which
+// isn't available on this system.
+func processCPUTime() (CPUTime, bool) {
+	return CPUTime{}, false
+}
@@ -0,0 +1,59 @@
+package suite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteCPUTimeTester struct {
+	Suite
+}
+
+func (s *SuiteCPUTimeTester) TestBusy() {
+	n := 0
+	for deadline := time.Now().Add(100 * time.Millisecond); time.Now().Before(deadline); {
+		n++
+	}
+	s.NotEqual(0, n)
+}
+
+func (s *SuiteCPUTimeTester) TestSleep() {
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestCPUTime(t *testing.T) {
+	if _, ok := processCPUTime(); !ok {
+		t.Skip("the CPU time of the process isn't available on this system")
+	}
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteCPUTimeTester), WithReporter(recorder))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	require.Len(t, recorder.Finished, 2)
+	busy, sleep := recorder.Finished[0], recorder.Finished[1]
+	assert.GreaterOrEqual(t, busy.CPU.Total(), 50*time.Millisecond, "busy: %+v", busy.CPU)
+	assert.Greater(t, busy.CPU.Total(), sleep.CPU.Total())
+	assert.GreaterOrEqual(t, sleep.Duration, 100*time.Millisecond)
+
+	// The CPU time of tests running in parallel isn't measured.
+	recorder = new(recordingReporter)
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteCPUTimeTester), WithReporter(recorder), WithParallel())
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	for _, result := range recorder.Finished {
+		assert.Zero(t, result.CPU, result.Name)
+	}
+}
+
+func TestJSONReporterCPUTime(t *testing.T) {
+	doc := newJSONSuite(SuiteResult{Tests: []TestResult{{
+		Name: "TestBusy",
+		CPU:  CPUTime{User: 1500 * time.Microsecond, System: 500 * time.Microsecond},
+	}}})
+	require.Len(t, doc.Tests, 1)
+	assert.Equal(t, 1.5, doc.Tests[0].UserMS)
+	assert.Equal(t, 0.5, doc.Tests[0].SystemMS)
+}
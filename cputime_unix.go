@@ -0,0 +1,20 @@
+//go:build unix
+
+package suite
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the CPU time spent by the process so far.
+func processCPUTime() (CPUTime, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return CPUTime{}, false
+	}
+	return CPUTime{
+		User:   time.Duration(usage.Utime.Nano()),
+		System: time.Duration(usage.Stime.Nano()),
+	}, true
+}
@@ -0,0 +1,146 @@
+package suite
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var diagnostics = flag.Bool("testify.diagnostics", false, "write a diagnostics bundle for every failed testify test to the artifact directory")
+
+// WithDiagnostics writes a diagnostics bundle for every failed test of the
+// suite to the test's artifact directory (see Suite.ArtifactDir), so that
+// failures on CI can be debugged without running the test again. The
+// bundle, diagnostics.txt, contains the seed and configuration of the run,
+// the environment variables, with the values of secrets redacted, the
+// stack traces of all goroutines and the output written through the
+// standard logger while the test ran.
+//
+// Without an artifact directory no bundle is written. Diagnostics are also
+// enabled for all suites by the -testify.diagnostics flag.
+func WithDiagnostics() Option {
+	return func(o *options) {
+		o.diagnostics = true
+	}
+}
+
+// captureLogs tees the output of the standard logger into a buffer until
+// the returned function is called, if diagnostics are enabled. Tests
+// running in parallel don't capture logs, as the standard logger is global.
+func (r *suiteRun) captureLogs() (*bytes.Buffer, func()) {
+	logs := &bytes.Buffer{}
+	if !r.opts.diagnostics || r.opts.parallel {
+		return logs, func() {}
+	}
+	output := log.Writer()
+	log.SetOutput(io.MultiWriter(output, logs))
+	return logs, func() { log.SetOutput(output) }
+}
+
+// writeDiagnostics writes the diagnostics bundle of the test if it failed.
+func (r *suiteRun) writeDiagnostics(t *testing.T, logs *bytes.Buffer) {
+	if !r.opts.diagnostics || !t.Failed() {
+		return
+	}
+	if r.opts.artifactDir == "" {
+		r.infof(t, "no artifact directory set, not writing diagnostics")
+		return
+	}
+	dir := testArtifactDir(r.opts.artifactDir, t.Name())
+	path := filepath.Join(dir, "diagnostics.txt")
+	err := os.MkdirAll(dir, 0755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(r.redact(string(r.diagnostics(t, logs.String())))), 0644)
+	}
+	if err != nil {
+		r.logf(t, "suite: failed to write diagnostics: %v", err)
+		return
+	}
+	r.logf(t, "suite: wrote diagnostics to %s", path)
+}
+
+// diagnostics returns the diagnostics bundle of the test.
+func (r *suiteRun) diagnostics(t *testing.T, logs string) []byte {
+	var b bytes.Buffer
+	section := func(title string) {
+		fmt.Fprintf(&b, "\n== %s ==\n\n", title)
+	}
+	fmt.Fprintf(&b, "test: %s\n", t.Name())
+	fmt.Fprintf(&b, "time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	section("configuration")
+	fmt.Fprintf(&b, "seed: %d\n", r.opts.seed)
+	fmt.Fprintf(&b, "verbosity: %d\n", r.opts.verbosity)
+	fmt.Fprintf(&b, "prefixes: %s\n", strings.Join(r.opts.prefixes, ", "))
+	if r.opts.shuffle != nil {
+		fmt.Fprintf(&b, "shuffle: %d\n", *r.opts.shuffle)
+	}
+	fmt.Fprintf(&b, "fail fast: %t\n", r.opts.failFast)
+	fmt.Fprintf(&b, "parallel: %t\n", r.opts.parallel)
+	if r.opts.timeout > 0 {
+		fmt.Fprintf(&b, "timeout: %v\n", r.opts.timeout)
+	}
+	fmt.Fprintf(&b, "args: %s\n", strings.Join(os.Args, " "))
+
+	section("environment")
+	for _, v := range redactedEnv(r.env().Environ()) {
+		fmt.Fprintln(&b, v)
+	}
+
+	section("goroutines")
+	stacks := goroutines()
+	ids := make([]string, 0, len(stacks))
+	for id := range stacks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%s\n\n", stacks[id])
+	}
+
+	section("logs")
+	b.WriteString(logs)
+	return b.Bytes()
+}
+
+// secretName matches the names of environment variables that likely hold
+// secrets, or of flags, e.g. -testify.webhook, whose URL usually embeds
+// one.
+var secretName = regexp.MustCompile(`(?i)secret|token|passw(or)?d|pwd|credential|private|api_?key|auth|webhook`)
+
+// redactedEnv returns the environment variables env, sorted, with the
+// values of the likely secrets replaced.
+func redactedEnv(env []string) []string {
+	redacted := make([]string, 0, len(env))
+	for _, v := range env {
+		name, value, _ := strings.Cut(v, "=")
+		redacted = append(redacted, name+"="+redactedValue(name, value))
+	}
+	sort.Strings(redacted)
+	return redacted
+}
+
+// redactedValue returns value, the value of the environment variable or
+// flag name, or a placeholder if it is likely a secret.
+func redactedValue(name, value string) string {
+	if value != "" && secretName.MatchString(name) {
+		return "<redacted>"
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteDiagnosticsTester struct {
+	Suite
+	artifacts []string
+}
+
+func (s *SuiteDiagnosticsTester) TestFails() {
+	s.artifacts = append(s.artifacts, s.ArtifactDir())
+	log.Print("connecting to the database")
+	s.T().Fail()
+}
+
+func (s *SuiteDiagnosticsTester) TestPasses() {
+	s.artifacts = append(s.artifacts, s.ArtifactDir())
+}
+
+func TestDiagnostics(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+	t.Setenv("TESTIFY_API_TOKEN", "hunter2")
+	t.Setenv("TESTIFY_REGION", "eu-west-1")
+	dir := t.TempDir()
+	s := new(SuiteDiagnosticsTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithArtifactDir(dir), WithDiagnostics(), WithSeed(42))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	path := filepath.Join(dir, "DetachedSuite", "TestFails", "diagnostics.txt")
+	assert.Contains(t, output, "suite: wrote diagnostics to "+path)
+	bundle, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(bundle), "test: DetachedSuite/TestFails\n")
+	assert.Contains(t, string(bundle), "seed: 42\n")
+	assert.Contains(t, string(bundle), "TESTIFY_API_TOKEN=<redacted>\n")
+	assert.Contains(t, string(bundle), "TESTIFY_REGION=eu-west-1\n")
+	assert.Contains(t, string(bundle), "== goroutines ==")
+	assert.Contains(t, string(bundle), "connecting to the database")
+	assert.NotContains(t, string(bundle), "hunter2")
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "DetachedSuite", "TestFails"),
+		filepath.Join(dir, "DetachedSuite", "TestPasses"),
+	}, s.artifacts)
+	assert.NoFileExists(t, filepath.Join(dir, "DetachedSuite", "TestPasses", "diagnostics.txt"))
+}
+
+func TestArtifactDirDefault(t *testing.T) {
+	s := new(SuiteDiagnosticsTester)
+	Run(t, s, WithFilter(func(methodName string) bool { return methodName == "TestPasses" }))
+	require.Len(t, s.artifacts, 1)
+	assert.NoDirExists(t, s.artifacts[0], "the temporary artifact directory is removed after the test")
+}
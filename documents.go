@@ -0,0 +1,141 @@
+package suite
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// JSONEq asserts that two JSON documents are semantically equal, ignoring
+// the order of object keys and formatting. On failure a unified diff of
+// the normalized documents is reported.
+func (suite *Suite) JSONEq(expected, actual string, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	return suite.documentEq(json.Unmarshal, "JSON", expected, actual, nil, msgAndArgs)
+}
+
+// JSONEqIgnoring is like JSONEq, but removes the fields at the given
+// paths from both documents before comparing them. A path is a dot
+// separated list of object keys, in which "*" matches any key or array
+// element, e.g. "items.*.id" or "metadata.createdAt".
+func (suite *Suite) JSONEqIgnoring(expected, actual string, ignored []string, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	return suite.documentEq(json.Unmarshal, "JSON", expected, actual, ignored, msgAndArgs)
+}
+
+// YAMLEq asserts that two YAML documents are semantically equal, ignoring
+// the order of mapping keys and formatting. On failure a unified diff of
+// the normalized documents is reported.
+func (suite *Suite) YAMLEq(expected, actual string, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	return suite.documentEq(yaml.Unmarshal, "YAML", expected, actual, nil, msgAndArgs)
+}
+
+// YAMLEqIgnoring is like YAMLEq, but removes the fields at the given paths
+// from both documents before comparing them. Paths are interpreted as by
+// JSONEqIgnoring.
+func (suite *Suite) YAMLEqIgnoring(expected, actual string, ignored []string, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	return suite.documentEq(yaml.Unmarshal, "YAML", expected, actual, ignored, msgAndArgs)
+}
+
+func (suite *Suite) documentEq(unmarshal func([]byte, interface{}) error, format, expected, actual string, ignored []string, msgAndArgs []interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	var expectedDoc, actualDoc interface{}
+	if err := unmarshal([]byte(expected), &expectedDoc); err != nil {
+		suite.errorf(msgAndArgs, "Expected value ('%s') is not valid %s: %v", expected, format, err)
+		return false
+	}
+	if err := unmarshal([]byte(actual), &actualDoc); err != nil {
+		suite.errorf(msgAndArgs, "Input ('%s') needs to be valid %s: %v", actual, format, err)
+		return false
+	}
+	expectedDoc, actualDoc = normalizeDocument(expectedDoc), normalizeDocument(actualDoc)
+	for _, path := range ignored {
+		removePath(expectedDoc, strings.Split(path, "."))
+		removePath(actualDoc, strings.Split(path, "."))
+	}
+	if reflect.DeepEqual(expectedDoc, actualDoc) {
+		return true
+	}
+	suite.errorf(msgAndArgs, "%s documents are not equal:\n%s", format, unifiedDiff(expectedDoc, actualDoc))
+	return false
+}
+
+// normalizeDocument converts the mappings of a decoded YAML document to
+// map[string]interface{}, as produced when decoding JSON, so that they can
+// be compared with and printed as JSON.
+func normalizeDocument(doc interface{}) interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range doc {
+			doc[k] = normalizeDocument(v)
+		}
+		return doc
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(doc))
+		for k, v := range doc {
+			m[fmt.Sprint(k)] = normalizeDocument(v)
+		}
+		return m
+	case []interface{}:
+		for i, v := range doc {
+			doc[i] = normalizeDocument(v)
+		}
+		return doc
+	}
+	return doc
+}
+
+// removePath deletes the fields matching path from doc.
+func removePath(doc interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	key, rest := path[0], path[1:]
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range doc {
+			if key != "*" && key != k {
+				continue
+			}
+			if len(rest) == 0 {
+				delete(doc, k)
+			} else {
+				removePath(v, rest)
+			}
+		}
+	case []interface{}:
+		if key != "*" {
+			return
+		}
+		for _, v := range doc {
+			removePath(v, rest)
+		}
+	}
+}
+
+// unifiedDiff returns a unified diff of the indented JSON representations
+// of expected and actual.
+func unifiedDiff(expected, actual interface{}) string {
+	e, _ := json.MarshalIndent(expected, "", "  ")
+	a, _ := json.MarshalIndent(actual, "", "  ")
+	return diffLines(string(e), string(a))
+}
+
+// diffLines returns a unified diff of two texts.
+func diffLines(expected, actual string) string {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected + "\n"),
+		B:        difflib.SplitLines(actual + "\n"),
+		FromFile: "Expected",
+		ToFile:   "Actual",
+		Context:  3,
+	})
+	return diff
+}
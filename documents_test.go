@@ -0,0 +1,41 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteDocumentTester struct {
+	Suite
+}
+
+func (s *SuiteDocumentTester) TestEqual() {
+	s.JSONEq(`{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`)
+	s.YAMLEq("a: 1\nb: [1, 2]\n", "b:\n- 1\n- 2\na: 1\n")
+	s.JSONEqIgnoring(
+		`{"id": 1, "items": [{"id": 2, "name": "x"}]}`,
+		`{"id": 3, "items": [{"id": 4, "name": "x"}]}`,
+		[]string{"id", "items.*.id"})
+	s.YAMLEqIgnoring("meta: {created: yesterday}\nname: x\n", "meta: {created: today}\nname: x\n", []string{"meta.created"})
+}
+
+func (s *SuiteDocumentTester) TestNotEqual() {
+	s.JSONEq(`{"a": 1, "b": "expected"}`, `{"a": 1, "b": "actual"}`)
+	s.YAMLEq("a: [", "a: 1")
+}
+
+func TestSuiteDocumentEquality(t *testing.T) {
+	recorder := new(recordingReporter)
+	_, output, err := runDetachedSuiteWithOutputCapture(new(SuiteDocumentTester), WithReporter(recorder))
+	require.NoError(t, err)
+	require.Len(t, recorder.Finished, 2)
+	assert.Equal(t, StatusPassed, recorder.Finished[0].Status, output)
+	assert.Equal(t, StatusFailed, recorder.Finished[1].Status)
+
+	assert.Contains(t, output, "JSON documents are not equal")
+	assert.Contains(t, output, `-  "b": "expected"`)
+	assert.Contains(t, output, `+  "b": "actual"`)
+	assert.Contains(t, output, "is not valid YAML")
+}
@@ -0,0 +1,149 @@
+package suite
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EnvKey is the key of the *Env of a test in its context, see Suite.Env
+// and EnvFrom.
+const EnvKey contextKey = "env"
+
+// Env is the environment of a test, layered over that of its suite, which
+// is layered over the environment of the process. Setting a variable in
+// the environment of a test only changes it for that test, so that tests
+// running in parallel can set variables without racing on the environment
+// of the process:
+//
+//	func (s *ClientSuite) TestProxy() {
+//		s.Env().Set("HTTPS_PROXY", s.proxy.URL)
+//		client := NewClient(s.Env().Get)
+//		...
+//	}
+//
+// The code under test has to read its variables through the Env, e.g. by
+// taking a lookup function, or through EnvFrom and the context of the
+// test; subprocesses get it with Environ. The fixtures of the suite, such
+// as Secret and SkipUnlessEnv, read the environment of the current test.
+//
+// A nil *Env is the environment of the process, which can't be changed
+// through it. Env is safe for concurrent use.
+type Env struct {
+	mu     sync.RWMutex
+	parent *Env
+	// values maps the variables set in this layer to their values, or to
+	// nil if they were unset.
+	values map[string]*string
+}
+
+// Env returns the environment of the current test, or of the suite
+// outside of tests, e.g. in SetupSuite, inherited by all of its tests.
+func (suite *Suite) Env() *Env {
+	env, ok := ContextValue(suite.Context(), EnvKey).(*Env)
+	if !ok {
+		suite.T().Fatalf("suite: Env must be called in a suite run by Run")
+	}
+	return env
+}
+
+// EnvFrom returns the environment of the test whose context is ctx, or nil,
+// the environment of the process, if ctx isn't the context of a test.
+func EnvFrom(ctx context.Context) *Env {
+	env, _ := ContextValue(ctx, EnvKey).(*Env)
+	return env
+}
+
+// Get returns the value of the variable name, or "" if it isn't set.
+func (e *Env) Get(name string) string {
+	value, _ := e.Lookup(name)
+	return value
+}
+
+// Lookup returns the value of the variable name and whether it is set.
+func (e *Env) Lookup(name string) (string, bool) {
+	if e == nil {
+		return os.LookupEnv(name)
+	}
+	e.mu.RLock()
+	value, ok := e.values[name]
+	e.mu.RUnlock()
+	if !ok {
+		return e.parent.Lookup(name)
+	}
+	if value == nil {
+		return "", false
+	}
+	return *value, true
+}
+
+// Set sets the variable name to value.
+func (e *Env) Set(name, value string) {
+	e.set(name, &value)
+}
+
+// Unset unsets the variable name.
+func (e *Env) Unset(name string) {
+	e.set(name, nil)
+}
+
+func (e *Env) set(name string, value *string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.values == nil {
+		e.values = make(map[string]*string)
+	}
+	e.values[name] = value
+}
+
+// Environ returns the variables of the environment in the form
+// NAME=value, sorted by name, e.g. for the Env field of an exec.Cmd.
+func (e *Env) Environ() []string {
+	vars := e.variables()
+	environ := make([]string, 0, len(vars))
+	for name, value := range vars {
+		environ = append(environ, name+"="+value)
+	}
+	sort.Strings(environ)
+	return environ
+}
+
+// variables returns the variables of the environment by name.
+func (e *Env) variables() map[string]string {
+	if e == nil {
+		vars := make(map[string]string)
+		for _, v := range os.Environ() {
+			if name, value, ok := strings.Cut(v, "="); ok {
+				vars[name] = value
+			}
+		}
+		return vars
+	}
+	vars := e.parent.variables()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for name, value := range e.values {
+		if value == nil {
+			delete(vars, name)
+		} else {
+			vars[name] = *value
+		}
+	}
+	return vars
+}
+
+// env returns the environment of the current test, or of the process if
+// the suite isn't run by Run.
+func (suite *Suite) env() *Env {
+	return EnvFrom(suite.Context())
+}
+
+// env returns the environment of the current test of the suite.
+func (r *suiteRun) env() *Env {
+	if b, ok := r.suite.(suiteBase); ok {
+		return b.base().env()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package suite
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvLayers(t *testing.T) {
+	t.Setenv("SUITE_TEST_ENV_PROCESS", "process")
+	t.Setenv("SUITE_TEST_ENV_UNSET", "process")
+	suiteEnv := &Env{}
+	suiteEnv.Set("SUITE_TEST_ENV_SUITE", "suite")
+	testEnv := &Env{parent: suiteEnv}
+	testEnv.Set("SUITE_TEST_ENV_PROCESS", "test")
+	testEnv.Unset("SUITE_TEST_ENV_UNSET")
+
+	assert.Equal(t, "test", testEnv.Get("SUITE_TEST_ENV_PROCESS"))
+	assert.Equal(t, "suite", testEnv.Get("SUITE_TEST_ENV_SUITE"))
+	_, ok := testEnv.Lookup("SUITE_TEST_ENV_UNSET")
+	assert.False(t, ok)
+	assert.Equal(t, "process", suiteEnv.Get("SUITE_TEST_ENV_PROCESS"))
+	assert.Equal(t, "process", os.Getenv("SUITE_TEST_ENV_PROCESS"))
+
+	environ := testEnv.Environ()
+	assert.Contains(t, environ, "SUITE_TEST_ENV_PROCESS=test")
+	assert.Contains(t, environ, "SUITE_TEST_ENV_SUITE=suite")
+	assert.NotContains(t, environ, "SUITE_TEST_ENV_UNSET=process")
+
+	var process *Env
+	assert.Equal(t, "process", process.Get("SUITE_TEST_ENV_PROCESS"))
+	assert.Nil(t, EnvFrom(context.Background()))
+}
+
+type SuiteEnvTester struct {
+	Suite
+	seen map[string]string
+}
+
+func (s *SuiteEnvTester) SetupSuite() {
+	s.seen = make(map[string]string)
+	s.Env().Set("SUITE_TEST_ENV_REGION", "eu-west-1")
+}
+
+func (s *SuiteEnvTester) TestA() {
+	s.Env().Set("SUITE_TEST_ENV_NAME", "a")
+	s.record()
+}
+
+func (s *SuiteEnvTester) TestB() {
+	s.Env().Set("SUITE_TEST_ENV_NAME", "b")
+	s.record()
+}
+
+func (s *SuiteEnvTester) TestC() {
+	s.SkipUnlessEnv("SUITE_TEST_ENV_NAME")
+	s.record()
+}
+
+func (s *SuiteEnvTester) record() {
+	env := EnvFrom(s.Context())
+	cmd := exec.Command("sh", "-c", "echo $SUITE_TEST_ENV_REGION/$SUITE_TEST_ENV_NAME")
+	cmd.Env = env.Environ()
+	out, err := cmd.Output()
+	s.Require().NoError(err)
+	s.seen[s.T().Name()] = strings.TrimSpace(string(out))
+}
+
+func TestSuiteEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	s := new(SuiteEnvTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, map[string]string{
+		"DetachedSuite/TestA": "eu-west-1/a",
+		"DetachedSuite/TestB": "eu-west-1/b",
+	}, s.seen)
+	_, set := os.LookupEnv("SUITE_TEST_ENV_NAME")
+	assert.False(t, set)
+}
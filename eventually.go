@@ -0,0 +1,109 @@
+package suite
+
+import (
+	"fmt"
+	"time"
+)
+
+// Eventually asserts that condition returns true within waitFor, polling
+// it every tick. Polling stops early, failing the assertion, when the
+// test's context is done, e.g. because the test binary is about to time
+// out. When timeouts are disabled (see WithoutTimeouts), polling goes on
+// until the test's context is done.
+func (suite *Suite) Eventually(condition func() bool, waitFor, tick time.Duration, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	return suite.EventuallyState(func() (bool, interface{}) {
+		return condition(), nil
+	}, waitFor, tick, msgAndArgs...)
+}
+
+// EventuallyState is like Eventually, but condition also returns the state
+// it observed, e.g. the status of a resource being waited for. The last
+// observed state is included in the failure message.
+func (suite *Suite) EventuallyState(condition func() (bool, interface{}), waitFor, tick time.Duration, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	p := suite.poll(condition, waitFor, tick, true)
+	if p.ok {
+		return true
+	}
+	suite.errorf(msgAndArgs, "Condition never satisfied: %s", p)
+	return false
+}
+
+// Never asserts that condition doesn't return true for waitFor, polling it
+// every tick. Polling stops early, failing the assertion, when the test's
+// context is done.
+func (suite *Suite) Never(condition func() bool, waitFor, tick time.Duration, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	p := suite.poll(func() (bool, interface{}) {
+		return condition(), nil
+	}, waitFor, tick, false)
+	if p.ok {
+		return true
+	}
+	suite.errorf(msgAndArgs, "Condition satisfied: %s", p)
+	return false
+}
+
+// polling is the outcome of polling a condition.
+type polling struct {
+	ok       bool
+	polls    int
+	elapsed  time.Duration
+	state    interface{}
+	hasState bool
+	aborted  error
+}
+
+func (p polling) String() string {
+	s := fmt.Sprintf("%d polls over %v", p.polls, p.elapsed.Round(time.Millisecond))
+	if p.aborted != nil {
+		s += fmt.Sprintf(", aborted: %v", p.aborted)
+	}
+	if p.hasState {
+		s += fmt.Sprintf("\nLast observed state: %#v", p.state)
+	}
+	return s
+}
+
+// poll calls condition every tick until it returns true, waitFor has
+// elapsed or the test's context is done. With want set, for Eventually,
+// the result is ok if condition returned true; otherwise, for Never, it is
+// ok if condition kept returning false until waitFor elapsed.
+func (suite *Suite) poll(condition func() (bool, interface{}), waitFor, tick time.Duration, want bool) polling {
+	ctx := suite.Context()
+	start := time.Now()
+	timer := time.NewTimer(waitFor)
+	defer timer.Stop()
+	timeout := timer.C
+	if want && suite.noTimeouts {
+		timeout = nil
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var p polling
+	for {
+		select {
+		case <-ctx.Done():
+			p.aborted = ctx.Err()
+			p.elapsed = time.Since(start)
+			return p
+		case <-timeout:
+			p.ok = !want
+			p.elapsed = time.Since(start)
+			return p
+		case <-ticker.C:
+			ok, state := condition()
+			p.polls++
+			p.state, p.hasState = state, state != nil
+			if ok {
+				p.ok = want
+				p.elapsed = time.Since(start)
+				return p
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package suite
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteEventuallyTester struct {
+	Suite
+
+	TestContext context.Context
+	results     map[string]bool
+}
+
+func (s *SuiteEventuallyTester) SetupSuite() {
+	s.results = make(map[string]bool)
+}
+
+func (s *SuiteEventuallyTester) TestEventually() {
+	var calls int32
+	s.results["Eventually"] = s.Eventually(func() bool {
+		return atomic.AddInt32(&calls, 1) == 3
+	}, time.Second, time.Millisecond)
+	s.TestContext = s.Context()
+}
+
+func (s *SuiteEventuallyTester) TestEventuallyFails() {
+	s.results["EventuallyState"] = s.EventuallyState(func() (bool, interface{}) {
+		return false, "still pending"
+	}, 10*time.Millisecond, time.Millisecond, "waiting for %s", "resource")
+}
+
+func (s *SuiteEventuallyTester) TestNever() {
+	var calls int32
+	s.results["Never"] = s.Never(func() bool {
+		atomic.AddInt32(&calls, 1)
+		return false
+	}, 20*time.Millisecond, time.Millisecond)
+	s.Assert().Greater(int(atomic.LoadInt32(&calls)), 1, "Never keeps polling while the condition is false")
+}
+
+func (s *SuiteEventuallyTester) TestNeverFails() {
+	var calls int32
+	s.results["NeverFails"] = s.Never(func() bool {
+		return atomic.AddInt32(&calls, 1) == 3
+	}, time.Second, time.Millisecond)
+}
+
+func TestSuiteEventually(t *testing.T) {
+	s := new(SuiteEventuallyTester)
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReporter(recorder))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, map[string]bool{"Eventually": true, "EventuallyState": false, "Never": true, "NeverFails": false}, s.results)
+	statuses := make(map[string]Status)
+	for _, result := range recorder.Finished {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"TestEventually":      StatusPassed,
+		"TestEventuallyFails": StatusFailed,
+		"TestNever":           StatusPassed,
+		"TestNeverFails":      StatusFailed,
+	}, statuses)
+	assert.Contains(t, output, "Condition never satisfied:")
+	assert.Contains(t, output, `Last observed state: "still pending"`)
+	assert.Contains(t, output, "Messages: waiting for resource")
+	assert.Contains(t, output, "Condition satisfied: 3 polls")
+
+	require.NotNil(t, s.TestContext)
+	assert.Error(t, s.TestContext.Err(), "the test context must be canceled after the test")
+}
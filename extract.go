@@ -0,0 +1,292 @@
+package suite
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// extractCacheSize is the size from which archives are only unpacked once
+// per suite, see ExtractTestdata.
+var extractCacheSize int64 = 1 << 20
+
+// ExtractTestdata unpacks the archive testdata/<name> into a new temporary
+// directory of the current test and returns its path. Archives ending in
+// .tar, .tar.gz, .tgz and .zip are supported. The directory is removed
+// when the test finishes unless the retention policy of the suite keeps
+// it, see Dispose.
+//
+// Archives of 1MiB or more are unpacked only once per suite, and their
+// tree is copied into the directory of each test, so that tests are still
+// free to modify it. Tests running in parallel share the unpacked tree,
+// and the first test needing it unpacks it while the others wait.
+func (suite *Suite) ExtractTestdata(name string) string {
+	t := suite.T()
+	t.Helper()
+	archive := filepath.Join("testdata", name)
+	info, err := os.Stat(archive)
+	if err != nil {
+		t.Fatalf("suite: failed to extract %s: %v", archive, err)
+	}
+	dir := suite.tempDirFor(t)
+	if info.Size() < extractCacheSize || suite.extracted == nil {
+		if err := extract(archive, dir); err != nil {
+			t.Fatalf("suite: failed to extract %s: %v", archive, err)
+		}
+		return dir
+	}
+	cached, err := suite.extracted.dir(archive, func() (string, error) {
+		cached := suite.tempDirFor(suite.suiteT)
+		suite.suiteT.Cleanup(func() {
+			suite.extracted.forget(archive)
+		})
+		return cached, extract(archive, cached)
+	})
+	if err != nil {
+		t.Fatalf("suite: failed to extract %s: %v", archive, err)
+	}
+	if err := copyTree(cached, dir); err != nil {
+		t.Fatalf("suite: failed to extract %s: %v", archive, err)
+	}
+	return dir
+}
+
+// extractCache maps the archives unpacked once per suite to their
+// directory. It is shared by the tests of the suite, which may run in
+// parallel.
+type extractCache struct {
+	mu   sync.Mutex
+	dirs map[string]string
+}
+
+// dir returns the directory archive was unpacked to, calling unpack to
+// unpack it if it wasn't yet. The cache stays locked while unpack runs, so
+// that tests running in parallel don't unpack the same archive twice.
+func (c *extractCache) dir(archive string, unpack func() (string, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if dir, ok := c.dirs[archive]; ok {
+		return dir, nil
+	}
+	dir, err := unpack()
+	if err != nil {
+		return "", err
+	}
+	c.dirs[archive] = dir
+	return dir, nil
+}
+
+// lookup returns the directory archive was unpacked to, if it was.
+func (c *extractCache) lookup(archive string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dir, ok := c.dirs[archive]
+	return dir, ok
+}
+
+// forget drops archive from the cache once its directory is removed.
+func (c *extractCache) forget(archive string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.dirs, archive)
+}
+
+// extract unpacks the archive at path into dir.
+func extract(path, dir string) error {
+	switch {
+	case strings.HasSuffix(path, ".zip"):
+		return extractZip(path, dir)
+	case strings.HasSuffix(path, ".tar"):
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return extractTar(f, dir)
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"):
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return extractTar(gz, dir)
+	}
+	return fmt.Errorf("unsupported archive format, want .tar, .tar.gz, .tgz or .zip")
+}
+
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := extractPath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeExtracted(target, header.FileInfo().Mode(), tr); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := checkLink(header.Name, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: unsupported entry type %q", header.Name, header.Typeflag)
+		}
+	}
+}
+
+func extractZip(path, dir string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		target, err := extractPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeExtracted(target, f.Mode(), r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractPath returns the path in dir of the archive entry name, refusing
+// entries escaping dir, including through the symlinks extracted before.
+func extractPath(dir, name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("%s: entry is outside of the archive's root", name)
+	}
+	parent := dir
+	for _, elem := range strings.Split(filepath.Dir(local), string(filepath.Separator)) {
+		if elem == "." {
+			continue
+		}
+		parent = filepath.Join(parent, elem)
+		if info, err := os.Lstat(parent); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("%s: entry is inside a symlink", name)
+		}
+	}
+	return filepath.Join(dir, local), nil
+}
+
+// checkLink refuses the symlink entry name of an archive pointing to link
+// if link could resolve outside of the archive's root: link must be
+// relative, stay in the root from the directory of name and only go up at
+// its start, so that going up doesn't follow other symlinks.
+func checkLink(name, link string) error {
+	local := filepath.FromSlash(link)
+	if !filepath.IsLocal(filepath.Join(filepath.Dir(filepath.FromSlash(name)), local)) || filepath.IsAbs(local) {
+		return fmt.Errorf("%s: symlink to %s is outside of the archive's root", name, link)
+	}
+	down := false
+	for _, elem := range strings.Split(local, string(filepath.Separator)) {
+		switch elem {
+		case "..":
+			if down {
+				return fmt.Errorf("%s: symlink to %s goes up after going down", name, link)
+			}
+		case "", ".":
+		default:
+			down = true
+		}
+	}
+	return nil
+}
+
+func writeExtracted(target string, mode fs.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0200)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// copyTree copies the tree rooted at src into dst, which must exist.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm()|0700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := checkLink(filepath.ToSlash(rel), link); err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return writeExtracted(target, info.Mode(), f)
+	})
+}
@@ -0,0 +1,156 @@
+package suite
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteExtractTester struct {
+	Suite
+	archive string
+	cached  []string
+}
+
+func (s *SuiteExtractTester) TestA() {
+	dir := s.ExtractTestdata(s.archive)
+	s.checkExtracted(dir)
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "greeting.txt"), []byte("changed"), 0644))
+}
+
+func (s *SuiteExtractTester) TestB() {
+	s.checkExtracted(s.ExtractTestdata(s.archive))
+}
+
+func (s *SuiteExtractTester) checkExtracted(dir string) {
+	greeting, err := os.ReadFile(filepath.Join(dir, "greeting.txt"))
+	s.Require().NoError(err)
+	s.Equal("hello\n", string(greeting))
+	info, err := os.Stat(filepath.Join(dir, "bin", "run.sh"))
+	s.Require().NoError(err)
+	s.Equal(os.FileMode(0755), info.Mode().Perm())
+	if cached, ok := s.extracted.lookup(filepath.Join("testdata", s.archive)); ok {
+		s.cached = append(s.cached, cached)
+	}
+}
+
+func TestExtractTestdata(t *testing.T) {
+	for _, archive := range []string{"fixture.tar.gz", "fixture.zip"} {
+		t.Run(archive, func(t *testing.T) {
+			s := &SuiteExtractTester{archive: archive}
+			ok, output, err := runDetachedSuiteWithOutputCapture(s)
+			require.NoError(t, err)
+			assert.True(t, ok, output)
+			assert.Empty(t, s.cached)
+		})
+	}
+}
+
+func TestExtractTestdataCached(t *testing.T) {
+	defer func(old int64) { extractCacheSize = old }(extractCacheSize)
+	extractCacheSize = 0
+
+	s := &SuiteExtractTester{archive: "fixture.tar.gz"}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	require.Len(t, s.cached, 2)
+	assert.Equal(t, s.cached[0], s.cached[1])
+	assert.NoDirExists(t, s.cached[0])
+	assert.Empty(t, s.extracted.dirs)
+
+	// Tests running in parallel unpack the archive once.
+	s = &SuiteExtractTester{archive: "fixture.tar.gz"}
+	ok, output, err = runDetachedSuiteWithOutputCapture(s, WithParallel())
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Empty(t, s.extracted.dirs)
+}
+
+type SuiteExtractMissingTester struct{ Suite }
+
+func (s *SuiteExtractMissingTester) TestMissing() {
+	s.ExtractTestdata("missing.tar.gz")
+}
+
+func TestExtractTestdataMissing(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteExtractMissingTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: failed to extract testdata/missing.tar.gz")
+}
+
+func TestExtractPath(t *testing.T) {
+	path, err := extractPath("/tmp/x", "a/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/x", "a", "b.txt"), path)
+	for _, name := range []string{"../escape", "/etc/passwd", "a/../../escape"} {
+		_, err := extractPath("/tmp/x", name)
+		assert.ErrorContains(t, err, "outside of the archive's root", name)
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	for link, allowed := range map[string]bool{
+		"b":           true,
+		"../c":        true,
+		"./b/c":       true,
+		"/etc/passwd": false,
+		"../../c":     false,
+		"b/../c":      false,
+		"b/../../c":   false,
+	} {
+		err := checkLink("a/link", link)
+		if allowed {
+			assert.NoError(t, err, link)
+		} else {
+			assert.Error(t, err, link)
+		}
+	}
+}
+
+func TestExtractTarSymlinks(t *testing.T) {
+	archive := func(headers ...*tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		w := tar.NewWriter(&buf)
+		for _, header := range headers {
+			require.NoError(t, w.WriteHeader(header))
+		}
+		require.NoError(t, w.Close())
+		return &buf
+	}
+	dir := t.TempDir()
+	err := extractTar(archive(&tar.Header{Name: "a/link", Typeflag: tar.TypeSymlink, Linkname: "../../outside"}), dir)
+	assert.ErrorContains(t, err, "a/link: symlink to ../../outside is outside of the archive's root")
+	err = extractTar(archive(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"}), dir)
+	assert.ErrorContains(t, err, "link: symlink to /etc is outside of the archive's root")
+
+	// A symlink to the root is allowed, but not entries going through it.
+	dir = t.TempDir()
+	err = extractTar(archive(
+		&tar.Header{Name: "root", Typeflag: tar.TypeSymlink, Linkname: "."},
+		&tar.Header{Name: "root/up", Typeflag: tar.TypeSymlink, Linkname: ".."},
+	), dir)
+	assert.ErrorContains(t, err, "root/up: entry is inside a symlink")
+	_, err = os.Lstat(filepath.Join(dir, "up"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCopyTreeSymlinks(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.Symlink("greeting.txt", filepath.Join(src, "inside")))
+	dst := t.TempDir()
+	require.NoError(t, copyTree(src, dst))
+	link, err := os.Readlink(filepath.Join(dst, "inside"))
+	require.NoError(t, err)
+	assert.Equal(t, "greeting.txt", link)
+
+	require.NoError(t, os.Symlink(filepath.Join("..", "outside"), filepath.Join(src, "outside")))
+	err = copyTree(src, t.TempDir())
+	assert.ErrorContains(t, err, "is outside of the archive's root")
+}
@@ -0,0 +1,99 @@
+package suite
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+)
+
+var (
+	factoryFirstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Rob", "Tony"}
+	factoryLastNames  = []string{"Allen", "Dijkstra", "Hamilton", "Hoare", "Hopper", "Kernighan", "Liskov", "Lovelace", "Perlman", "Pike", "Ritchie", "Thompson", "Turing", "Wirth"}
+	factoryWords      = []string{"amber", "birch", "cobalt", "delta", "ember", "fjord", "granite", "harbor", "indigo", "juniper", "kestrel", "lagoon", "meadow", "nimbus", "orchid", "pebble", "quartz", "raven", "sierra", "tundra"}
+)
+
+// Factory generates test data, such as names and email addresses, from
+// the random source of the current test, see Suite.Rand, so that the data
+// of a failed test is reproduced by rerunning it with the logged seed.
+// The identifiers it generates, such as email addresses, are prefixed
+// with a tag derived from the name of the test and numbered, so that they
+// don't collide with those of other tests sharing a database.
+type Factory struct {
+	rand   *rand.Rand
+	prefix string
+	n      int
+}
+
+// Factory returns the test data factory of the current test.
+//
+//	user := User{Name: s.Factory().Name(), Email: s.Factory().Email()}
+func (suite *Suite) Factory() *Factory {
+	if suite.test.factory == nil {
+		h := fnv.New32a()
+		h.Write([]byte(suite.T().Name()))
+		suite.test.factory = &Factory{
+			rand:   suite.Rand(),
+			prefix: fmt.Sprintf("t%08x", h.Sum32()),
+		}
+	}
+	return suite.test.factory
+}
+
+// Unique returns base prefixed with the tag of the test and suffixed with
+// a number unique within the test, e.g. "t1a2b3c4d-order-3".
+func (f *Factory) Unique(base string) string {
+	f.n++
+	return fmt.Sprintf("%s-%s-%d", f.prefix, base, f.n)
+}
+
+// Int returns a random integer in [min, max].
+func (f *Factory) Int(min, max int) int {
+	return min + f.rand.Intn(max-min+1)
+}
+
+// Bool returns a random boolean.
+func (f *Factory) Bool() bool {
+	return f.rand.Intn(2) == 1
+}
+
+// Word returns a random lowercase word.
+func (f *Factory) Word() string {
+	return factoryWords[f.rand.Intn(len(factoryWords))]
+}
+
+// String returns a random string of n lowercase letters and digits.
+func (f *Factory) String(n int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[f.rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+// Name returns a random full name.
+func (f *Factory) Name() string {
+	return factoryFirstNames[f.rand.Intn(len(factoryFirstNames))] + " " + factoryLastNames[f.rand.Intn(len(factoryLastNames))]
+}
+
+// Username returns a random username unique within the test run, see
+// Unique.
+func (f *Factory) Username() string {
+	return f.Unique(strings.ToLower(factoryFirstNames[f.rand.Intn(len(factoryFirstNames))]))
+}
+
+// Email returns a random email address unique within the test run, in
+// the reserved example.com domain.
+func (f *Factory) Email() string {
+	return f.Username() + "@example.com"
+}
+
+// UUID returns a random version 4 UUID.
+func (f *Factory) UUID() string {
+	var b [16]byte
+	f.rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,51 @@
+package suite
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteFactoryTester struct {
+	Suite
+	data map[string][]string
+}
+
+func (s *SuiteFactoryTester) generate() {
+	f := s.Factory()
+	if s.data == nil {
+		s.data = make(map[string][]string)
+	}
+	name := s.T().Name()
+	s.data[name] = append(s.data[name], f.Email(), f.Email(), f.UUID(), f.Name(), f.Unique("order"), f.String(8))
+}
+
+func (s *SuiteFactoryTester) TestA() { s.generate() }
+
+func (s *SuiteFactoryTester) TestB() { s.generate() }
+
+func TestFactory(t *testing.T) {
+	run := func(seed int64) map[string][]string {
+		s := new(SuiteFactoryTester)
+		ok, output, err := runDetachedSuiteWithOutputCapture(s, WithSeed(seed))
+		require.NoError(t, err)
+		require.True(t, ok, output)
+		return s.data
+	}
+	first := run(42)
+	assert.Equal(t, first, run(42))
+	assert.NotEqual(t, first, run(43))
+
+	a, b := first["DetachedSuite/TestA"], first["DetachedSuite/TestB"]
+	require.Len(t, a, 6)
+	assert.Regexp(t, `^t[0-9a-f]{8}-[a-z]+-1@example\.com$`, a[0])
+	assert.Regexp(t, `^t[0-9a-f]{8}-[a-z]+-2@example\.com$`, a[1])
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, a[2])
+	assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+$`, a[3])
+	assert.Regexp(t, `^t[0-9a-f]{8}-order-3$`, a[4])
+	assert.Regexp(t, `^[a-z0-9]{8}$`, a[5])
+	prefix := regexp.MustCompile(`^t[0-9a-f]{8}`)
+	assert.NotEqual(t, prefix.FindString(a[0]), prefix.FindString(b[0]))
+}
@@ -0,0 +1,235 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Faults describes the faults injected into the fixtures of a test, to
+// verify how the code under test copes with them. They are declared per
+// test in its Metadata, or set by the test with Suite.InjectFaults, and
+// injected by the FaultInjector of the suite.
+type Faults struct {
+	// Latency delays every request and dial.
+	Latency time.Duration `json:"latency,omitempty"`
+	// ErrorRate is the probability of a request or a dial failing.
+	ErrorRate float64 `json:"error_rate,omitempty"`
+	// DropRate is the probability of a request or a read or write on a
+	// connection dropping the connection.
+	DropRate float64 `json:"drop_rate,omitempty"`
+}
+
+func (f Faults) String() string {
+	return fmt.Sprintf("latency %v, error rate %g, drop rate %g", f.Latency, f.ErrorRate, f.DropRate)
+}
+
+// ErrInjectedFault is the error of requests and dials failed by a
+// FaultInjector.
+var ErrInjectedFault = errors.New("suite: injected fault")
+
+// FaultInjector injects the faults of the running test of a suite into the
+// suite's fixtures. It is typically created in SetupSuite and wrapped
+// around the fixtures:
+//
+//	func (s *CheckoutSuite) SetupSuite() {
+//		faults := s.FaultInjector()
+//		s.payments = httptest.NewServer(faults.Handler(fakePayments))
+//		s.db = openDB(faults.DialContext((&net.Dialer{}).DialContext))
+//	}
+//
+//	func (s *CheckoutSuite) Metadata() map[string]suite.Metadata {
+//		return map[string]suite.Metadata{
+//			"TestSlowPayments": {Faults: &suite.Faults{Latency: 2 * time.Second}},
+//		}
+//	}
+//
+// Random faults are drawn from a source seeded like Suite.Rand. As the
+// faults are those of the running test, a FaultInjector can't tell tests
+// running in parallel apart.
+type FaultInjector struct {
+	mu     sync.Mutex
+	faults Faults
+	rand   *rand.Rand
+}
+
+// FaultInjector returns the fault injector of the suite.
+func (suite *Suite) FaultInjector() *FaultInjector {
+	if suite.faults == nil {
+		suite.faults = &FaultInjector{rand: rand.New(rand.NewSource(suite.seed))}
+	}
+	return suite.faults
+}
+
+// InjectFaults sets the faults injected into the fixtures of the suite for
+// the rest of the current test, overriding those of its Metadata.
+func (suite *Suite) InjectFaults(faults Faults) {
+	suite.FaultInjector().set(faults, suite.seed, suite.T().Name())
+	suite.test.randUsed = true
+}
+
+// setTestFaults sets the faults of the test named test, if the suite has a
+// fault injector or faults is set.
+func (suite *Suite) setTestFaults(faults *Faults, test string) {
+	if faults == nil {
+		if suite.faults != nil {
+			suite.faults.set(Faults{}, suite.seed, test)
+		}
+		return
+	}
+	suite.FaultInjector().set(*faults, suite.seed, test)
+	suite.test.randUsed = true
+}
+
+// set sets the injected faults, reseeding the random source for the test
+// named test.
+func (i *FaultInjector) set(faults Faults, seed int64, test string) {
+	h := fnv.New64a()
+	h.Write([]byte(test))
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = faults
+	i.rand = rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+}
+
+// Faults returns the faults currently injected.
+func (i *FaultInjector) Faults() Faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.faults
+}
+
+// draw reports whether an event of probability p happens.
+func (i *FaultInjector) draw(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < p
+}
+
+// delay waits for the injected latency, or until ctx is done.
+func (i *FaultInjector) delay(ctx context.Context) error {
+	latency := i.Faults().Latency
+	if latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Handler returns a handler injecting faults into the requests served by
+// h: failed requests are answered with 503 Service Unavailable, and
+// dropped requests have their connection closed without an answer.
+func (i *FaultInjector) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := i.delay(r.Context()); err != nil {
+			return
+		}
+		faults := i.Faults()
+		if i.draw(faults.DropRate) {
+			if hijacker, ok := w.(http.Hijacker); ok {
+				if conn, _, err := hijacker.Hijack(); err == nil {
+					conn.Close()
+					return
+				}
+			}
+			panic(http.ErrAbortHandler)
+		}
+		if i.draw(faults.ErrorRate) {
+			http.Error(w, ErrInjectedFault.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Transport returns a round tripper injecting faults into the requests
+// made through rt, or http.DefaultTransport if rt is nil: failed and
+// dropped requests return an error wrapping ErrInjectedFault.
+func (i *FaultInjector) Transport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if err := i.delay(r.Context()); err != nil {
+			return nil, err
+		}
+		faults := i.Faults()
+		if i.draw(faults.DropRate) {
+			return nil, fmt.Errorf("connection dropped: %w", ErrInjectedFault)
+		}
+		if i.draw(faults.ErrorRate) {
+			return nil, ErrInjectedFault
+		}
+		return rt.RoundTrip(r)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// DialContext returns a dial function injecting faults into the
+// connections made through dial, e.g. the connections of a database
+// client: failed dials return an error wrapping ErrInjectedFault, and
+// connections are dropped by closing them on a read or a write.
+func (i *FaultInjector) DialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if err := i.delay(ctx); err != nil {
+			return nil, err
+		}
+		if i.draw(i.Faults().ErrorRate) {
+			return nil, fmt.Errorf("dial %s %s: %w", network, address, ErrInjectedFault)
+		}
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		return &faultyConn{Conn: conn, injector: i}, nil
+	}
+}
+
+// faultyConn is a connection dropped at random.
+type faultyConn struct {
+	net.Conn
+	injector *FaultInjector
+}
+
+func (c *faultyConn) Read(b []byte) (int, error) {
+	if err := c.drop(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *faultyConn) Write(b []byte) (int, error) {
+	if err := c.drop(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+// drop closes the connection if the injector drops it.
+func (c *faultyConn) drop() error {
+	if !c.injector.draw(c.injector.Faults().DropRate) {
+		return nil
+	}
+	c.Conn.Close()
+	return fmt.Errorf("connection dropped: %w", ErrInjectedFault)
+}
@@ -0,0 +1,104 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteFaultsTester struct {
+	Suite
+	server *httptest.Server
+	client *http.Client
+}
+
+func (s *SuiteFaultsTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{
+		"TestErrors":  {Faults: &Faults{ErrorRate: 1}},
+		"TestDrops":   {Faults: &Faults{DropRate: 1}},
+		"TestLatency": {Faults: &Faults{Latency: 30 * time.Millisecond}},
+	}
+}
+
+func (s *SuiteFaultsTester) SetupSuite() {
+	faults := s.FaultInjector()
+	s.server = httptest.NewServer(faults.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	s.client = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+}
+
+func (s *SuiteFaultsTester) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *SuiteFaultsTester) get() (int, error) {
+	resp, err := s.client.Get(s.server.URL)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (s *SuiteFaultsTester) TestErrors() {
+	status, err := s.get()
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusServiceUnavailable, status)
+}
+
+func (s *SuiteFaultsTester) TestDrops() {
+	_, err := s.get()
+	s.Assert().Error(err)
+}
+
+func (s *SuiteFaultsTester) TestLatency() {
+	start := time.Now()
+	_, err := s.get()
+	s.Require().NoError(err)
+	s.Assert().GreaterOrEqual(time.Since(start), 30*time.Millisecond)
+}
+
+func (s *SuiteFaultsTester) TestNoFaults() {
+	status, err := s.get()
+	s.Require().NoError(err)
+	s.Assert().Equal(http.StatusOK, status)
+}
+
+func (s *SuiteFaultsTester) TestTransport() {
+	s.InjectFaults(Faults{ErrorRate: 1})
+	client := &http.Client{Transport: s.FaultInjector().Transport(nil)}
+	_, err := client.Get("http://192.0.2.1/")
+	s.Assert().ErrorIs(err, ErrInjectedFault)
+}
+
+func (s *SuiteFaultsTester) TestDialContext() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+	dial := s.FaultInjector().DialContext((&net.Dialer{}).DialContext)
+
+	conn, err := dial(context.Background(), "tcp", listener.Addr().String())
+	s.Require().NoError(err)
+	defer conn.Close()
+	s.InjectFaults(Faults{DropRate: 1})
+	_, err = conn.Write([]byte("ping"))
+	s.Assert().ErrorIs(err, ErrInjectedFault)
+
+	s.InjectFaults(Faults{ErrorRate: 1})
+	_, err = dial(context.Background(), "tcp", listener.Addr().String())
+	s.Assert().True(errors.Is(err, ErrInjectedFault))
+}
+
+func TestFaultInjection(t *testing.T) {
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteFaultsTester), WithReporter(recorder))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Len(t, recorder.Finished, 6)
+}
@@ -0,0 +1,175 @@
+package suite
+
+import "sync"
+
+// FlagProvider is a source of feature flags, such as the client of a
+// feature-flag service, whose flags tests override with Suite.WithFlag.
+// Code under test reading its flags through a MemFlags, or through an
+// adapter of the real service implementing this interface, can be tested
+// with any combination of flags, see WithFlagProvider.
+type FlagProvider interface {
+	// Override sets the flag name to value until restore is called, which
+	// restores the previous value of the flag.
+	Override(name string, value any) (restore func(), err error)
+}
+
+// WithFlagProvider sets the provider of the feature flags overridden by
+// Suite.WithFlag and returned by Suite.Flags, a MemFlags by default.
+func WithFlagProvider(p FlagProvider) Option {
+	return func(o *options) {
+		o.flagProvider = p
+	}
+}
+
+// featureFlags tracks the flags overridden by the tests of a suite.
+type featureFlags struct {
+	mu       sync.Mutex
+	provider FlagProvider
+	// owners maps the overridden flags to the test overriding them.
+	owners map[string]*flagOwner
+}
+
+type flagOwner struct {
+	test      string
+	overrides int
+}
+
+// Flags returns the provider of the feature flags of the suite, set with
+// WithFlagProvider, to be passed to the code under test.
+func (suite *Suite) Flags() FlagProvider {
+	return suite.featureFlags().provider
+}
+
+// featureFlags returns the feature flags of the suite, with a MemFlags
+// provider unless one was set with WithFlagProvider.
+func (suite *Suite) featureFlags() *featureFlags {
+	if suite.flags == nil {
+		suite.flags = &featureFlags{}
+	}
+	suite.flags.mu.Lock()
+	defer suite.flags.mu.Unlock()
+	if suite.flags.provider == nil {
+		suite.flags.provider = NewMemFlags(nil)
+	}
+	return suite.flags
+}
+
+// WithFlag overrides the feature flag name with value for the rest of the
+// current test, restoring its previous value when the test finishes:
+//
+//	func (s *CheckoutSuite) TestNewPaymentFlow() {
+//		s.WithFlag("new-payment-flow", true)
+//		...
+//	}
+//
+// A flag can't be overridden by tests running in parallel at the same
+// time, as they would see each other's overrides.
+func (suite *Suite) WithFlag(name string, value any) {
+	t := suite.T()
+	t.Helper()
+	flags := suite.featureFlags()
+	if err := flags.claim(name, t.Name()); err != nil {
+		t.Fatalf("suite: failed to override flag %s: %v", name, err)
+	}
+	restore, err := flags.provider.Override(name, value)
+	if err != nil {
+		flags.release(name)
+		t.Fatalf("suite: failed to override flag %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		restore()
+		flags.release(name)
+	})
+}
+
+// claim records that the test is overriding the flag name, failing if
+// another test is overriding it.
+func (f *featureFlags) claim(name, test string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	owner := f.owners[name]
+	if owner == nil {
+		if f.owners == nil {
+			f.owners = make(map[string]*flagOwner)
+		}
+		owner = &flagOwner{test: test}
+		f.owners[name] = owner
+	}
+	if owner.test != test {
+		return &flagConflictError{test: owner.test}
+	}
+	owner.overrides++
+	return nil
+}
+
+// release records that an override of the flag name was restored.
+func (f *featureFlags) release(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if owner := f.owners[name]; owner != nil {
+		if owner.overrides--; owner.overrides == 0 {
+			delete(f.owners, name)
+		}
+	}
+}
+
+type flagConflictError struct {
+	test string
+}
+
+func (e *flagConflictError) Error() string {
+	return "it is already overridden by " + e.test + ", which is running in parallel"
+}
+
+// MemFlags is an in-memory FlagProvider. It is safe for concurrent use.
+type MemFlags struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// NewMemFlags returns a MemFlags with the given flags set.
+func NewMemFlags(values map[string]any) *MemFlags {
+	f := &MemFlags{values: make(map[string]any)}
+	for name, value := range values {
+		f.values[name] = value
+	}
+	return f
+}
+
+// Value returns the value of the flag name, and whether it is set.
+func (f *MemFlags) Value(name string) (any, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	value, ok := f.values[name]
+	return value, ok
+}
+
+// Enabled reports whether the flag name is set to true.
+func (f *MemFlags) Enabled(name string) bool {
+	value, _ := f.Value(name)
+	enabled, _ := value.(bool)
+	return enabled
+}
+
+// Set sets the flag name to value.
+func (f *MemFlags) Set(name string, value any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[name] = value
+}
+
+func (f *MemFlags) Override(name string, value any) (func(), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	previous, ok := f.values[name]
+	f.values[name] = value
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if ok {
+			f.values[name] = previous
+		} else {
+			delete(f.values, name)
+		}
+	}, nil
+}
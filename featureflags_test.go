@@ -0,0 +1,80 @@
+package suite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteFeatureFlagsTester struct {
+	Suite
+	observed []interface{}
+}
+
+func (s *SuiteFeatureFlagsTester) observe(name string) {
+	value, _ := s.Flags().(*MemFlags).Value(name)
+	s.observed = append(s.observed, value)
+}
+
+func (s *SuiteFeatureFlagsTester) TestA_Override() {
+	s.WithFlag("new-checkout", true)
+	s.WithFlag("variant", "b")
+	s.WithFlag("variant", "c")
+	s.observe("new-checkout")
+	s.observe("variant")
+}
+
+func (s *SuiteFeatureFlagsTester) TestB_Restored() {
+	s.observe("new-checkout")
+	s.observe("variant")
+}
+
+func TestWithFlag(t *testing.T) {
+	flags := NewMemFlags(map[string]any{"variant": "a"})
+	s := new(SuiteFeatureFlagsTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithFlagProvider(flags))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, []interface{}{true, "c", nil, "a"}, s.observed)
+	assert.False(t, flags.Enabled("new-checkout"))
+}
+
+func TestWithFlagDefaultProvider(t *testing.T) {
+	s := new(SuiteFeatureFlagsTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, []interface{}{true, "c", nil, nil}, s.observed)
+}
+
+type failingFlags struct{}
+
+func (failingFlags) Override(name string, value any) (func(), error) {
+	return nil, errors.New("unknown flag")
+}
+
+type SuiteFailingFlagsTester struct{ Suite }
+
+func (s *SuiteFailingFlagsTester) TestOverride() {
+	s.WithFlag("missing", true)
+}
+
+func TestWithFlagProviderError(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteFailingFlagsTester), WithFlagProvider(failingFlags{}))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: failed to override flag missing: unknown flag")
+}
+
+func TestFeatureFlagsConflict(t *testing.T) {
+	f := &featureFlags{}
+	require.NoError(t, f.claim("beta", "Suite/TestA"))
+	require.NoError(t, f.claim("beta", "Suite/TestA"))
+	assert.EqualError(t, f.claim("beta", "Suite/TestB"), "it is already overridden by Suite/TestA, which is running in parallel")
+	f.release("beta")
+	assert.Error(t, f.claim("beta", "Suite/TestB"))
+	f.release("beta")
+	assert.NoError(t, f.claim("beta", "Suite/TestB"))
+}
@@ -0,0 +1,100 @@
+package suite
+
+import (
+	"flag"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// gitSHAVariables are the environment variables CI systems commonly set to
+// the commit being tested.
+var gitSHAVariables = []string{"GIT_COMMIT", "GITHUB_SHA", "CI_COMMIT_SHA", "BUILDKITE_COMMIT", "CIRCLE_SHA1"}
+
+// Environment describes the conditions a suite ran in, so that they are
+// captured alongside the results of a failing CI run.
+type Environment struct {
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	// Seed is the seed of the suite's random sources, see Suite.Rand.
+	Seed int64 `json:"seed"`
+	// ShuffleSeed is the seed the tests were shuffled with, if they were,
+	// see WithShuffle.
+	ShuffleSeed *int64 `json:"shuffle_seed,omitempty"`
+	// Flags are the command-line flags set for the test binary. The values
+	// of likely secrets, such as -testify.webhook, are redacted.
+	Flags map[string]string `json:"flags,omitempty"`
+	// Env holds the environment variables affecting Go programs, such as
+	// GOMAXPROCS and GODEBUG, CI and those named with WithFingerprintEnv.
+	// The values of likely secrets are redacted.
+	Env map[string]string `json:"env,omitempty"`
+	// GitSHA is the commit under test, as set with WithGitSHA or by the CI
+	// system, e.g. through GITHUB_SHA.
+	GitSHA string `json:"git_sha,omitempty"`
+}
+
+// WithGitSHA records sha as the commit under test in the environment of
+// the suite's results, see Environment.
+func WithGitSHA(sha string) Option {
+	return func(o *options) {
+		o.gitSHA = sha
+	}
+}
+
+// WithFingerprintEnv records the environment variables names in the
+// environment of the suite's results, in addition to those recorded by
+// default, see Environment.
+func WithFingerprintEnv(names ...string) Option {
+	return func(o *options) {
+		o.fingerprintEnv = append(o.fingerprintEnv, names...)
+	}
+}
+
+// environment returns the environment the suite runs in.
+func (r *suiteRun) environment() Environment {
+	env := Environment{
+		GoVersion:   runtime.Version(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Seed:        r.opts.seed,
+		ShuffleSeed: r.opts.shuffle,
+		GitSHA:      r.opts.gitSHA,
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if env.Flags == nil {
+			env.Flags = make(map[string]string)
+		}
+		env.Flags[f.Name] = redactedValue(f.Name, f.Value.String())
+	})
+	for _, v := range redactedEnv(os.Environ()) {
+		name, value, _ := strings.Cut(v, "=")
+		if !r.fingerprinted(name) {
+			continue
+		}
+		if env.Env == nil {
+			env.Env = make(map[string]string)
+		}
+		env.Env[name] = value
+	}
+	for _, name := range gitSHAVariables {
+		if env.GitSHA == "" {
+			env.GitSHA = os.Getenv(name)
+		}
+	}
+	return env
+}
+
+// fingerprinted reports whether the environment variable name is recorded
+// in the environment of the suite's results.
+func (r *suiteRun) fingerprinted(name string) bool {
+	if strings.HasPrefix(name, "GO") || strings.HasPrefix(name, "CGO_") || name == "CI" {
+		return true
+	}
+	for _, n := range r.opts.fingerprintEnv {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebhook is a flag named like a secret, set by the fingerprint test.
+var testWebhook = flag.String("suite-test-webhook", "", "webhook set by TestEnvironmentFingerprint")
+
+func TestEnvironmentFingerprint(t *testing.T) {
+	require.NoError(t, flag.Set("suite-test-webhook", "https://hooks.example.com/services/T0/B0/XXXX"))
+	defer func() { *testWebhook = "" }()
+	t.Setenv("GODEBUG", "gctrace=0")
+	t.Setenv("SUITE_TEST_REGION", "eu-west-1")
+	t.Setenv("SUITE_TEST_IGNORED", "yes")
+	t.Setenv("GOPRIVATE_TOKEN", "hunter2")
+	for _, name := range gitSHAVariables {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GITHUB_SHA", "from-ci")
+
+	recorder := new(recordingReporter)
+	var report bytes.Buffer
+	dir := t.TempDir()
+	runDetachedSuiteWithOutputCapture(new(SuiteReportingTester), WithReporter(recorder), WithShuffle(42), WithSeed(7),
+		WithFingerprintEnv("SUITE_TEST_REGION"), WithReporter(NewJSONReporter(&report)), WithReporter(NewJUnitReporter(dir)))
+
+	require.Len(t, recorder.Suites, 1)
+	env := recorder.Suites[0].Environment
+	assert.Equal(t, runtime.Version(), env.GoVersion)
+	assert.Equal(t, runtime.GOOS, env.OS)
+	assert.Equal(t, runtime.GOARCH, env.Arch)
+	assert.Equal(t, int64(7), env.Seed)
+	if assert.NotNil(t, env.ShuffleSeed) {
+		assert.Equal(t, int64(42), *env.ShuffleSeed)
+	}
+	assert.Equal(t, "gctrace=0", env.Env["GODEBUG"])
+	assert.Equal(t, "eu-west-1", env.Env["SUITE_TEST_REGION"])
+	assert.Equal(t, "<redacted>", env.Env["GOPRIVATE_TOKEN"])
+	assert.NotContains(t, env.Env, "SUITE_TEST_IGNORED")
+	assert.Equal(t, "from-ci", env.GitSHA)
+	assert.Equal(t, "<redacted>", env.Flags["suite-test-webhook"])
+
+	var doc jsonSuite
+	require.NoError(t, json.Unmarshal(report.Bytes(), &doc))
+	assert.Equal(t, "from-ci", doc.Environment.GitSHA)
+
+	junit, err := os.ReadFile(filepath.Join(dir, "TEST-SuiteReportingTester.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<property name="git_sha" value="from-ci"></property>`)
+	assert.Contains(t, string(junit), `<property name="env.SUITE_TEST_REGION" value="eu-west-1"></property>`)
+
+	runDetachedSuiteWithOutputCapture(new(SuiteReportingTester), WithReporter(recorder), WithGitSHA("abc123"))
+	assert.Equal(t, "abc123", recorder.Suites[1].Environment.GitSHA)
+}
@@ -0,0 +1,81 @@
+package suite
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// focusPrefix marks focused test methods: FTestLogin is a focused test,
+// just like TestLogin after a call to Focus("TestLogin").
+const focusPrefix = "F"
+
+// Focus focuses the named test methods of the suite, usually from
+// SetupSuite. Naming a test method with an F in front of its prefix, e.g.
+// FTestLogin, focuses it too. When a suite has focused tests, only those
+// run, which is convenient while working on a few tests of a large suite.
+//
+// Focus is meant for local development only: on CI, detected through the
+// CI environment variable and the variables of common CI systems, a suite
+// with focused tests fails and runs all of its tests, so that focus
+// markers can't be merged unnoticed.
+func (suite *Suite) Focus(names ...string) {
+	suite.focused = append(suite.focused, names...)
+}
+
+// focusPrefixes returns the prefixes of the focused test methods.
+func focusPrefixes(prefixes []string) []string {
+	focused := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		focused[i] = focusPrefix + prefix
+	}
+	return focused
+}
+
+// focus returns the focused methods, or all methods if none is focused or
+// when running on CI, where focus markers fail the suite.
+func (r *suiteRun) focus(methods []reflect.Method) ([]reflect.Method, error) {
+	byName := make(map[string]bool)
+	if b, ok := r.suite.(suiteBase); ok {
+		for _, name := range b.base().focused {
+			if _, ok := reflect.TypeOf(r.suite).MethodByName(name); !ok {
+				return methods, fmt.Errorf("Focus lists %s, which is not a method of the suite", name)
+			}
+			byName[name] = true
+		}
+	}
+	var focused []reflect.Method
+	var names []string
+	for _, method := range methods {
+		if byName[method.Name] || hasAnyPrefix(method.Name, focusPrefixes(r.opts.prefixes)) {
+			focused = append(focused, method)
+			names = append(names, method.Name)
+		}
+	}
+	if len(focused) == 0 {
+		return methods, nil
+	}
+	if onCI() {
+		return methods, fmt.Errorf("focused tests %s must not be run on CI, running all tests", strings.Join(names, ", "))
+	}
+	r.infof(r.suiteT, "running only the focused tests of %s: %s", r.suiteName, strings.Join(names, ", "))
+	return focused, nil
+}
+
+// ciVariables are environment variables set by CI systems that don't set
+// CI.
+var ciVariables = []string{"BUILD_ID", "BUILDKITE", "JENKINS_URL", "TEAMCITY_VERSION", "TF_BUILD"}
+
+// onCI reports whether the tests run on CI.
+func onCI() bool {
+	if ci, ok := os.LookupEnv("CI"); ok {
+		return ci != "false" && ci != "0"
+	}
+	for _, name := range ciVariables {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
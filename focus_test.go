@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteFocusTester struct {
+	Suite
+
+	ran []string
+}
+
+func (s *SuiteFocusTester) SetupSuite() {
+	s.Focus("TestFocused")
+}
+
+func (s *SuiteFocusTester) TestFocused() {
+	s.ran = append(s.ran, "TestFocused")
+}
+
+func (s *SuiteFocusTester) FTestPrefixed() {
+	s.ran = append(s.ran, "FTestPrefixed")
+}
+
+func (s *SuiteFocusTester) TestOther() {
+	s.ran = append(s.ran, "TestOther")
+}
+
+type SuiteUnknownFocusTester struct {
+	Suite
+}
+
+func (s *SuiteUnknownFocusTester) SetupSuite() {
+	s.Focus("TestMissing")
+}
+
+func (s *SuiteUnknownFocusTester) TestOne() {}
+
+func TestFocus(t *testing.T) {
+	t.Setenv("CI", "false")
+	s := new(SuiteFocusTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, []string{"FTestPrefixed", "TestFocused"}, s.ran)
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteUnknownFocusTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "Focus lists TestMissing, which is not a method of the suite")
+}
+
+func TestFocusOnCI(t *testing.T) {
+	t.Setenv("CI", "true")
+	s := new(SuiteFocusTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "focused tests FTestPrefixed, TestFocused must not be run on CI, running all tests")
+	assert.Equal(t, []string{"FTestPrefixed", "TestFocused", "TestOther"}, s.ran)
+}
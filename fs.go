@@ -0,0 +1,44 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// WithFS sets the function creating the filesystem returned by Suite.FS
+// for every test, MemFS by default. Use TempDirFS for tests that need a
+// real filesystem, e.g. to run external programs on the files:
+//
+//	suite.Run(t, new(ImportSuite), suite.WithFS(suite.TempDirFS))
+func WithFS(newFS func(t *testing.T) afero.Fs) Option {
+	return func(o *options) {
+		o.newFS = newFS
+	}
+}
+
+// MemFS returns a new in-memory filesystem.
+func MemFS(t *testing.T) afero.Fs {
+	return afero.NewMemMapFs()
+}
+
+// TempDirFS returns a filesystem rooted at a new temporary directory of
+// t, which is removed once t has finished.
+func TempDirFS(t *testing.T) afero.Fs {
+	return afero.NewBasePathFs(afero.NewOsFs(), t.TempDir())
+}
+
+// FS returns the filesystem of the current test, created by the function
+// set with WithFS on the first call of each test. As every test gets a
+// filesystem of its own, tests using FS instead of the os package are
+// hermetic and can run in parallel.
+func (suite *Suite) FS() afero.Fs {
+	if suite.test.fs == nil {
+		newFS := suite.newFS
+		if newFS == nil {
+			newFS = MemFS
+		}
+		suite.test.fs = newFS(suite.T())
+	}
+	return suite.test.fs
+}
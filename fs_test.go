@@ -0,0 +1,47 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteFSTester struct {
+	Suite
+	files []afero.Fs
+}
+
+func (s *SuiteFSTester) TestWrite() {
+	s.Require().NoError(afero.WriteFile(s.FS(), "/config.yaml", []byte("a: 1"), 0644))
+	s.files = append(s.files, s.FS())
+}
+
+func (s *SuiteFSTester) TestIsolated() {
+	_, err := s.FS().Stat("/config.yaml")
+	s.Assert().True(os.IsNotExist(err))
+	s.files = append(s.files, s.FS())
+}
+
+func TestSuiteFS(t *testing.T) {
+	s := new(SuiteFSTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	require.Len(t, s.files, 2)
+	assert.IsType(t, &afero.MemMapFs{}, s.files[0])
+
+	s = new(SuiteFSTester)
+	ok, output, err = runDetachedSuiteWithOutputCapture(s, WithFS(TempDirFS))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	require.Len(t, s.files, 2)
+	dir, err := s.files[0].(*afero.BasePathFs).RealPath("/")
+	require.NoError(t, err)
+	// The temporary directory is removed after the test.
+	_, err = os.Stat(filepath.Join(dir, "config.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,178 @@
+package suite
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// globalKeyVariable is the environment variable naming the group of test
+// processes sharing the results of global setups.
+const globalKeyVariable = "TESTIFY_GLOBAL_KEY"
+
+// defaultGlobalTimeout is the default of GlobalSetup.Timeout.
+const defaultGlobalTimeout = 10 * time.Minute
+
+// GlobalSetup is setup that runs once for all the test processes of a go
+// test invocation, e.g. for all packages of go test ./... or all shards of
+// a CI job on one machine, and for every -count, such as provisioning a
+// cluster. It is declared at package level and requested by suites,
+// typically in SetupSuite:
+//
+//	var cluster = &suite.GlobalSetup{
+//		Name:  "cluster",
+//		Setup: provisionCluster, // returns the cluster's DSN
+//	}
+//
+//	func (s *ExampleTestSuite) SetupSuite() {
+//		s.dsn = cluster.Get(s)
+//	}
+//
+// The first process requesting the setup runs it, holding a lock file,
+// and writes its result to a state file in the temporary directory, which
+// the other processes read. A failed setup isn't shared: the next process
+// requesting it runs it again. Processes share the state when they have
+// the same TESTIFY_GLOBAL_KEY environment variable, e.g. set to the ID of
+// a CI job, or by default when they have the same parent process, which is
+// the go command for the packages of one invocation. The state of an
+// invocation is removed by the first process of a later one once the go
+// command has exited; the state of a TESTIFY_GLOBAL_KEY is kept, to be
+// removed with the environment it belongs to. Nothing tears the setup
+// down, as no process knows it is the last one: provisioned resources
+// must expire or be cleaned up by a later step.
+type GlobalSetup struct {
+	// Name identifies the setup among those of the group of processes. It
+	// is used in the names of the lock and state files.
+	Name  string
+	Setup func() (string, error)
+	// Timeout is how long to wait for another process running the setup,
+	// 10 minutes by default, before failing. The lock of a process that
+	// crashed is released by the system.
+	Timeout time.Duration
+
+	once  sync.Once
+	value string
+	err   error
+}
+
+// Get returns the result of the setup, running it if no process of the
+// group did yet. A failure of the setup, or of waiting for another process
+// running it, fails the current test of s.
+func (g *GlobalSetup) Get(s TestingSuite) string {
+	g.once.Do(func() {
+		g.value, g.err = g.run(globalStateDir())
+	})
+	if g.err != nil {
+		s.T().Fatalf("suite: global setup %q failed: %v", g.Name, g.err)
+	}
+	return g.value
+}
+
+// globalState is the content of the state file of a global setup.
+type globalState struct {
+	Value string `json:"value"`
+}
+
+// globalStateDir returns the directory of the lock and state files of the
+// group of processes, removing those of the finished invocations of go
+// test when the group is the default one.
+func globalStateDir() string {
+	root := filepath.Join(os.TempDir(), "go-suite-global")
+	if key := os.Getenv(globalKeyVariable); key != "" {
+		return filepath.Join(root, key)
+	}
+	removeFinishedGroups(root)
+	return filepath.Join(root, processGroupKey(os.Getppid()))
+}
+
+// processGroupKey returns the name of the state directory of the processes
+// whose parent is pid. It includes the start time of the parent if known,
+// so that a later process reusing its pid doesn't share the state.
+func processGroupKey(pid int) string {
+	key := "ppid-" + strconv.Itoa(pid)
+	if start := processStartTime(pid); start != "" {
+		key += "-" + start
+	}
+	return key
+}
+
+// removeFinishedGroups removes the state directories in root of the
+// default groups of processes whose parent has exited.
+func removeFinishedGroups(root string) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		rest, ok := strings.CutPrefix(entry.Name(), "ppid-")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.SplitN(rest, "-", 2)[0])
+		if err != nil {
+			continue
+		}
+		if !processAlive(pid) || processGroupKey(pid) != entry.Name() {
+			os.RemoveAll(filepath.Join(root, entry.Name()))
+		}
+	}
+}
+
+// run returns the result of the setup from its state file in dir, running
+// it first if no other process did or does.
+func (g *GlobalSetup) run(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	statePath := filepath.Join(dir, g.Name+".json")
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = defaultGlobalTimeout
+	}
+	if state, ok := readGlobalState(statePath); ok {
+		return state.Value, nil
+	}
+	unlock, err := lockFile(filepath.Join(dir, g.Name+".lock"), timeout)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	// Another process may have finished the setup while this one waited.
+	if state, ok := readGlobalState(statePath); ok {
+		return state.Value, nil
+	}
+	value, err := g.Setup()
+	if err != nil {
+		return "", err
+	}
+	return value, writeGlobalState(statePath, globalState{Value: value})
+}
+
+// readGlobalState reads the state file at path, reporting whether it
+// exists.
+func readGlobalState(path string) (globalState, bool) {
+	var state globalState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, false
+	}
+	return state, json.Unmarshal(data, &state) == nil
+}
+
+// writeGlobalState atomically writes the state file at path.
+func writeGlobalState(path string, state globalState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
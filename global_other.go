@@ -0,0 +1,43 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package suite
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockFile takes an exclusive lock of the file path, waiting up to timeout
+// for the process holding it, and returns the function releasing it. The
+// lock is the existence of the file, which a process that crashes leaves
+// behind.
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %v waiting for another process running the setup, or remove %s if it crashed", timeout, path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// processAlive reports whether the process pid is running, which can't be
+// told on this system.
+func processAlive(pid int) bool {
+	return true
+}
+
+// processStartTime returns the start time of the process pid, which isn't
+// known on this system.
+func processStartTime(pid int) string {
+	return ""
+}
@@ -0,0 +1,98 @@
+package suite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteGlobalSetupTester struct {
+	Suite
+	setup *GlobalSetup
+	value string
+}
+
+func (s *SuiteGlobalSetupTester) SetupSuite() {
+	s.value = s.setup.Get(s)
+}
+
+func (s *SuiteGlobalSetupTester) TestValue() {}
+
+func TestGlobalSetup(t *testing.T) {
+	t.Setenv(globalKeyVariable, t.Name()+"-"+time.Now().Format("150405.000000000"))
+	defer os.RemoveAll(globalStateDir())
+
+	var mu sync.Mutex
+	calls := 0
+	provision := func() (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return "postgres://cluster", nil
+	}
+	// Separate GlobalSetup values stand for the same setup declared by
+	// different test processes.
+	first := new(SuiteGlobalSetupTester)
+	first.setup = &GlobalSetup{Name: "cluster", Setup: provision}
+	second := new(SuiteGlobalSetupTester)
+	second.setup = &GlobalSetup{Name: "cluster", Setup: provision}
+	Run(t, first)
+	Run(t, second)
+	assert.Equal(t, "postgres://cluster", first.value)
+	assert.Equal(t, "postgres://cluster", second.value)
+	assert.Equal(t, 1, calls)
+
+	failing := new(SuiteGlobalSetupTester)
+	failing.setup = &GlobalSetup{Name: "broken", Setup: func() (string, error) { return "", errors.New("no quota") }}
+	ok, output, err := runDetachedSuiteWithOutputCapture(failing)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, `suite: global setup "broken" failed: no quota`)
+
+	// The failure isn't shared: another process runs the setup again.
+	value, err := (&GlobalSetup{Name: "broken", Setup: provision}).run(globalStateDir())
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://cluster", value)
+	assert.Equal(t, 2, calls)
+}
+
+func TestGlobalSetupLock(t *testing.T) {
+	dir := t.TempDir()
+	// A lock file left behind by a crashed process isn't locked.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cluster.lock"), nil, 0600))
+	g := &GlobalSetup{Name: "cluster", Setup: func() (string, error) { return "ok", nil }, Timeout: time.Minute}
+	value, err := g.run(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", value)
+
+	// A lock held by a running setup isn't stolen.
+	unlock, err := lockFile(filepath.Join(dir, "busy.lock"), time.Second)
+	require.NoError(t, err)
+	defer unlock()
+	g = &GlobalSetup{Name: "busy", Setup: func() (string, error) { return "ok", nil }, Timeout: 100 * time.Millisecond}
+	_, err = g.run(dir)
+	assert.ErrorContains(t, err, "timed out after 100ms waiting for another process running the setup")
+}
+
+func TestRemoveFinishedGroups(t *testing.T) {
+	root := t.TempDir()
+	running := processGroupKey(os.Getpid())
+	// No process has this pid, which exceeds the maximum on all systems.
+	finished := "ppid-1073741824"
+	for _, name := range []string{running, finished, "ci-job-42"} {
+		require.NoError(t, os.Mkdir(filepath.Join(root, name), 0700))
+	}
+	removeFinishedGroups(root)
+	assert.DirExists(t, filepath.Join(root, running))
+	assert.DirExists(t, filepath.Join(root, "ci-job-42"))
+	if runtime.GOOS == "linux" {
+		assert.NoDirExists(t, filepath.Join(root, finished))
+	}
+}
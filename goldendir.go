@@ -0,0 +1,229 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoldenDir asserts that the directory tree gotDir, e.g. the output of a
+// code generator, equals the golden tree testdata/<name>: that both have
+// the same files and directories, that files have the same content and
+// that the same files are executable, which is the permission version
+// control keeps. Files and directories whose path relative to the tree,
+// in slash form, or whose name matches one of the ignore patterns (see
+// path.Match) are left out. All differences are reported at once, with a
+// diff of the content of the files. With the -testify.update flag the
+// golden tree is updated to a copy of gotDir instead, keeping the ignored
+// entries of the golden tree, such as a README describing it.
+func (suite *Suite) GoldenDir(name, gotDir string, ignore ...string) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	goldenDir := filepath.Join("testdata", name)
+	got, err := readTree(gotDir, ignore)
+	if err != nil {
+		suite.T().Fatalf("suite: failed to read directory: %v", err)
+	}
+	if *update {
+		if err := writeTree(goldenDir, got, ignore); err != nil {
+			suite.T().Fatalf("suite: failed to update golden directory: %v", err)
+		}
+		return true
+	}
+	golden, err := readTree(goldenDir, ignore)
+	if err != nil {
+		suite.errorf(nil, "Failed to read golden directory, run with -testify.update to create it: %v", err)
+		return false
+	}
+	if differences := compareTrees(golden, got); len(differences) > 0 {
+		suite.errorf(nil, "Directory %s differs from %s, run with -testify.update to update it:\n%s", gotDir, goldenDir, strings.Join(differences, "\n"))
+		return false
+	}
+	return true
+}
+
+// treeEntry is a file or directory of a tree read by readTree.
+type treeEntry struct {
+	dir        bool
+	executable bool
+	content    []byte
+}
+
+// readTree reads the files and directories of the tree root, by their
+// path relative to root in slash form, leaving out those matching ignore.
+func readTree(root string, ignore []string) (map[string]treeEntry, error) {
+	tree := make(map[string]treeEntry)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignored(rel, ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			tree[rel] = treeEntry{dir: true}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		tree[rel] = treeEntry{executable: info.Mode().Perm()&0111 != 0, content: content}
+		return nil
+	})
+	return tree, err
+}
+
+// ignored reports whether the path rel or its name matches one of the
+// patterns.
+func ignored(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compareTrees returns the differences between the trees golden and got,
+// sorted by path.
+func compareTrees(golden, got map[string]treeEntry) []string {
+	paths := make(map[string]bool)
+	for p := range golden {
+		paths[p] = true
+	}
+	for p := range got {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var differences []string
+	for _, p := range sorted {
+		want, inGolden := golden[p]
+		have, inGot := got[p]
+		switch {
+		case !inGot:
+			differences = append(differences, fmt.Sprintf("missing %s %s", kind(want), p))
+		case !inGolden:
+			differences = append(differences, fmt.Sprintf("unexpected %s %s", kind(have), p))
+		case want.dir != have.dir:
+			differences = append(differences, fmt.Sprintf("%s is a %s, expected a %s", p, kind(have), kind(want)))
+		case want.dir:
+		default:
+			if want.executable != have.executable {
+				differences = append(differences, fmt.Sprintf("%s is %s, expected %s", p, executable(have.executable), executable(want.executable)))
+			}
+			if !bytes.Equal(want.content, have.content) {
+				differences = append(differences, fmt.Sprintf("content of %s differs:\n%s", p, diffLines(string(want.content), string(have.content))))
+			}
+		}
+	}
+	return differences
+}
+
+func kind(entry treeEntry) string {
+	if entry.dir {
+		return "directory"
+	}
+	return "file"
+}
+
+func executable(x bool) string {
+	if x {
+		return "executable"
+	}
+	return "not executable"
+}
+
+// writeTree replaces the entries of the directory root that don't match
+// ignore by the tree.
+func writeTree(root string, tree map[string]treeEntry, ignore []string) error {
+	if err := pruneTree(root, tree, ignore); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(tree))
+	for p := range tree {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		entry, target := tree[p], filepath.Join(root, filepath.FromSlash(p))
+		if entry.dir {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		perm := os.FileMode(0644)
+		if entry.executable {
+			perm = 0755
+		}
+		if err := os.WriteFile(target, entry.content, perm); err != nil {
+			return err
+		}
+		if err := os.Chmod(target, perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneTree removes the entries of the directory root that don't match
+// ignore and aren't in the tree, or are of another kind there. Directories
+// left holding ignored entries are kept.
+func pruneTree(root string, tree map[string]treeEntry, ignore []string) error {
+	existing, err := readTree(root, ignore)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(existing))
+	for p := range existing {
+		paths = append(paths, p)
+	}
+	// Entries are removed before the directories holding them.
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	for _, p := range paths {
+		entry, ok := tree[p]
+		if ok && entry.dir == existing[p].dir {
+			continue
+		}
+		err := os.Remove(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil && (ok || !existing[p].dir) {
+			return err
+		}
+	}
+	return nil
+}
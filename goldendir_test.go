@@ -0,0 +1,98 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scaffold writes the tree of testdata/SuiteGoldenDirTester_scaffold into
+// dir, changed by change.
+func scaffold(t *testing.T, dir string, change func(dir string)) {
+	t.Helper()
+	files := map[string]string{
+		"README.md":       "# App\n",
+		"cmd/app/main.go": "package main\n\nfunc main() {}\n",
+		"run.sh":          "#!/bin/sh\ngo run ./cmd/app\n",
+		"docs/.keep":      "",
+		"build.log":       "ignored",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(dir, "run.sh"), 0755))
+	if change != nil {
+		change(dir)
+	}
+}
+
+type SuiteGoldenDirTester struct {
+	Suite
+	change func(dir string)
+}
+
+func (s *SuiteGoldenDirTester) TestScaffold() {
+	dir := s.T().TempDir()
+	scaffold(s.T(), dir, s.change)
+	s.GoldenDir("SuiteGoldenDirTester_scaffold", dir, "*.log")
+}
+
+func TestGoldenDir(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteGoldenDirTester))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(&SuiteGoldenDirTester{change: func(dir string) {
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Application\n"), 0644)
+		os.Chmod(filepath.Join(dir, "run.sh"), 0644)
+		os.RemoveAll(filepath.Join(dir, "docs"))
+		os.WriteFile(filepath.Join(dir, "extra.txt"), nil, 0644)
+	}})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "differs from testdata/SuiteGoldenDirTester_scaffold, run with -testify.update to update it:")
+	assert.Contains(t, output, "content of README.md differs:")
+	assert.Contains(t, output, "+# Application")
+	assert.Contains(t, output, "missing directory docs")
+	assert.Contains(t, output, "missing file docs/.keep")
+	assert.Contains(t, output, "unexpected file extra.txt")
+	assert.Contains(t, output, "run.sh is not executable, expected executable")
+	assert.NotContains(t, output, "build.log")
+}
+
+func TestGoldenDirUpdate(t *testing.T) {
+	defer func(old bool) { *update = old }(*update)
+	*update = true
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+	require.NoError(t, os.MkdirAll(filepath.Join("testdata", "SuiteGoldenDirTester_scaffold", "stale", "logs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("testdata", "SuiteGoldenDirTester_scaffold", "stale.txt"), nil, 0644))
+	// Ignored entries of the golden tree are kept.
+	require.NoError(t, os.WriteFile(filepath.Join("testdata", "SuiteGoldenDirTester_scaffold", "golden.log"), []byte("kept"), 0644))
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteGoldenDirTester))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	golden, err := readTree(filepath.Join("testdata", "SuiteGoldenDirTester_scaffold"), []string{"*.log"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]treeEntry{
+		"README.md":       {content: []byte("# App\n")},
+		"cmd":             {dir: true},
+		"cmd/app":         {dir: true},
+		"cmd/app/main.go": {content: []byte("package main\n\nfunc main() {}\n")},
+		"docs":            {dir: true},
+		"docs/.keep":      {content: []byte{}},
+		"run.sh":          {executable: true, content: []byte("#!/bin/sh\ngo run ./cmd/app\n")},
+	}, golden)
+	kept, err := os.ReadFile(filepath.Join("testdata", "SuiteGoldenDirTester_scaffold", "golden.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "kept", string(kept))
+	assert.NoFileExists(t, filepath.Join("testdata", "SuiteGoldenDirTester_scaffold", "build.log"))
+}
@@ -0,0 +1,122 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var goroutineDump = flag.Bool("testify.goroutine-dump", false, "attach the goroutines running when a testify test fails to its artifacts")
+
+// WithGoroutineDump attaches the stack traces of the goroutines running
+// when a test fails to the test's artifacts (see Suite.ArtifactDir), as
+// goroutines.txt, because failures are often a secondary effect of a
+// stuck background goroutine that is gone by the time the failure is
+// investigated. The goroutines are dumped when the first assertion of the
+// suite fails, or when the test returns if it failed otherwise, e.g.
+// through t.Error.
+//
+// Goroutines running only code of the standard library, such as those of
+// the runtime and the testing package, are left out, as are those whose
+// stack trace contains any of the ignore strings. Dumps are also enabled
+// for all suites by the -testify.goroutine-dump flag.
+func WithGoroutineDump(ignore ...string) Option {
+	return func(o *options) {
+		o.goroutineDump = true
+		o.goroutineDumpIgnore = ignore
+	}
+}
+
+// goroutineDumper dumps the goroutines of failed tests, see
+// WithGoroutineDump.
+type goroutineDumper struct {
+	ignore []string
+}
+
+// dumpGoroutines attaches the goroutines to the artifacts of the current
+// test, unless they have already been attached or dumps are disabled.
+func (suite *Suite) dumpGoroutines() {
+	if suite.goroutineDump == nil || suite.test.goroutinesDumped {
+		return
+	}
+	suite.test.goroutinesDumped = true
+	t := suite.T()
+	dump := suite.goroutineDump.dump()
+	path := filepath.Join(suite.artifactDirFor(t), "goroutines.txt")
+	if err := os.WriteFile(path, []byte(suite.redact(dump)), 0644); err != nil {
+		t.Logf("suite: failed to attach goroutines: %v", err)
+		return
+	}
+	t.Logf("suite: attached the goroutines running at the time of the failure to %s", path)
+}
+
+// dumpGoroutinesOnFailure dumps the goroutines if the test t failed
+// without an assertion of the suite failing.
+func (r *suiteRun) dumpGoroutinesOnFailure(t *testing.T) {
+	if b, ok := r.suite.(suiteBase); ok && t.Failed() {
+		b.base().dumpGoroutines()
+	}
+}
+
+// dump returns the stack traces of the goroutines that aren't filtered
+// out, ordered by ID.
+func (d *goroutineDumper) dump() string {
+	stacks := goroutines()
+	ids := make([]int, 0, len(stacks))
+	for id := range stacks {
+		n, _ := strconv.Atoi(id)
+		ids = append(ids, n)
+	}
+	sort.Ints(ids)
+	var kept []string
+	for _, id := range ids {
+		stack := stacks[strconv.Itoa(id)]
+		if !d.ignored(stack) {
+			kept = append(kept, stack)
+		}
+	}
+	return fmt.Sprintf("%d of %d goroutines:\n\n%s\n", len(kept), len(stacks), strings.Join(kept, "\n\n"))
+}
+
+// ignored reports whether stack is filtered out of dumps.
+func (d *goroutineDumper) ignored(stack string) bool {
+	for _, ignore := range d.ignore {
+		if strings.Contains(stack, ignore) {
+			return true
+		}
+	}
+	for _, line := range strings.Split(stack, "\n")[1:] {
+		if strings.HasPrefix(line, "\t") && !isStdFile(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// isStdFile reports whether the file of a line of a stack trace, e.g.
+// "\t/usr/local/go/src/net/http/server.go:2009 +0x8e", belongs to the
+// standard library, being in the source tree of GOROOT. Unlike import
+// paths, file paths tell apart the standard library from the packages of
+// modules without a dot in their path, e.g. GOPATH packages.
+func isStdFile(line string) bool {
+	file, _, _ := strings.Cut(strings.TrimPrefix(line, "\t"), " +0x")
+	if i := strings.LastIndexByte(file, ':'); i >= 0 {
+		file = file[:i]
+	}
+	return goroot != "" && strings.HasPrefix(filepath.ToSlash(file), goroot)
+}
+
+// goroot is the source tree of the standard library, as it appears in
+// stack traces.
+var goroot = func() string {
+	if runtime.GOROOT() == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Join(runtime.GOROOT(), "src")) + "/"
+}()
@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stuckWorker(started, stop chan struct{}) {
+	close(started)
+	<-stop
+}
+
+type SuiteGoroutineDumpTester struct {
+	Suite
+	stop chan struct{}
+}
+
+func (s *SuiteGoroutineDumpTester) TestAssertionFails() {
+	started := make(chan struct{})
+	go stuckWorker(started, s.stop)
+	<-started
+	s.Equal(1, 2)
+	close(s.stop)
+}
+
+func (s *SuiteGoroutineDumpTester) TestErrorf() {
+	s.T().Error("failed without an assertion")
+}
+
+func (s *SuiteGoroutineDumpTester) TestPasses() {}
+
+func TestGoroutineDump(t *testing.T) {
+	dir := t.TempDir()
+	s := &SuiteGoroutineDumpTester{stop: make(chan struct{})}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithArtifactDir(dir), WithGoroutineDump())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: attached the goroutines running at the time of the failure to ")
+
+	dump, err := os.ReadFile(filepath.Join(dir, "DetachedSuite", "TestAssertionFails", "goroutines.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dump), "go-suite.stuckWorker(")
+	assert.NotContains(t, string(dump), "os/signal.signal_recv")
+	assert.FileExists(t, filepath.Join(dir, "DetachedSuite", "TestErrorf", "goroutines.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "DetachedSuite", "TestPasses", "goroutines.txt"))
+}
+
+func TestGoroutineDumpIgnore(t *testing.T) {
+	d := &goroutineDumper{ignore: []string{"stuckWorker"}}
+	assert.True(t, d.ignored("goroutine 7 [chan receive]:\ngithub.com/mwitkow/go-suite.stuckWorker(0x0)\n\t/src/x.go:1 +0x1"))
+	std := strings.TrimSuffix(goroot, "/")
+	assert.True(t, d.ignored("goroutine 2 [chan receive]:\nos/signal.signal_recv()\n\t"+std+"/runtime/sigqueue.go:152 +0x29\ncreated by os/signal.Notify.func1.1 in goroutine 1\n\t"+std+"/os/signal/signal.go:151 +0x1f"))
+	assert.False(t, d.ignored("goroutine 9 [select]:\nnet/http.(*conn).serve(0xc0)\n\t"+std+"/net/http/server.go:2009\ncreated by example.com/app.Start in goroutine 1\n\t/app/x.go:2"))
+	assert.False(t, d.ignored("goroutine 3 [running]:\nmain.main()\n\t/app/main.go:1"))
+	// Packages without a dot in their import path aren't necessarily in
+	// the standard library.
+	assert.False(t, d.ignored("goroutine 4 [select]:\ninternal/worker.Run()\n\t/home/dev/go/src/internal/worker/run.go:12 +0x1f"))
+}
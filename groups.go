@@ -0,0 +1,73 @@
+package suite
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// GroupedSuite has a Groups method returning the serial groups of its
+// tests: the names of the test methods of each group, keyed by the name of
+// the group. When the tests run in parallel (see WithParallel), the tests
+// of a group, including their per-test hooks, run one at a time, while
+// other tests run in parallel with them. This is useful when only some
+// tests contend for a shared resource:
+//
+//	func (s *StoreSuite) Groups() map[string][]string {
+//		return map[string][]string{
+//			"schema": {"TestMigrate", "TestRollback"},
+//		}
+//	}
+type GroupedSuite interface {
+	Groups() map[string][]string
+}
+
+// serialGroups returns the locks serializing the tests of the suite's
+// groups, keyed by test method name.
+func (r *suiteRun) serialGroups() (map[string]*sync.Mutex, error) {
+	grouped, ok := r.suite.(GroupedSuite)
+	if !ok {
+		return nil, nil
+	}
+	groups := grouped.Groups()
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	locks, owners := make(map[string]*sync.Mutex), make(map[string]string)
+	var problems []string
+	for _, group := range names {
+		lock := &sync.Mutex{}
+		for _, test := range groups[group] {
+			if owner, ok := owners[test]; ok {
+				problems = append(problems, fmt.Sprintf("%s is in groups %q and %q", test, owner, group))
+				continue
+			}
+			if _, ok := reflect.TypeOf(r.suite).MethodByName(test); !ok {
+				problems = append(problems, fmt.Sprintf("group %q lists %s, which is not a method of the suite", group, test))
+				continue
+			}
+			owners[test] = group
+			locks[test] = lock
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid serial groups: %s", strings.Join(problems, "; "))
+	}
+	return locks, nil
+}
+
+// lockGroup waits until no other test of the serial group of the test
+// method name runs, and returns the function ending the test's turn.
+func (r *suiteRun) lockGroup(name string) func() {
+	lock, ok := r.groups[name]
+	if !ok {
+		return func() {}
+	}
+	lock.Lock()
+	return lock.Unlock
+}
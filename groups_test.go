@@ -0,0 +1,81 @@
+package suite
+
+import (
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteGroupsTester struct {
+	Suite
+	mu                     *sync.Mutex
+	running, inGroup       *int
+	maxRunning, maxInGroup *int
+}
+
+func (s *SuiteGroupsTester) SetupSuite() {
+	s.mu = &sync.Mutex{}
+	s.running, s.inGroup, s.maxRunning, s.maxInGroup = new(int), new(int), new(int), new(int)
+}
+
+func (s *SuiteGroupsTester) Groups() map[string][]string {
+	return map[string][]string{"database": {"TestMigrate", "TestRollback", "TestSeed"}}
+}
+
+func (s *SuiteGroupsTester) test(grouped bool) {
+	s.mu.Lock()
+	*s.running++
+	*s.maxRunning = max(*s.maxRunning, *s.running)
+	if grouped {
+		*s.inGroup++
+		*s.maxInGroup = max(*s.maxInGroup, *s.inGroup)
+	}
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	*s.running--
+	if grouped {
+		*s.inGroup--
+	}
+	s.mu.Unlock()
+}
+
+func (s *SuiteGroupsTester) TestMigrate()  { s.test(true) }
+func (s *SuiteGroupsTester) TestRollback() { s.test(true) }
+func (s *SuiteGroupsTester) TestSeed()     { s.test(true) }
+func (s *SuiteGroupsTester) TestAPI()      { s.test(false) }
+func (s *SuiteGroupsTester) TestUI()       { s.test(false) }
+
+type SuiteInvalidGroupsTester struct {
+	Suite
+}
+
+func (s *SuiteInvalidGroupsTester) Groups() map[string][]string {
+	return map[string][]string{
+		"a": {"TestOne", "TestMissing"},
+		"b": {"TestOne"},
+	}
+}
+
+func (s *SuiteInvalidGroupsTester) TestOne() {}
+
+func TestSerialGroups(t *testing.T) {
+	parallel := flag.Lookup("test.parallel").Value
+	defer parallel.Set(parallel.String())
+	parallel.Set("5")
+
+	s := new(SuiteGroupsTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithParallel())
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 1, *s.maxInGroup)
+	assert.Greater(t, *s.maxRunning, 1)
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteInvalidGroupsTester))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, `suite: SuiteInvalidGroupsTester: invalid serial groups: group "a" lists TestMissing, which is not a method of the suite; TestOne is in groups "a" and "b"`)
+}
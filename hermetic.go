@@ -0,0 +1,193 @@
+package suite
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// guards are the network guards of the tests running in hermetic mode, by
+// full test name.
+var guards struct {
+	sync.Mutex
+	byTest map[string]*networkGuard
+}
+
+// installGuardedTransport replaces http.DefaultTransport, once, by a copy
+// guarding the dials of the tests running in hermetic mode.
+var installGuardedTransport = sync.OnceFunc(func() {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return
+	}
+	guarded := transport.Clone()
+	proxy := transport.Proxy
+	guarded.Proxy = func(req *http.Request) (*url.URL, error) {
+		// Requests sent through a proxy would dial the proxy instead of
+		// the guarded address.
+		if proxy == nil || hermeticTests() > 0 {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	guarded.DialContext = guardedDial(dial)
+	http.DefaultTransport = guarded
+})
+
+// networkGuard fails a test dialing addresses that aren't allowed.
+type networkGuard struct {
+	t     *testing.T
+	allow []string
+}
+
+// NetworkInterceptor returns an Interceptor running tests in hermetic mode:
+// a test dialing a network address, other than a loopback address or an
+// address allowed by allow, fails and the dial returns an error. It keeps
+// unit tests from depending on external services by accident.
+//
+// Entries of allow are host names, IP addresses, CIDR ranges or
+// host:port addresses, e.g. "10.0.0.0/8" or "db.internal:5432".
+//
+// Outbound connections are caught when they are made through
+// http.DefaultTransport, e.g. with http.Get, or through Suite.DialContext.
+// Clients created by the tests should dial through Suite.DialContext to be
+// guarded as well:
+//
+//	client := &http.Client{Transport: &http.Transport{DialContext: s.DialContext}}
+//
+// The per-test hooks aren't guarded, so that they can set up fixtures.
+//
+// Hermetic tests can run in parallel, each with its allow-list. A dial of
+// http.DefaultTransport is attributed to the test of its context, e.g.
+// with http.NewRequestWithContext(s.Context(), ...); without one, it must
+// be allowed for all the tests running in hermetic mode, and fails them
+// all if it isn't. http.DefaultTransport doesn't use a proxy while tests
+// run in hermetic mode.
+func NetworkInterceptor(allow ...string) Interceptor {
+	return func(t *testing.T, testName string, next func()) {
+		installGuardedTransport()
+		guard := &networkGuard{t: t, allow: allow}
+		guards.Lock()
+		if guards.byTest == nil {
+			guards.byTest = make(map[string]*networkGuard)
+		}
+		guards.byTest[t.Name()] = guard
+		guards.Unlock()
+		defer func() {
+			guards.Lock()
+			delete(guards.byTest, t.Name())
+			guards.Unlock()
+			if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+				transport.CloseIdleConnections()
+			}
+		}()
+		next()
+	}
+}
+
+// hermeticTests returns the number of tests running in hermetic mode.
+func hermeticTests() int {
+	guards.Lock()
+	defer guards.Unlock()
+	return len(guards.byTest)
+}
+
+// activeGuards returns the guard of the test of ctx, if it runs in
+// hermetic mode, or those of all tests running in hermetic mode if ctx
+// isn't the context of a test.
+func activeGuards(ctx context.Context) []*networkGuard {
+	guards.Lock()
+	defer guards.Unlock()
+	if name, ok := ContextValue(ctx, TestNameKey).(string); ok {
+		if guard, ok := guards.byTest[name]; ok {
+			return []*networkGuard{guard}
+		}
+		return nil
+	}
+	active := make([]*networkGuard, 0, len(guards.byTest))
+	for _, guard := range guards.byTest {
+		active = append(active, guard)
+	}
+	return active
+}
+
+// guardedDial guards dial with the guards of the tests running in
+// hermetic mode, see activeGuards.
+func guardedDial(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		for _, guard := range activeGuards(ctx) {
+			if err := guard.check(network, address); err != nil {
+				return nil, err
+			}
+		}
+		return dial(ctx, network, address)
+	}
+}
+
+// WithHermeticNetwork runs the tests of the suite in hermetic mode, see
+// NetworkInterceptor.
+func WithHermeticNetwork(allow ...string) Option {
+	return WithInterceptor(NetworkInterceptor(allow...))
+}
+
+// DialContext connects to address on network like net.Dialer does,
+// failing the current test instead when it runs in hermetic mode and
+// address isn't allowed, see NetworkInterceptor.
+func (suite *Suite) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	guards.Lock()
+	guard := guards.byTest[suite.T().Name()]
+	guards.Unlock()
+	if guard != nil {
+		if err := guard.check(network, address); err != nil {
+			return nil, err
+		}
+	}
+	return (&net.Dialer{}).DialContext(ctx, network, address)
+}
+
+// check fails the test of the guard and returns an error if dialing
+// address on network isn't allowed.
+func (g *networkGuard) check(network, address string) error {
+	if g.allowed(network, address) {
+		return nil
+	}
+	err := fmt.Errorf("suite: hermetic mode: dialing %s %s is not allowed", network, address)
+	g.t.Error(err)
+	return err
+}
+
+// allowed reports whether dialing address on network is allowed.
+func (g *networkGuard) allowed(network, address string) bool {
+	if strings.HasPrefix(network, "unix") {
+		return true
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+	for _, allowed := range g.allow {
+		if allowed == address || allowed == host {
+			return true
+		}
+		if _, ipNet, err := net.ParseCIDR(allowed); err == nil && ip != nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
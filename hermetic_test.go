@@ -0,0 +1,142 @@
+package suite
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteHermeticTester struct {
+	Suite
+	server *httptest.Server
+}
+
+func (s *SuiteHermeticTester) SetupSuite() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+func (s *SuiteHermeticTester) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *SuiteHermeticTester) TestLoopback() {
+	resp, err := http.Get(s.server.URL)
+	if s.Assert().NoError(err) {
+		resp.Body.Close()
+	}
+}
+
+func (s *SuiteHermeticTester) TestOutbound() {
+	_, err := http.Get("http://example.com/")
+	s.Assert().ErrorContains(err, "hermetic mode")
+}
+
+func (s *SuiteHermeticTester) TestDialer() {
+	_, err := s.DialContext(context.Background(), "tcp", "192.0.2.1:25")
+	s.Assert().Error(err)
+}
+
+func TestWithHermeticNetwork(t *testing.T) {
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteHermeticTester), WithHermeticNetwork("10.0.0.0/8"), WithReporter(recorder))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	statuses := map[string]Status{}
+	for _, result := range recorder.Finished {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, map[string]Status{"TestLoopback": StatusPassed, "TestOutbound": StatusFailed, "TestDialer": StatusFailed}, statuses)
+	assert.Contains(t, output, "suite: hermetic mode: dialing tcp example.com:80 is not allowed")
+	assert.Contains(t, output, "suite: hermetic mode: dialing tcp 192.0.2.1:25 is not allowed")
+	_, isDefault := http.DefaultTransport.(*http.Transport)
+	assert.True(t, isDefault)
+}
+
+func TestNetworkGuardAllowed(t *testing.T) {
+	guard := &networkGuard{allow: []string{"10.0.0.0/8", "db.internal:5432", "cache.internal"}}
+	for address, allowed := range map[string]bool{
+		"127.0.0.1:80":     true,
+		"[::1]:80":         true,
+		"localhost:8080":   true,
+		"10.1.2.3:443":     true,
+		"db.internal:5432": true,
+		"db.internal:5433": false,
+		"cache.internal:1": true,
+		"example.com:443":  false,
+		"192.168.0.1:80":   false,
+	} {
+		assert.Equal(t, allowed, guard.allowed("tcp", address), address)
+	}
+	assert.True(t, guard.allowed("unix", "/tmp/socket"))
+}
+
+type SuiteHermeticContextTester struct {
+	Suite
+}
+
+func (s *SuiteHermeticContextTester) TestOwnGuard() {
+	// The guard of another test, which allows nothing, doesn't apply to the
+	// dials of this test.
+	other := &networkGuard{t: s.T()}
+	guards.Lock()
+	guards.byTest["Other"] = other
+	guards.Unlock()
+	defer func() {
+		guards.Lock()
+		delete(guards.byTest, "Other")
+		guards.Unlock()
+	}()
+	dial := guardedDial(func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, nil
+	})
+	_, err := dial(s.Context(), "tcp", "10.1.2.3:80")
+	s.Assert().NoError(err)
+	_, err = dial(s.Context(), "tcp", "192.0.2.1:80")
+	s.Assert().ErrorContains(err, "suite: hermetic mode: dialing tcp 192.0.2.1:80 is not allowed")
+}
+
+func TestNetworkGuardOfContext(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteHermeticContextTester), WithHermeticNetwork("10.0.0.0/8"))
+	require.NoError(t, err)
+	assert.False(t, ok, "the dial of 192.0.2.1 fails the test")
+	assert.Equal(t, 1, strings.Count(output, "dialing tcp 192.0.2.1:80 is not allowed"), output)
+	assert.NotContains(t, output, "dialing tcp 10.1.2.3:80")
+}
+
+func TestNetworkInterceptorPerTest(t *testing.T) {
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		NetworkInterceptor("10.0.0.0/8")(t, "TestA", func() {
+			// The proxy would be dialed instead of the guarded address.
+			req := httptest.NewRequest("GET", "http://10.1.2.3/", nil)
+			proxy, err := http.DefaultTransport.(*http.Transport).Proxy(req)
+			assert.NoError(t, err)
+			assert.Nil(t, proxy)
+
+			other := &networkGuard{t: t, allow: []string{"192.0.2.0/24"}}
+			guards.Lock()
+			guards.byTest["Other"] = other
+			guards.Unlock()
+			defer func() {
+				guards.Lock()
+				delete(guards.byTest, "Other")
+				guards.Unlock()
+			}()
+			dial := guardedDial(func(ctx context.Context, network, address string) (net.Conn, error) {
+				return nil, nil
+			})
+			// A dial of no test must be allowed for all of them.
+			_, err = dial(context.Background(), "tcp", "192.0.2.1:80")
+			assert.ErrorContains(t, err, "suite: hermetic mode: dialing tcp 192.0.2.1:80 is not allowed")
+		})
+	})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: hermetic mode: dialing tcp 192.0.2.1:80 is not allowed")
+	assert.Zero(t, hermeticTests())
+}
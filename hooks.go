@@ -0,0 +1,153 @@
+package suite
+
+import (
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// hookNames are the methods of the interfaces the runner calls hooks
+// through.
+var hookNames = []string{
+	"SetupSuite", "TearDownSuite", "TearDownSuiteOnFailure", "TearDownSuiteContext",
+	"SetupTest", "TearDownTest", "TearDownTestContext",
+	"BeforeTest", "AfterTest",
+}
+
+// declaresMethod reports whether the method name of typ is declared by typ
+// itself, rather than promoted from one of its embedded fields.
+func declaresMethod(typ reflect.Type, name string) bool {
+	method, ok := typ.MethodByName(name)
+	if !ok {
+		return false
+	}
+	if !isAutogenerated(method.Func) {
+		return true
+	}
+	// Methods with value receivers are wrapped for the pointer type.
+	if typ.Kind() == reflect.Ptr {
+		if method, ok := typ.Elem().MethodByName(name); ok {
+			return !isAutogenerated(method.Func)
+		}
+	}
+	return false
+}
+
+// isAutogenerated reports whether fn is a wrapper generated by the
+// compiler, e.g. for a promoted method.
+func isAutogenerated(fn reflect.Value) bool {
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return false
+	}
+	file, _ := f.FileLine(f.Entry())
+	return file == "<autogenerated>"
+}
+
+// hookOwner returns the type that declares the method name that typ
+// resolves to, following Go's rules for promoting methods of embedded
+// fields. It returns nil if typ has no such method.
+func hookOwner(typ reflect.Type, name string) reflect.Type {
+	if _, ok := typ.MethodByName(name); !ok {
+		return nil
+	}
+	if declaresMethod(typ, name) {
+		return typ
+	}
+	for _, field := range embeddedFields(typ) {
+		if owner := hookOwner(field, name); owner != nil {
+			return owner
+		}
+	}
+	return nil
+}
+
+// embeddedFields returns the types of the embedded fields of the struct
+// typ points to, as pointer types.
+func embeddedFields(typ reflect.Type) []reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		if field.Type.Kind() == reflect.Ptr {
+			fields = append(fields, field.Type)
+		} else {
+			fields = append(fields, reflect.PtrTo(field.Type))
+		}
+	}
+	return fields
+}
+
+// ExtendsHooks is a marker to embed into a suite that extends another
+// suite it embeds:
+//
+//	type FileStoreSuite struct {
+//		storagetest.Suite
+//		suite.ExtendsHooks
+//	}
+//
+// When a suite embedding ExtendsHooks declares a hook that an embedded
+// suite provides as well, the runner calls both instead of only the
+// overriding one: setup hooks and BeforeTest of the embedded suite first,
+// AfterTest and teardown hooks of the embedded suite last. The overriding
+// hook must therefore not call the embedded one itself.
+type ExtendsHooks struct{}
+
+var extendsHooksType = reflect.TypeOf(ExtendsHooks{})
+
+// extendsHooks reports whether the struct typ points to embeds
+// ExtendsHooks.
+func extendsHooks(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous && field.Type == extendsHooksType {
+			return true
+		}
+	}
+	return false
+}
+
+// superHooks returns the embedded suites of target whose hook name is
+// extended rather than replaced by target, outermost last. It returns nil
+// unless target embeds ExtendsHooks and declares the hook itself.
+func superHooks(target interface{}, name string) []interface{} {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || !extendsHooks(v.Type()) || !declaresMethod(v.Type(), name) {
+		return nil
+	}
+	var supers []interface{}
+	elem := v.Elem()
+	for i := 0; i < elem.NumField(); i++ {
+		if !elem.Type().Field(i).Anonymous {
+			continue
+		}
+		field := elem.Field(i)
+		if field.Kind() != reflect.Ptr {
+			field = field.Addr()
+		} else if field.IsNil() {
+			continue
+		}
+		// Embedded suites are often of unexported types, whose values
+		// can't be used through reflection without unsafe.
+		field = reflect.NewAt(field.Type().Elem(), unsafe.Pointer(field.Pointer()))
+		if _, ok := field.Type().MethodByName(name); ok {
+			super := field.Interface()
+			supers = append(supers, superHooks(super, name)...)
+			supers = append(supers, super)
+		}
+	}
+	return supers
+}
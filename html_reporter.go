@@ -0,0 +1,182 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// htmlReporter writes a self-contained HTML report per finished suite.
+type htmlReporter struct {
+	dir string
+}
+
+type htmlSuite struct {
+	Name       string
+	Status     string
+	Duration   string
+	SkipReason string
+	Summary    Summary
+	Tests      []htmlTest
+}
+
+type htmlTest struct {
+	Name       string
+	Status     string
+	Duration   string
+	SkipReason string
+	// SkipCategory is the category of the skip reason, see SkipCategory.
+	SkipCategory string
+	Failures     []string
+	Metadata     string
+	// Artifacts is the path of the artifact directory of the test,
+	// relative to the report, if it has one.
+	Artifacts string
+}
+
+// NewHTMLReporter returns a Reporter writing a self-contained HTML report
+// per suite into dir once the suite has finished, named <suite>.html, or
+// <suite>-N.html for the Nth run of the suite by the test binary. The
+// report lists the tests with their outcome, duration, metadata and the
+// failure messages reported through the suite (see TestResult.Failures),
+// and can be filtered
+// by name and status. When dir is the artifact directory of the suite (see
+// WithArtifactDir), tests link to their artifacts.
+//
+// The reporter is enabled for all suites by the -testify.html-dir flag.
+func NewHTMLReporter(dir string) Reporter {
+	return &htmlReporter{dir: dir}
+}
+
+func (r *htmlReporter) SuiteStarted(suiteName string)          {}
+func (r *htmlReporter) TestStarted(suiteName, testName string) {}
+func (r *htmlReporter) TestFinished(result TestResult)         {}
+
+func (r *htmlReporter) SuiteFinished(result SuiteResult) {
+	doc := htmlSuite{
+		Name:       result.Name,
+		Status:     suiteStatus(result).String(),
+		Duration:   result.Duration.Round(time.Millisecond).String(),
+		SkipReason: result.SkipReason,
+		Summary:    result.Summary(),
+	}
+	for _, test := range result.Tests {
+		t := htmlTest{
+			Name:         test.Name,
+			Status:       test.Status.String(),
+			Duration:     test.Duration.Round(time.Millisecond).String(),
+			SkipReason:   test.SkipReason,
+			SkipCategory: string(test.SkipCategory),
+			Failures:     test.Failures,
+		}
+		if test.Status == StatusFailed && len(t.Failures) == 0 {
+			t.Failures = []string{"test failed, see the output of " + test.FullName}
+		}
+		if !test.Metadata.isZero() {
+			t.Metadata = test.Metadata.String()
+		}
+		if test.FullName != "" {
+			if info, err := os.Stat(testArtifactDir(r.dir, test.FullName)); err == nil && info.IsDir() {
+				t.Artifacts = filepath.ToSlash(test.FullName) + "/"
+			}
+		}
+		doc.Tests = append(doc.Tests, t)
+	}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to render HTML report: %v\n", err)
+		return
+	}
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to write HTML report: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(htmlReportPath(r.dir, result.Name), buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to write HTML report: %v\n", err)
+	}
+}
+
+// htmlReports counts the HTML reports written per path by the process, so
+// that the reports of suites run more than once, e.g. by several tests or
+// with -count, don't overwrite each other.
+var htmlReports = struct {
+	mu    sync.Mutex
+	paths map[string]int
+}{paths: make(map[string]int)}
+
+// htmlReportPath returns the path of the next HTML report of the suite
+// named suiteName in dir: <suite>.html, then <suite>-2.html, and so on.
+func htmlReportPath(dir, suiteName string) string {
+	name := sanitizeName(suiteName)
+	htmlReports.mu.Lock()
+	defer htmlReports.mu.Unlock()
+	path := filepath.Join(dir, name)
+	htmlReports.paths[path]++
+	if n := htmlReports.paths[path]; n > 1 {
+		return fmt.Sprintf("%s-%d.html", path, n)
+	}
+	return path + ".html"
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+pre { margin: 0.3em 0; white-space: pre-wrap; }
+.pass { color: #2a7d2a; }
+.fail { color: #c62828; }
+.skip, .pending { color: #a66f00; }
+.meta { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Name}} <span class="{{.Status}}">{{.Status}}</span></h1>
+<p>{{.Summary.Tests}} tests in {{.Duration}}{{range $status, $n := .Summary.Counts}}, {{$n}} {{$status}}{{end}}{{if .SkipReason}}, skipped: {{.SkipReason}}{{end}}</p>
+<p>
+<input id="filter" type="search" placeholder="Filter tests" oninput="filterTests()">
+<label><input type="checkbox" class="status" value="pass" checked onchange="filterTests()"> pass</label>
+<label><input type="checkbox" class="status" value="fail" checked onchange="filterTests()"> fail</label>
+<label><input type="checkbox" class="status" value="skip" checked onchange="filterTests()"> skip</label>
+<label><input type="checkbox" class="status" value="pending" checked onchange="filterTests()"> pending</label>
+</p>
+<table>
+<thead><tr><th>Test</th><th>Status</th><th>Duration</th><th>Details</th></tr></thead>
+<tbody>
+{{- range .Tests}}
+<tr class="test" data-name="{{.Name}}" data-status="{{.Status}}"{{if .SkipCategory}} data-skip-category="{{.SkipCategory}}"{{end}}>
+<td>{{.Name}}</td>
+<td class="{{.Status}}">{{.Status}}</td>
+<td>{{.Duration}}</td>
+<td>
+{{- if .SkipReason}}{{.SkipReason}}{{end}}{{if .SkipCategory}} <span class="meta">({{.SkipCategory}})</span>{{end}}
+{{- range .Failures}}<pre>{{.}}</pre>{{end}}
+{{- if .Metadata}}<div class="meta">{{.Metadata}}</div>{{end}}
+{{- if .Artifacts}}<a href="{{.Artifacts}}">artifacts</a>{{end -}}
+</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+function filterTests() {
+	var text = document.getElementById("filter").value.toLowerCase();
+	var statuses = {};
+	document.querySelectorAll("input.status").forEach(function (box) { statuses[box.value] = box.checked; });
+	document.querySelectorAll("tr.test").forEach(function (row) {
+		var visible = row.dataset.name.toLowerCase().indexOf(text) >= 0 && statuses[row.dataset.status];
+		row.style.display = visible ? "" : "none";
+	});
+}
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,66 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteHTMLReportTester struct {
+	Suite
+}
+
+func (s *SuiteHTMLReportTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{"TestFails": {Owner: "team-storage"}}
+}
+
+func (s *SuiteHTMLReportTester) TestFails() {
+	require.NoError(s.T(), os.WriteFile(filepath.Join(s.ArtifactDir(), "log.txt"), []byte("log"), 0644))
+	s.Equal("<b>", "<i>")
+}
+
+func (s *SuiteHTMLReportTester) TestPasses() {}
+
+func (s *SuiteHTMLReportTester) TestPanics() {
+	s.Require().Len([]int{1}, 1)
+	panic("boom")
+}
+
+func (s *SuiteHTMLReportTester) TestRequire() {
+	s.Require().Equal(1, 2)
+}
+
+func (s *SuiteHTMLReportTester) TestT() {
+	s.T().Error("plain failure")
+}
+
+func TestHTMLReporter(t *testing.T) {
+	dir := t.TempDir()
+	ok, _, err := runDetachedSuiteWithOutputCapture(new(SuiteHTMLReportTester), WithArtifactDir(dir), WithReporter(NewHTMLReporter(dir)))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	data, err := os.ReadFile(filepath.Join(dir, "SuiteHTMLReportTester.html"))
+	require.NoError(t, err)
+	report := string(data)
+	assert.Contains(t, report, `<h1>SuiteHTMLReportTester <span class="fail">fail</span></h1>`)
+	assert.Regexp(t, `<p>5 tests in \S+, 1 pass, 4 fail</p>`, report)
+	assert.Contains(t, report, `<tr class="test" data-name="TestFails" data-status="fail">`)
+	assert.Contains(t, report, `&#34;&lt;b&gt;&#34;`, "failure messages are escaped")
+	assert.Contains(t, report, `<div class="meta">owner: team-storage</div>`)
+	assert.Contains(t, report, `<a href="DetachedSuite/TestFails/">artifacts</a>`)
+	assert.Contains(t, report, `<tr class="test" data-name="TestPasses" data-status="pass">`)
+	assert.Regexp(t, `<pre>suite: TestPanics at html_reporter_test.go:\d+ panicked: boom`, report)
+	assert.Regexp(t, `<pre>\s*Error Trace:.*\n\s*Error:\s*Not equal`, report)
+	assert.Contains(t, report, `<pre>test failed, see the output of DetachedSuite/TestT</pre>`)
+
+	// The report of another run of the suite doesn't overwrite the first.
+	ok, _, err = runDetachedSuiteWithOutputCapture(new(SuiteHTMLReportTester), WithReporter(NewHTMLReporter(dir)))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.FileExists(t, filepath.Join(dir, "SuiteHTMLReportTester.html"))
+	assert.FileExists(t, filepath.Join(dir, "SuiteHTMLReportTester-2.html"))
+}
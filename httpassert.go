@@ -0,0 +1,242 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// HTTPResponse makes assertions on an HTTP response, see Suite.HTTP. Its
+// methods return the HTTPResponse, so that assertions can be chained. The
+// first failed assertion attaches the request and the response to the
+// artifacts of the test, see Suite.ArtifactDir.
+type HTTPResponse struct {
+	suite    *Suite
+	resp     *http.Response
+	body     []byte
+	readErr  error
+	attached bool
+}
+
+// HTTP returns assertions on resp, reporting their failures to the current
+// test:
+//
+//	s.HTTP(resp).Status(200).HeaderEq("Content-Type", "application/json").BodyJSONPath("$.items", 3)
+//
+// The body of resp is read and closed, and replaced by a reader of its
+// content.
+func (suite *Suite) HTTP(resp *http.Response) *HTTPResponse {
+	h := &HTTPResponse{suite: suite, resp: resp}
+	if resp.Body != nil {
+		h.body, h.readErr = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(h.body))
+	}
+	return h
+}
+
+// Body returns the body of the response.
+func (h *HTTPResponse) Body() []byte {
+	return h.body
+}
+
+// Status asserts that the response has the status code.
+func (h *HTTPResponse) Status(code int) *HTTPResponse {
+	h.suite.T().Helper()
+	h.suite.countAssertion()
+	if h.resp.StatusCode != code {
+		h.errorf("HTTP status is %d %s, expected %d %s", h.resp.StatusCode, http.StatusText(h.resp.StatusCode), code, http.StatusText(code))
+	}
+	return h
+}
+
+// HeaderEq asserts that the header name of the response is value.
+func (h *HTTPResponse) HeaderEq(name, value string) *HTTPResponse {
+	h.suite.T().Helper()
+	h.suite.countAssertion()
+	if values := h.resp.Header.Values(name); len(values) != 1 || values[0] != value {
+		h.errorf("HTTP header %s is %q, expected %q", name, values, value)
+	}
+	return h
+}
+
+// BodyContains asserts that the body of the response contains s.
+func (h *HTTPResponse) BodyContains(s string) *HTTPResponse {
+	h.suite.T().Helper()
+	h.suite.countAssertion()
+	if h.checkBody() && !bytes.Contains(h.body, []byte(s)) {
+		h.errorf("HTTP body doesn't contain %q", s)
+	}
+	return h
+}
+
+// BodyJSONEq asserts that the body of the response is the JSON document
+// expected, as compared by Suite.JSONEq.
+func (h *HTTPResponse) BodyJSONEq(expected string) *HTTPResponse {
+	h.suite.T().Helper()
+	if h.checkBody() && !h.suite.JSONEq(expected, string(h.body)) {
+		h.attach()
+	}
+	return h
+}
+
+// BodyJSONPath asserts that the value at path in the JSON body of the
+// response equals expected, once both are encoded as JSON. When the value
+// is an array or an object and expected is an int, its length is compared
+// instead, e.g. BodyJSONPath("$.items", 3). A path starts with $ for the
+// whole document, followed by object keys, as in .items, and array
+// indexes, as in [0].
+func (h *HTTPResponse) BodyJSONPath(path string, expected interface{}) *HTTPResponse {
+	h.suite.T().Helper()
+	h.suite.countAssertion()
+	if !h.checkBody() {
+		return h
+	}
+	var doc interface{}
+	if err := json.Unmarshal(h.body, &doc); err != nil {
+		h.errorf("HTTP body is not valid JSON: %v", err)
+		return h
+	}
+	actual, err := jsonPath(doc, path)
+	if err != nil {
+		h.errorf("HTTP body has no %s: %v", path, err)
+		return h
+	}
+	if n, ok := expected.(int); ok {
+		switch actual := actual.(type) {
+		case []interface{}:
+			if len(actual) != n {
+				h.errorf("HTTP body has %d elements at %s, expected %d", len(actual), path, n)
+			}
+			return h
+		case map[string]interface{}:
+			if len(actual) != n {
+				h.errorf("HTTP body has %d fields at %s, expected %d", len(actual), path, n)
+			}
+			return h
+		}
+	}
+	var want interface{}
+	data, err := json.Marshal(expected)
+	if err == nil {
+		err = json.Unmarshal(data, &want)
+	}
+	if err != nil {
+		h.errorf("expected value %#v of %s can't be encoded as JSON: %v", expected, path, err)
+		return h
+	}
+	if !reflect.DeepEqual(want, actual) {
+		got, _ := json.Marshal(actual)
+		h.errorf("HTTP body has %s at %s, expected %s", got, path, data)
+	}
+	return h
+}
+
+// checkBody reports whether the body of the response could be read,
+// failing the test if not.
+func (h *HTTPResponse) checkBody() bool {
+	h.suite.T().Helper()
+	if h.readErr != nil {
+		h.errorf("failed to read HTTP body: %v", h.readErr)
+		return false
+	}
+	return true
+}
+
+func (h *HTTPResponse) errorf(format string, args ...interface{}) {
+	h.suite.T().Helper()
+	h.suite.errorf(nil, format, args...)
+	h.attach()
+}
+
+// attach writes the request and the response to the artifacts of the
+// test, once.
+func (h *HTTPResponse) attach() {
+	t := h.suite.T()
+	t.Helper()
+	if h.attached {
+		return
+	}
+	h.attached = true
+	var dump bytes.Buffer
+	if req := h.resp.Request; req != nil {
+		if data, err := httputil.DumpRequest(req, false); err == nil {
+			dump.Write(data)
+			dump.WriteString("\n")
+		}
+	}
+	if data, err := httputil.DumpResponse(h.resp, true); err == nil {
+		dump.Write(data)
+	}
+	dir := h.suite.ArtifactDir()
+	path := filepath.Join(dir, "http-response.txt")
+	for i := 2; fileExists(path); i++ {
+		path = filepath.Join(dir, fmt.Sprintf("http-response-%d.txt", i))
+	}
+	if err := os.WriteFile(path, []byte(h.suite.redact(dump.String())), 0644); err != nil {
+		t.Logf("suite: failed to attach HTTP response: %v", err)
+		return
+	}
+	t.Logf("suite: attached HTTP request and response to %s", path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// jsonPath returns the value at path in the decoded JSON document doc.
+func jsonPath(doc interface{}, path string) (interface{}, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("path %q doesn't start with $", path)
+	}
+	value := doc
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			rest = rest[end+1:]
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("not an object at field %q", key)
+			}
+			if value, ok = object[key]; !ok {
+				return nil, fmt.Errorf("no field %q", key)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in %q", path)
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q", rest[1:end])
+			}
+			rest = rest[end+1:]
+			array, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("not an array at index %d", i)
+			}
+			if i < 0 || i >= len(array) {
+				return nil, fmt.Errorf("index %d out of range of %d elements", i, len(array))
+			}
+			value = array[i]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", rest[0], path)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,89 @@
+package suite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteHTTPTester struct {
+	Suite
+	server *httptest.Server
+}
+
+func (s *SuiteHTTPTester) SetupSuite() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"id": 1}, {"id": 2}, {"id": 3}], "next": null}`))
+	}))
+}
+
+func (s *SuiteHTTPTester) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *SuiteHTTPTester) get() *http.Response {
+	resp, err := http.Get(s.server.URL + "/items")
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *SuiteHTTPTester) TestPasses() {
+	s.HTTP(s.get()).
+		Status(200).
+		HeaderEq("Content-Type", "application/json").
+		BodyJSONPath("$.items", 3).
+		BodyJSONPath("$.items[1].id", 2).
+		BodyJSONPath("$.next", nil).
+		BodyContains(`"id": 3`).
+		BodyJSONEq(`{"next": null, "items": [{"id": 1}, {"id": 2}, {"id": 3}]}`)
+}
+
+func (s *SuiteHTTPTester) TestFails() {
+	s.HTTP(s.get()).
+		Status(201).
+		HeaderEq("Content-Type", "text/plain").
+		BodyJSONPath("$.items", 2).
+		BodyJSONPath("$.items[0].name", "a").
+		BodyJSONPath("$.items[0].id", "1")
+}
+
+func TestHTTPResponse(t *testing.T) {
+	dir := t.TempDir()
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteHTTPTester), WithArtifactDir(dir))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "TestPasses")
+	assert.Contains(t, output, "HTTP status is 200 OK, expected 201 Created")
+	assert.Contains(t, output, `HTTP header Content-Type is ["application/json"], expected "text/plain"`)
+	assert.Contains(t, output, "HTTP body has 3 elements at $.items, expected 2")
+	assert.Contains(t, output, `HTTP body has no $.items[0].name: no field "name"`)
+	assert.Contains(t, output, `HTTP body has 1 at $.items[0].id, expected "1"`)
+
+	path := filepath.Join(dir, "DetachedSuite", "TestFails", "http-response.txt")
+	assert.Contains(t, output, "suite: attached HTTP request and response to "+path)
+	dump, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(dump), "GET /items HTTP/1.1")
+	assert.Contains(t, string(dump), "HTTP/1.1 200 OK")
+	assert.Contains(t, string(dump), `{"items": [{"id": 1}`)
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": "c"}}}
+	value, err := jsonPath(doc, "$.a[0].b")
+	require.NoError(t, err)
+	assert.Equal(t, "c", value)
+	value, err = jsonPath(doc, "$")
+	require.NoError(t, err)
+	assert.Equal(t, doc, value)
+	_, err = jsonPath(doc, "$.a[1]")
+	assert.EqualError(t, err, "index 1 out of range of 1 elements")
+	_, err = jsonPath(doc, "a")
+	assert.EqualError(t, err, `path "a" doesn't start with $`)
+}
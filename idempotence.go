@@ -0,0 +1,70 @@
+package suite
+
+import (
+	"sort"
+	"testing"
+)
+
+// DetectNonIdempotence diagnoses suites that only pass once per process,
+// e.g. because TearDownSuite doesn't reset a fixture or a test leaves
+// behind data that makes it fail the next time.
+//
+// The suite created by newSuite is run three times in a row, as subtests
+// of t: once, again with the same instance, and again with a fresh
+// instance from newSuite. Every test that passes on the first run but
+// fails on a later one is reported as an error:
+//
+//	suite: TestCreate passes on the first run only: it fails when run again on the same suite and on a new suite
+//
+// A test failing again on the same instance only points at state kept in
+// the fields of the suite, while one failing on a new instance too points
+// at state outside of it, such as global variables, files or databases.
+func DetectNonIdempotence(t *testing.T, newSuite func() TestingSuite, opts ...Option) {
+	t.Helper()
+	suite := newSuite()
+	first := runCollecting(t, "first", suite, opts)
+	again := runCollecting(t, "same-suite", suite, opts)
+	fresh := runCollecting(t, "new-suite", newSuite(), opts)
+
+	var names []string
+	for name, status := range first {
+		if status == StatusPassed && (again[name] == StatusFailed || fresh[name] == StatusFailed) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		t.Logf("suite: all tests pass when run again")
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var when string
+		switch {
+		case again[name] == StatusFailed && fresh[name] == StatusFailed:
+			when = "on the same suite and on a new suite"
+		case again[name] == StatusFailed:
+			when = "on the same suite"
+		default:
+			when = "on a new suite"
+		}
+		t.Errorf("suite: %s passes on the first run only: it fails when run again %s", name, when)
+	}
+}
+
+// runCollecting runs suite as the subtest name of t and returns the
+// statuses of its tests.
+func runCollecting(t *testing.T, name string, suite TestingSuite, opts []Option) map[string]Status {
+	t.Helper()
+	collector := &resultCollector{}
+	runOpts := append(append([]Option(nil), opts...), WithReporter(collector))
+	t.Run(name, func(t *testing.T) {
+		Run(t, suite, runOpts...)
+	})
+	statuses := make(map[string]Status)
+	for _, result := range collector.results {
+		for _, test := range result.Tests {
+			statuses[test.Name] = test.Status
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,46 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// idempotenceRegistry is global state that SuiteIdempotenceTester doesn't
+// reset.
+var idempotenceRegistry = map[string]bool{}
+
+type SuiteIdempotenceTester struct {
+	Suite
+	created []string
+}
+
+func (s *SuiteIdempotenceTester) TestStable() {}
+
+func (s *SuiteIdempotenceTester) TestRegister() {
+	assert.False(s.T(), idempotenceRegistry["user"], "already registered")
+	idempotenceRegistry["user"] = true
+}
+
+func (s *SuiteIdempotenceTester) TestCreate() {
+	assert.Empty(s.T(), s.created)
+	s.created = append(s.created, "user")
+}
+
+func TestDetectNonIdempotence(t *testing.T) {
+	idempotenceRegistry = map[string]bool{}
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		DetectNonIdempotence(t, func() TestingSuite { return new(SuiteIdempotenceTester) })
+	})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: TestCreate passes on the first run only: it fails when run again on the same suite\n")
+	assert.Contains(t, output, "suite: TestRegister passes on the first run only: it fails when run again on the same suite and on a new suite\n")
+	assert.NotContains(t, output, "TestStable passes")
+
+	ok, output, err = runDetachedWithOutputCapture(func(t *testing.T) {
+		DetectNonIdempotence(t, func() TestingSuite { return new(SuiteIndependentTester) })
+	})
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+}
@@ -1,6 +1,9 @@
 package suite
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 // TestingSuite can store and return the current *testing.T context
 // generated by 'go test'.
@@ -27,12 +30,37 @@ type TearDownAllSuite interface {
 	TearDownSuite()
 }
 
+// TearDownOnFailureSuite has a TearDownSuiteOnFailure method, which will
+// run after all the tests in the suite have been run, before TearDownSuite,
+// if a test or the suite's setup failed. It is meant for collecting
+// diagnostics that are too expensive to collect on every run, such as the
+// logs of containers or the contents of a database.
+type TearDownOnFailureSuite interface {
+	TearDownSuiteOnFailure()
+}
+
 // TearDownTestSuite has a TearDownTest method, which will run after
 // each test in the suite.
 type TearDownTestSuite interface {
 	TearDownTest()
 }
 
+// TearDownTestContextSuite has a TearDownTestContext method, which will
+// run after each test in the suite, before TearDownTest. Its context
+// expires after the teardown grace period (see WithTeardownGrace), even if
+// the context of the test has already expired, so that the cleanup of
+// remote resources can't hang the suite forever. See Interrupted.
+type TearDownTestContextSuite interface {
+	TearDownTestContext(ctx context.Context)
+}
+
+// TearDownSuiteContextSuite has a TearDownSuiteContext method, which will
+// run after all the tests in the suite have been run, before
+// TearDownSuite, with a context like that of TearDownTestContext.
+type TearDownSuiteContextSuite interface {
+	TearDownSuiteContext(ctx context.Context)
+}
+
 // BeforeTest has a function to be executed right before the test function
 // starts and receives the suite structure and test function names as input
 type BeforeTest interface {
@@ -44,3 +72,26 @@ type BeforeTest interface {
 type AfterTest interface {
 	AfterTest(suiteName, testName string)
 }
+
+// ConditionalSuite has a ShouldRun method, which is called before
+// SetupSuite. When it returns false, the whole suite is skipped with the
+// returned reason, and none of its hooks are run.
+type ConditionalSuite interface {
+	ShouldRun() (bool, string)
+}
+
+// RetryableSetup has a SetupRetryPolicy method returning the policy with
+// which the setup hook named hook, SetupSuite or SetupTest, is retried when
+// it fails, e.g. because a container is slow to start. See RetryPolicy.
+type RetryableSetup interface {
+	SetupRetryPolicy(hook string) RetryPolicy
+}
+
+// PreflightSuite has a Preflight method, which is called before
+// SetupSuite to check that the environment the suite needs is usable, e.g.
+// that a database is reachable. When it returns an error, the whole suite
+// is skipped rather than failed, with the error as its skip reason, so that
+// reports tell a missing environment from broken code.
+type PreflightSuite interface {
+	Preflight() error
+}
@@ -0,0 +1,124 @@
+package suite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// jsonReporter writes a JSON document per finished suite.
+type jsonReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+type jsonSuite struct {
+	Suite        string      `json:"suite"`
+	Status       string      `json:"status"`
+	DurationMS   float64     `json:"duration_ms"`
+	SkipReason   string      `json:"skip_reason,omitempty"`
+	SkipCategory string      `json:"skip_category,omitempty"`
+	Race         bool        `json:"race,omitempty"`
+	Environment  Environment `json:"environment"`
+	Tests        []jsonTest  `json:"tests"`
+}
+
+type jsonTest struct {
+	ID           string    `json:"id,omitempty"`
+	Name         string    `json:"name"`
+	FullName     string    `json:"full_name"`
+	Status       string    `json:"status"`
+	DurationMS   float64   `json:"duration_ms"`
+	UserMS       float64   `json:"cpu_user_ms"`
+	SystemMS     float64   `json:"cpu_system_ms"`
+	SkipReason   string    `json:"skip_reason,omitempty"`
+	SkipCategory string    `json:"skip_category,omitempty"`
+	Assertions   int       `json:"assertions"`
+	Race         bool      `json:"race_detected,omitempty"`
+	Allocs       uint64    `json:"allocs"`
+	AllocBytes   uint64    `json:"alloc_bytes"`
+	Metadata     *Metadata `json:"metadata,omitempty"`
+}
+
+// NewJSONReporter returns a Reporter writing a JSON document per suite to w
+// once the suite has finished, one per line. The documents contain the
+// outcome, duration and metadata of every test, and the stable ID of tests
+// that have one.
+//
+// The reporter is enabled for all suites by the -testify.json flag, which
+// names a file to append the documents to.
+func NewJSONReporter(w io.Writer) Reporter {
+	return &jsonReporter{w: w}
+}
+
+func (r *jsonReporter) SuiteStarted(suiteName string)          {}
+func (r *jsonReporter) TestStarted(suiteName, testName string) {}
+func (r *jsonReporter) TestFinished(result TestResult)         {}
+
+func (r *jsonReporter) SuiteFinished(result SuiteResult) {
+	line, err := json.Marshal(newJSONSuite(result))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to encode JSON report: %v\n", err)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to write JSON report: %v\n", err)
+	}
+}
+
+// newJSONSuite returns the JSON document describing the result of a suite.
+func newJSONSuite(result SuiteResult) jsonSuite {
+	doc := jsonSuite{
+		Suite:        result.Name,
+		Status:       suiteStatus(result).String(),
+		DurationMS:   milliseconds(result.Duration),
+		SkipReason:   result.SkipReason,
+		SkipCategory: string(result.SkipCategory),
+		Race:         result.Race,
+		Environment:  result.Environment,
+		Tests:        []jsonTest{},
+	}
+	for _, test := range result.Tests {
+		t := jsonTest{
+			ID:           test.Metadata.ID,
+			Name:         test.Name,
+			FullName:     test.FullName,
+			Status:       test.Status.String(),
+			DurationMS:   milliseconds(test.Duration),
+			UserMS:       milliseconds(test.CPU.User),
+			SystemMS:     milliseconds(test.CPU.System),
+			SkipReason:   test.SkipReason,
+			SkipCategory: string(test.SkipCategory),
+			Assertions:   test.Assertions,
+			Race:         test.RaceDetected,
+			Allocs:       test.Allocs.Count,
+			AllocBytes:   test.Allocs.Bytes,
+		}
+		if !test.Metadata.isZero() {
+			metadata := test.Metadata
+			t.Metadata = &metadata
+		}
+		doc.Tests = append(doc.Tests, t)
+	}
+	return doc
+}
+
+// suiteStatus summarizes the outcome of a suite.
+func suiteStatus(result SuiteResult) Status {
+	if result.Failed() {
+		return StatusFailed
+	}
+	if result.SkipReason != "" {
+		return StatusSkipped
+	}
+	return StatusPassed
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,165 @@
+package suite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitReporter writes a JUnit XML file per finished suite.
+type junitReporter struct {
+	dir string
+}
+
+type junitTestSuite struct {
+	XMLName    xml.Name        `xml:"testsuite"`
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitMessage   `xml:"failure,omitempty"`
+	Skipped    *junitMessage   `xml:"skipped,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// NewJUnitReporter returns a Reporter writing a JUnit XML report per suite
+// into dir once the suite has finished, named TEST-<suite>.xml as most CI
+// systems expect. The metadata of tests is written as properties of the
+// test cases.
+//
+// The reporter is enabled for all suites by the -testify.junit-dir flag.
+func NewJUnitReporter(dir string) Reporter {
+	return &junitReporter{dir: dir}
+}
+
+func (r *junitReporter) SuiteStarted(suiteName string)          {}
+func (r *junitReporter) TestStarted(suiteName, testName string) {}
+func (r *junitReporter) TestFinished(result TestResult)         {}
+
+func (r *junitReporter) SuiteFinished(result SuiteResult) {
+	doc := junitTestSuite{
+		Name:  result.Name,
+		Tests: len(result.Tests),
+		Time:  seconds(result.Duration),
+	}
+	if result.SkipReason != "" {
+		doc.Properties = append(doc.Properties, junitProperty{Name: "skip_reason", Value: result.SkipReason})
+		doc.Properties = append(doc.Properties, junitProperty{Name: "skip_category", Value: string(result.SkipCategory)})
+	}
+	if result.Race {
+		doc.Properties = append(doc.Properties, junitProperty{Name: "race", Value: "true"})
+	}
+	doc.Properties = append(doc.Properties, environmentProperties(result.Environment)...)
+	for _, test := range result.Tests {
+		testCase := junitTestCase{
+			Name:       test.Name,
+			ClassName:  result.Name,
+			Time:       seconds(test.Duration),
+			Properties: metadataProperties(test.Metadata),
+		}
+		switch test.Status {
+		case StatusFailed:
+			doc.Failures++
+			testCase.Failure = &junitMessage{Message: "test failed, see the output of " + test.FullName}
+			if test.RaceDetected {
+				testCase.Failure.Message = "data race detected, see the output of " + test.FullName
+			}
+		case StatusSkipped, StatusPending:
+			doc.Skipped++
+			testCase.Skipped = &junitMessage{Message: test.SkipReason, Type: string(test.SkipCategory)}
+		}
+		doc.TestCases = append(doc.TestCases, testCase)
+	}
+	if err := r.write(result.Name, doc); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to write JUnit report: %v\n", err)
+	}
+}
+
+func (r *junitReporter) write(suiteName string, doc junitTestSuite) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append([]byte(xml.Header), append(out, '\n')...)
+	return os.WriteFile(filepath.Join(r.dir, "TEST-"+suiteName+".xml"), out, 0644)
+}
+
+func metadataProperties(metadata Metadata) []junitProperty {
+	var properties []junitProperty
+	for _, p := range []junitProperty{
+		{Name: "id", Value: metadata.ID},
+		{Name: "owner", Value: metadata.Owner},
+		{Name: "issue", Value: metadata.Issue},
+		{Name: "description", Value: metadata.Description},
+		{Name: "requirements", Value: strings.Join(metadata.Requirements, ",")},
+		{Name: "tags", Value: strings.Join(metadata.Tags, ",")},
+	} {
+		if p.Value != "" {
+			properties = append(properties, p)
+		}
+	}
+	return properties
+}
+
+func environmentProperties(env Environment) []junitProperty {
+	properties := []junitProperty{
+		{Name: "go_version", Value: env.GoVersion},
+		{Name: "os", Value: env.OS},
+		{Name: "arch", Value: env.Arch},
+		{Name: "seed", Value: strconv.FormatInt(env.Seed, 10)},
+	}
+	if env.ShuffleSeed != nil {
+		properties = append(properties, junitProperty{Name: "shuffle_seed", Value: strconv.FormatInt(*env.ShuffleSeed, 10)})
+	}
+	if env.GitSHA != "" {
+		properties = append(properties, junitProperty{Name: "git_sha", Value: env.GitSHA})
+	}
+	properties = append(properties, mapProperties("flag.", env.Flags)...)
+	return append(properties, mapProperties("env.", env.Env)...)
+}
+
+// mapProperties returns the properties named after the keys of values with
+// prefix, sorted by name.
+func mapProperties(prefix string, values map[string]string) []junitProperty {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	properties := make([]junitProperty, 0, len(names))
+	for _, name := range names {
+		properties = append(properties, junitProperty{Name: prefix + name, Value: values[name]})
+	}
+	return properties
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
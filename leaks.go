@@ -0,0 +1,195 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// LeakDetector detects resources leaked by tests. Snapshot is called before
+// the SetupTest hooks of every test and returns the check of the test,
+// called after its TearDownTest hooks. The check returns an error
+// describing the resources acquired since the snapshot and not released,
+// which fails the test. As the tests of a suite may run in parallel, see
+// WithParallel, detectors keep the snapshot of a test in its check rather
+// than in the detector.
+//
+// Detectors are registered with WithLeakDetector. Custom detectors can
+// track any resource, such as open database connections or temporary
+// files.
+type LeakDetector interface {
+	Snapshot() (check func() error)
+}
+
+// WithLeakDetector runs detectors around every test of the suite.
+func WithLeakDetector(detectors ...LeakDetector) Option {
+	return func(o *options) {
+		o.leakDetectors = append(o.leakDetectors, detectors...)
+	}
+}
+
+// checkLeaks takes a snapshot of all leak detectors. The returned function
+// fails t for every detector reporting a leak.
+func (r *suiteRun) checkLeaks(t *testing.T) func() {
+	checks := make([]func() error, len(r.opts.leakDetectors))
+	for i, detector := range r.opts.leakDetectors {
+		checks[i] = detector.Snapshot()
+	}
+	return func() {
+		for _, check := range checks {
+			if err := check(); err != nil {
+				t.Errorf("suite: leak detected: %v", err)
+			}
+		}
+	}
+}
+
+// goroutineDetector detects goroutines started by a test and still running
+// after it.
+type goroutineDetector struct {
+	ignore []string
+	grace  time.Duration
+}
+
+// GoroutineLeakDetector returns a LeakDetector reporting the goroutines that
+// were started during a test and are still running after it. Goroutines
+// are given a second to exit after the test. Goroutines whose stack trace
+// contains any of the ignore strings, e.g. the name of a function running
+// a background worker, are not reported. The goroutines of the process are
+// compared, so the goroutines started by other tests running at the same
+// time are reported too.
+func GoroutineLeakDetector(ignore ...string) LeakDetector {
+	return &goroutineDetector{ignore: ignore, grace: time.Second}
+}
+
+func (d *goroutineDetector) Snapshot() func() error {
+	before := make(map[string]bool)
+	for id := range goroutines() {
+		before[id] = true
+	}
+	return func() error {
+		deadline := time.Now().Add(d.grace)
+		wait := time.Millisecond
+		for {
+			leaked := d.leaked(before)
+			if len(leaked) == 0 {
+				return nil
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%d goroutines still running:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+			}
+			time.Sleep(wait)
+			if wait < 100*time.Millisecond {
+				wait *= 2
+			}
+		}
+	}
+}
+
+// leaked returns the stack traces of the goroutines that were not running
+// at the time of the snapshot before.
+func (d *goroutineDetector) leaked(before map[string]bool) []string {
+	var leaked []string
+	for id, stack := range goroutines() {
+		if !before[id] && !d.ignored(stack) {
+			leaked = append(leaked, stack)
+		}
+	}
+	sort.Strings(leaked)
+	return leaked
+}
+
+func (d *goroutineDetector) ignored(stack string) bool {
+	for _, ignore := range d.ignore {
+		if strings.Contains(stack, ignore) {
+			return true
+		}
+	}
+	return false
+}
+
+// goroutines returns the stack traces of all goroutines, keyed by their
+// IDs.
+func goroutines() map[string]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	stacks := make(map[string]string)
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		// Every stack starts with a header like "goroutine 7 [running]:".
+		fields := strings.Fields(string(stack))
+		if len(fields) > 1 && fields[0] == "goroutine" {
+			stacks[fields[1]] = string(stack)
+		}
+	}
+	return stacks
+}
+
+// fdDetector detects file descriptors opened by a test and still open after
+// it.
+type fdDetector struct{}
+
+// FDLeakDetector returns a LeakDetector reporting the file descriptors that
+// were opened during a test and are still open after it, with the files
+// they refer to. It is a no-op on systems that don't list the open file
+// descriptors in /proc/self/fd or /dev/fd. The file descriptors of the
+// process are compared, so those opened by other tests running at the same
+// time are reported too.
+func FDLeakDetector() LeakDetector {
+	return &fdDetector{}
+}
+
+func (d *fdDetector) Snapshot() func() error {
+	before := make(map[string]bool)
+	for fd := range openFDs() {
+		before[fd] = true
+	}
+	return func() error {
+		var leaked []string
+		for fd, target := range openFDs() {
+			if !before[fd] {
+				leaked = append(leaked, fmt.Sprintf("%s (%s)", fd, target))
+			}
+		}
+		if len(leaked) == 0 {
+			return nil
+		}
+		sort.Strings(leaked)
+		return fmt.Errorf("%d file descriptors still open: %s", len(leaked), strings.Join(leaked, ", "))
+	}
+}
+
+// openFDs returns the open file descriptors of the process with the files
+// they refer to.
+func openFDs() map[string]string {
+	fds := make(map[string]string)
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			target, err := os.Readlink(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				// The descriptor used to read the directory is already
+				// closed.
+				continue
+			}
+			fds[entry.Name()] = target
+		}
+		break
+	}
+	return fds
+}
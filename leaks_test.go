@@ -0,0 +1,120 @@
+package suite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingDetector struct {
+	calls []string
+	err   error
+}
+
+func (d *recordingDetector) Snapshot() func() error {
+	d.calls = append(d.calls, "Snapshot")
+	return func() error {
+		d.calls = append(d.calls, "Check")
+		return d.err
+	}
+}
+
+type SuiteLeakTester struct {
+	Suite
+	calls    *[]string
+	release  chan struct{}
+	openFile *os.File
+	leakFD   bool
+}
+
+func (s *SuiteLeakTester) SetupTest() {
+	*s.calls = append(*s.calls, "SetupTest")
+}
+
+func (s *SuiteLeakTester) TearDownTest() {
+	*s.calls = append(*s.calls, "TearDownTest")
+}
+
+func (s *SuiteLeakTester) TestLeak() {
+	if s.release != nil {
+		go func() { <-s.release }()
+	}
+	if s.leakFD {
+		f, err := os.Create(filepath.Join(s.T().TempDir(), "leaked"))
+		require.NoError(s.T(), err)
+		s.openFile = f
+	}
+}
+
+func TestLeakDetectorCalls(t *testing.T) {
+	var calls []string
+	detector := &recordingDetector{}
+	s := &SuiteLeakTester{calls: &calls}
+	ok, _, err := runDetachedSuiteWithOutputCapture(s, WithLeakDetector(detector))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"Snapshot", "Check"}, detector.calls)
+	assert.Equal(t, []string{"SetupTest", "TearDownTest"}, calls)
+
+	detector = &recordingDetector{err: errors.New("3 connections still open")}
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteLeakTester{calls: &calls}, WithLeakDetector(detector))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: leak detected: 3 connections still open")
+}
+
+func TestGoroutineLeakDetector(t *testing.T) {
+	var calls []string
+	release := make(chan struct{})
+	defer close(release)
+	detector := &goroutineDetector{grace: 10 * time.Millisecond}
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteLeakTester{calls: &calls, release: release}, WithLeakDetector(detector))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: leak detected: 1 goroutines still running:")
+	assert.Contains(t, output, "SuiteLeakTester).TestLeak.func1")
+
+	detector = &goroutineDetector{grace: 10 * time.Millisecond, ignore: []string{"TestLeak.func1"}}
+	ok, _, err = runDetachedSuiteWithOutputCapture(&SuiteLeakTester{calls: &calls, release: release}, WithLeakDetector(detector))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestFDLeakDetector(t *testing.T) {
+	if _, err := os.ReadDir("/proc/self/fd"); err != nil {
+		t.Skip("open file descriptors can't be listed")
+	}
+	var calls []string
+	s := &SuiteLeakTester{calls: &calls, leakFD: true}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithLeakDetector(FDLeakDetector()))
+	s.openFile.Close()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: leak detected: 1 file descriptors still open: ")
+	assert.Contains(t, output, "leaked)")
+}
+
+type SuiteParallelLeakTester struct {
+	Suite
+}
+
+func (s *SuiteParallelLeakTester) TestA() {
+	time.Sleep(10 * time.Millisecond)
+}
+
+func (s *SuiteParallelLeakTester) TestB() {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestLeakDetectorParallel(t *testing.T) {
+	// Every test checks against its own snapshot.
+	detector := &goroutineDetector{grace: 10 * time.Millisecond}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteParallelLeakTester), WithLeakDetector(detector), WithParallel())
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+}
@@ -0,0 +1,83 @@
+package suite
+
+import (
+	"os"
+	"time"
+)
+
+// Names of the resources locked while a test changes the process-level
+// timezone or locale, see Suite.LockResource.
+const (
+	timezoneResource = "suite:timezone"
+	localeResource   = "suite:locale"
+)
+
+// localeVariables are the environment variables set by WithLocale, in
+// decreasing order of precedence.
+var localeVariables = []string{"LC_ALL", "LANG"}
+
+// WithTimezone sets the local timezone of the process, time.Local and the
+// TZ environment variable inherited by subprocesses, to the IANA timezone
+// name, e.g. "Asia/Tokyo", for the rest of the current test, restoring it
+// when the test finishes.
+//
+// Tests setting the timezone are serialized, like tests locking the same
+// resource. As time.Local is read without synchronization by the time
+// package, WithTimezone fails the test when the suite runs with
+// WithParallel, and must not be used while other tests of the binary run
+// in parallel, e.g. tests calling t.Parallel.
+func (suite *Suite) WithTimezone(name string) {
+	t := suite.T()
+	t.Helper()
+	if suite.parallel {
+		t.Fatalf("suite: WithTimezone can't be used by tests running in parallel, which read time.Local without synchronization")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("suite: failed to set the timezone: %v", err)
+	}
+	suite.LockResource(timezoneResource)
+	local := time.Local
+	restoreEnv := setenv("TZ", name)
+	time.Local = loc
+	t.Cleanup(func() {
+		time.Local = local
+		restoreEnv()
+	})
+}
+
+// WithLocale sets the locale of the process, the LC_ALL and LANG
+// environment variables read by subprocesses and localization libraries,
+// to locale, e.g. "de_DE.UTF-8", for the rest of the current test,
+// restoring it when the test finishes. Tests setting the locale are
+// serialized like tests locking the same resource. Unlike WithTimezone, it
+// can be used by tests running in parallel, which see the locale change.
+func (suite *Suite) WithLocale(locale string) {
+	t := suite.T()
+	t.Helper()
+	suite.LockResource(localeResource)
+	var restores []func()
+	for _, name := range localeVariables {
+		restores = append(restores, setenv(name, locale))
+	}
+	t.Cleanup(func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+	})
+}
+
+// setenv sets the environment variable name to value, returning the
+// function restoring its previous value. Unlike testing.T.Setenv, it can
+// be used in parallel tests, which must synchronize access to name.
+func setenv(name, value string) func() {
+	previous, ok := os.LookupEnv(name)
+	os.Setenv(name, value)
+	return func() {
+		if ok {
+			os.Setenv(name, previous)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package suite
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteLocaleTester struct {
+	Suite
+	zones   []string
+	locales []string
+}
+
+func (s *SuiteLocaleTester) TestA_Tokyo() {
+	s.WithTimezone("Asia/Tokyo")
+	s.WithLocale("ja_JP.UTF-8")
+	s.record()
+	s.Equal("09:00", time.Unix(0, 0).Local().Format("15:04"))
+}
+
+func (s *SuiteLocaleTester) TestB_Restored() {
+	s.record()
+}
+
+func (s *SuiteLocaleTester) TestC_UnknownTimezone() {
+	s.WithTimezone("Nowhere/Atlantis")
+}
+
+func (s *SuiteLocaleTester) record() {
+	s.zones = append(s.zones, time.Local.String()+" "+os.Getenv("TZ"))
+	s.locales = append(s.locales, os.Getenv("LC_ALL")+" "+os.Getenv("LANG"))
+}
+
+func TestWithTimezoneAndLocale(t *testing.T) {
+	t.Setenv("TZ", "UTC")
+	t.Setenv("LANG", "C.UTF-8")
+	t.Setenv("LC_ALL", "")
+	os.Unsetenv("LC_ALL")
+	local := time.Local
+	s := new(SuiteLocaleTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestA_Tokyo")
+	assert.Contains(t, output, "suite: failed to set the timezone: unknown time zone Nowhere/Atlantis")
+	assert.Equal(t, []string{"Asia/Tokyo Asia/Tokyo", local.String() + " UTC"}, s.zones)
+	assert.Equal(t, []string{"ja_JP.UTF-8 ja_JP.UTF-8", " C.UTF-8"}, s.locales)
+	_, ok = os.LookupEnv("LC_ALL")
+	assert.False(t, ok)
+	assert.Equal(t, local, time.Local)
+}
+
+type SuiteParallelTimezoneTester struct {
+	Suite
+}
+
+func (s *SuiteParallelTimezoneTester) TestTokyo() {
+	s.WithTimezone("Asia/Tokyo")
+}
+
+func TestWithTimezoneParallel(t *testing.T) {
+	local := time.Local
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteParallelTimezoneTester), WithParallel())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: WithTimezone can't be used by tests running in parallel")
+	assert.Equal(t, local, time.Local)
+}
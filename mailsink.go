@@ -0,0 +1,267 @@
+package suite
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// defaultMailWait is the default of MailSink.Wait.
+const defaultMailWait = 5 * time.Second
+
+// MailMessage is a message received by a MailSink.
+type MailMessage struct {
+	// From and To are the envelope sender and recipients.
+	From    string
+	To      []string
+	Header  mail.Header
+	Subject string
+	Body    string
+}
+
+// MailSink is an in-memory SMTP server capturing the messages sent to it,
+// see Suite.MailSink.
+type MailSink struct {
+	// Addr is the host:port address of the server.
+	Addr string
+	// Wait is how long ExpectMessageTo waits for a message to arrive, 5
+	// seconds by default.
+	Wait time.Duration
+
+	// suite is the suite of the test using the sink, for its assertions.
+	suite *Suite
+	*mailServer
+}
+
+// mailServer is the state of a MailSink, shared by the tests of a suite
+// running in parallel.
+type mailServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []MailMessage
+	received chan struct{}
+}
+
+// MailSink returns the SMTP server of the suite, starting it on the first
+// call, so that code sending notifications can be tested without a mail
+// server:
+//
+//	func (s *SignupSuite) SetupSuite() {
+//		s.app = NewApp(Config{SMTPAddr: s.MailSink().Addr})
+//	}
+//
+//	func (s *SignupSuite) TestWelcomeMail() {
+//		s.app.SignUp("ada@example.com")
+//		msg := s.MailSink().ExpectMessageTo("ada@example.com")
+//		s.Equal("Welcome!", msg.Subject)
+//	}
+//
+// The server accepts every message without authentication, and is stopped
+// once the suite has finished. The messages are cleared before every
+// test.
+//
+// When the tests run in parallel, see WithParallel, the tests calling
+// MailSink are serialized from their first call until they finish, and the
+// messages are cleared on that call, as the tests would see each other's
+// messages. Tests must call MailSink before the code under test sends
+// messages.
+func (suite *Suite) MailSink() *MailSink {
+	t := suite.T()
+	t.Helper()
+	if suite.mailSink == nil {
+		suite.startMailSink(t)
+	}
+	sink := suite.mailSink
+	if sink.suite != suite {
+		// The test runs in parallel on a copy of the suite, which the
+		// assertions of the sink must use.
+		view := *sink
+		view.suite = suite
+		sink = &view
+	}
+	if suite.lockSharedFixture(&suite.test.mailSinkLocked) {
+		sink.Reset()
+	}
+	return sink
+}
+
+// startMailSink starts the SMTP server of the suite.
+func (suite *Suite) startMailSink(t *testing.T) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("suite: failed to start mail sink: %v", err)
+	}
+	sink := &MailSink{
+		Addr:  listener.Addr().String(),
+		Wait:  defaultMailWait,
+		suite: suite,
+		mailServer: &mailServer{
+			listener: listener,
+			received: make(chan struct{}, 1),
+		},
+	}
+	go sink.serve()
+	owner := suite.suiteT
+	if owner == nil {
+		owner = t
+	}
+	owner.Cleanup(func() {
+		listener.Close()
+		suite.mailSink = nil
+	})
+	suite.BeforeEach(func() {
+		if !suite.parallel {
+			sink.Reset()
+		}
+	})
+	suite.mailSink = sink
+}
+
+// Messages returns the messages received since the current test started.
+func (m *MailSink) Messages() []MailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MailMessage(nil), m.messages...)
+}
+
+// Reset clears the received messages.
+func (m *MailSink) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
+
+// ExpectMessageTo asserts that a message is sent to the recipient addr,
+// waiting for it for up to Wait, and returns the first such message.
+func (m *MailSink) ExpectMessageTo(addr string, msgAndArgs ...interface{}) MailMessage {
+	m.suite.T().Helper()
+	m.suite.countAssertion()
+	ctx := m.suite.Context()
+	timeout := time.NewTimer(m.Wait)
+	defer timeout.Stop()
+	for {
+		for _, msg := range m.Messages() {
+			for _, to := range msg.To {
+				if strings.EqualFold(to, addr) {
+					return msg
+				}
+			}
+		}
+		select {
+		case <-m.received:
+			continue
+		case <-ctx.Done():
+		case <-timeout.C:
+		}
+		var recipients []string
+		for _, msg := range m.Messages() {
+			recipients = append(recipients, msg.To...)
+		}
+		m.suite.errorf(msgAndArgs, "No mail sent to %s, only to %q", addr, recipients)
+		return MailMessage{}
+	}
+}
+
+func (m *mailServer) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.handle(conn)
+	}
+}
+
+// handle serves an SMTP session.
+func (m *mailServer) handle(conn net.Conn) {
+	defer conn.Close()
+	c := textproto.NewConn(conn)
+	reply := func(format string, args ...interface{}) bool {
+		return c.PrintfLine(format, args...) == nil
+	}
+	if !reply("220 go-suite mail sink") {
+		return
+	}
+	var msg MailMessage
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO", "EHLO":
+			reply("250 go-suite")
+		case "MAIL":
+			msg = MailMessage{From: smtpPath(arg, "FROM:")}
+			reply("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, smtpPath(arg, "TO:"))
+			reply("250 OK")
+		case "DATA":
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if err := m.receive(msg, data); err != nil {
+				reply("554 %v", err)
+				continue
+			}
+			reply("250 OK")
+		case "RSET":
+			msg = MailMessage{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 %s not implemented", verb)
+		}
+	}
+}
+
+// receive parses the content of the message msg and keeps it.
+func (m *mailServer) receive(msg MailMessage, data []byte) error {
+	parsed, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid message: %v", err)
+	}
+	body, err := io.ReadAll(parsed.Body)
+	if err != nil {
+		return fmt.Errorf("invalid message: %v", err)
+	}
+	msg.Header = parsed.Header
+	msg.Subject = parsed.Header.Get("Subject")
+	msg.Body = string(body)
+	m.mu.Lock()
+	m.messages = append(m.messages, msg)
+	m.mu.Unlock()
+	select {
+	case m.received <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// smtpPath returns the address of the argument of a MAIL or RCPT command,
+// e.g. "FROM:<ada@example.com> SIZE=42".
+func smtpPath(arg, prefix string) string {
+	if len(arg) >= len(prefix) && strings.EqualFold(arg[:len(prefix)], prefix) {
+		arg = arg[len(prefix):]
+	}
+	arg, _, _ = strings.Cut(strings.TrimSpace(arg), " ")
+	return strings.Trim(arg, "<>")
+}
@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteMailSinkTester struct {
+	Suite
+}
+
+func (s *SuiteMailSinkTester) SetupSuite() {
+	s.MailSink().Wait = 100 * time.Millisecond
+}
+
+func (s *SuiteMailSinkTester) send(to string) {
+	message := "Subject: Welcome!\r\nFrom: app@example.com\r\n\r\nHello " + to + "\r\n"
+	go smtp.SendMail(s.MailSink().Addr, nil, "app@example.com", []string{to}, []byte(message))
+}
+
+func (s *SuiteMailSinkTester) TestFirst() {
+	s.send("ada@example.com")
+	msg := s.MailSink().ExpectMessageTo("ada@example.com")
+	s.Equal("Welcome!", msg.Subject)
+	s.Equal("app@example.com", msg.From)
+	s.Equal("Hello ada@example.com\n", msg.Body)
+	s.Equal("app@example.com", msg.Header.Get("From"))
+}
+
+func (s *SuiteMailSinkTester) TestSecond() {
+	s.Equal(0, len(s.MailSink().Messages()), "messages of earlier tests are cleared")
+	s.send("grace@example.com")
+	s.MailSink().ExpectMessageTo("ada@example.com")
+}
+
+func TestMailSink(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteMailSinkTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestFirst")
+	assert.NotContains(t, output, "messages of earlier tests are cleared")
+	assert.Contains(t, output, "No mail sent to ada@example.com, only to ")
+}
+
+func TestMailSinkParallel(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteMailSinkTester), WithParallel())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestFirst")
+	assert.NotContains(t, output, "messages of earlier tests are cleared")
+	// The failure is reported to the test running in parallel.
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/TestSecond")
+	assert.Contains(t, output, `No mail sent to ada@example.com, only to ["grace@example.com"]`)
+}
+
+func TestSMTPPath(t *testing.T) {
+	assert.Equal(t, "ada@example.com", smtpPath("FROM:<ada@example.com> SIZE=42", "FROM:"))
+	assert.Equal(t, "ada@example.com", smtpPath("to: <ada@example.com>", "TO:"))
+}
@@ -0,0 +1,318 @@
+package suite
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	memoryBaseline  = flag.String("testify.memory-baseline", "", "compare the peak memory usage of testify suites against the baseline in this file")
+	memoryRecord    = flag.Bool("testify.memory-record", false, "record the peak memory usage of testify suites to -testify.memory-baseline instead of comparing it")
+	memoryTolerance = flag.Float64("testify.memory-tolerance", 0.2, "growth of the peak memory usage of a testify suite, relative to its baseline, failing it")
+)
+
+// memorySampling is the interval at which the heap in use is sampled,
+// besides after every test.
+const memorySampling = 100 * time.Millisecond
+
+// MemoryUsage is the peak memory usage of a suite run, measured as the
+// growth over the memory used when the suite started, so that it doesn't
+// depend on the suites that ran before.
+type MemoryUsage struct {
+	// PeakHeapGrowth is the largest growth of the number of bytes in
+	// in-use heap spans sampled while the suite ran, including its setup
+	// and teardown.
+	PeakHeapGrowth uint64 `json:"peak_heap_growth"`
+	// PeakRSSGrowth is the growth of the peak resident set size of the
+	// test process while the suite ran, only measured on Linux.
+	PeakRSSGrowth uint64 `json:"peak_rss_growth,omitempty"`
+}
+
+// MemoryGate compares the peak memory usage of suites against a baseline
+// recorded by an earlier run, failing the suites whose memory usage grew
+// significantly, which long-running integration suites reveal best.
+type MemoryGate struct {
+	// Baseline is the path of the baseline file.
+	Baseline string
+	// Record records the memory usage of the suites to the baseline file
+	// instead of comparing it with it.
+	Record bool
+	// Tolerance is the growth of the peak memory usage beyond which a
+	// suite fails, e.g. 0.2 for 20%.
+	Tolerance float64
+
+	// mu guards the baseline file.
+	mu sync.Mutex
+}
+
+// WithMemoryGate measures the peak memory usage of the suite, reported in
+// SuiteResult.Memory, and compares it against the baseline of gate. The
+// gate is also enabled for all suites by the -testify.memory-baseline
+// flag, configured by -testify.memory-record and
+// -testify.memory-tolerance.
+//
+// The memory usage is that of the whole process, so the measurement
+// includes the memory used by the suites and tests running at the same
+// time, e.g. by the other top-level tests calling t.Parallel, and
+// measuring the peak RSS resets the peak of the process.
+func WithMemoryGate(gate *MemoryGate) Option {
+	return func(o *options) {
+		o.memoryGate = gate
+	}
+}
+
+// memoryGateFromFlags returns the gate enabled on the command line, if
+// any, shared by all suites so that they update the baseline file in turn.
+var memoryGateFromFlags = sync.OnceValue(func() *MemoryGate {
+	if *memoryBaseline == "" {
+		return nil
+	}
+	return &MemoryGate{
+		Baseline:  *memoryBaseline,
+		Record:    *memoryRecord,
+		Tolerance: *memoryTolerance,
+	}
+})
+
+// memoryWatch tracks the peak memory usage of a suite.
+type memoryWatch struct {
+	mu    sync.Mutex
+	start uint64
+	peak  uint64
+	// startRSS is the resident set size of the process when the suite
+	// started, if its peak could be reset then.
+	startRSS   uint64
+	resetRSSOK bool
+}
+
+// sample samples the heap in use, which, unlike runtime.ReadMemStats,
+// doesn't stop the world.
+func (w *memoryWatch) sample() {
+	if w == nil {
+		return
+	}
+	inuse := heapInUse()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.peak = max(w.peak, inuse)
+}
+
+// usage returns the memory usage of the suite so far.
+func (w *memoryWatch) usage() MemoryUsage {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	usage := MemoryUsage{PeakHeapGrowth: growth(w.start, w.peak)}
+	if w.resetRSSOK {
+		usage.PeakRSSGrowth = growth(w.startRSS, peakRSS())
+	}
+	return usage
+}
+
+// growth returns the growth from before to after, or 0 if it shrank.
+func growth(before, after uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
+
+// heapMetrics are the metrics of runtime/metrics whose sum is the number
+// of bytes in in-use heap spans, HeapInuse in runtime.MemStats.
+var heapMetrics = []string{"/memory/classes/heap/objects:bytes", "/memory/classes/heap/unused:bytes"}
+
+// heapInUse samples the heap in use, readHeapInUse but in tests.
+var heapInUse = readHeapInUse
+
+// readHeapInUse returns the number of bytes in in-use heap spans.
+func readHeapInUse() uint64 {
+	samples := make([]metrics.Sample, len(heapMetrics))
+	for i, name := range heapMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	var inuse uint64
+	for _, sample := range samples {
+		if sample.Value.Kind() == metrics.KindUint64 {
+			inuse += sample.Value.Uint64()
+		}
+	}
+	return inuse
+}
+
+// watchMemory samples the heap in use until the returned function is
+// called, which records the peak memory usage of the suite and compares it
+// against the baseline of the memory gate.
+func (r *suiteRun) watchMemory() func() {
+	gate := r.opts.memoryGate
+	if gate == nil {
+		return func() {}
+	}
+	w := &memoryWatch{start: heapInUse()}
+	w.peak = w.start
+	w.startRSS, w.resetRSSOK = resetPeakRSS()
+	r.memory = w
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(memorySampling)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				w.sample()
+				return
+			case <-ticker.C:
+				w.sample()
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+		r.result.Memory = w.usage()
+		if gate.Record {
+			if err := gate.record(r.suiteName, r.result.Memory); err != nil {
+				r.suiteT.Errorf("suite: failed to record the memory usage of %s: %v", r.suiteName, err)
+			}
+			return
+		}
+		if err := gate.compare(r.suiteName, r.result.Memory); err != nil {
+			r.suiteT.Errorf("suite: %s: %v", r.suiteName, err)
+		}
+	}
+}
+
+// load reads the baseline file, which maps the names of suites to their
+// memory usage.
+func (g *MemoryGate) load() (map[string]MemoryUsage, error) {
+	baseline := make(map[string]MemoryUsage)
+	data, err := os.ReadFile(g.Baseline)
+	if errors.Is(err, fs.ErrNotExist) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %v", g.Baseline, err)
+	}
+	return baseline, nil
+}
+
+// record sets the baseline of the suite.
+func (g *MemoryGate) record(suite string, usage MemoryUsage) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	baseline, err := g.load()
+	if err != nil {
+		return err
+	}
+	baseline[suite] = usage
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(g.Baseline, append(data, '\n'), 0644)
+}
+
+// compare returns an error if the memory usage of the suite grew beyond
+// the tolerance from its baseline.
+func (g *MemoryGate) compare(suite string, usage MemoryUsage) error {
+	g.mu.Lock()
+	baseline, err := g.load()
+	g.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	old, ok := baseline[suite]
+	if !ok {
+		return nil
+	}
+	var grown []string
+	for _, m := range []struct {
+		name     string
+		old, new uint64
+	}{
+		{"peak heap growth", old.PeakHeapGrowth, usage.PeakHeapGrowth},
+		{"peak RSS growth", old.PeakRSSGrowth, usage.PeakRSSGrowth},
+	} {
+		if m.old == 0 || m.new == 0 {
+			continue
+		}
+		if growth := float64(m.new)/float64(m.old) - 1; growth > g.Tolerance {
+			grown = append(grown, fmt.Sprintf("%s of %s exceeds the baseline of %s (%+.0f%%)", m.name, formatBytes(m.new), formatBytes(m.old), growth*100))
+		}
+	}
+	if len(grown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s, beyond the tolerance of %.0f%%", strings.Join(grown, ", "), g.Tolerance*100)
+}
+
+// peakRSS returns the peak resident set size of the process, or 0 if it
+// isn't known.
+func peakRSS() uint64 {
+	return procStatus("VmHWM:")
+}
+
+// resetPeakRSS resets the peak resident set size of the process to its
+// current resident set size, which it returns, reporting whether it could.
+func resetPeakRSS() (uint64, bool) {
+	if err := os.WriteFile("/proc/self/clear_refs", []byte("5"), 0); err != nil {
+		return 0, false
+	}
+	rss := procStatus("VmRSS:")
+	return rss, rss > 0
+}
+
+// procStatus returns the size field of /proc/self/status, in bytes, or 0
+// if it isn't known.
+func procStatus(field string) uint64 {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	return statusSize(bufio.NewScanner(f), field)
+}
+
+// statusSize returns the size field, in bytes, of the /proc/<pid>/status
+// of a process that is scanned.
+func statusSize(status *bufio.Scanner, field string) uint64 {
+	for status.Scan() {
+		value, ok := strings.CutPrefix(status.Text(), field)
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
+
+// formatBytes formats a number of bytes in binary units, e.g. "12.5MiB".
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
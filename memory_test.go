@@ -0,0 +1,85 @@
+package suite
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var memoryBallast []byte
+
+// fakeHeap is the heap in use sampled by the memory gate while
+// TestMemoryGate runs, grown by the allocations of SuiteMemoryTester, so
+// that its measures don't depend on the garbage collector.
+var fakeHeap atomic.Uint64
+
+type SuiteMemoryTester struct {
+	Suite
+	allocate int
+}
+
+func (s *SuiteMemoryTester) TestAllocate() {
+	memoryBallast = make([]byte, s.allocate)
+	for i := range memoryBallast {
+		memoryBallast[i] = 1
+	}
+	fakeHeap.Add(uint64(s.allocate))
+}
+
+func (s *SuiteMemoryTester) TearDownSuite() {
+	memoryBallast = nil
+	fakeHeap.Store(0)
+}
+
+func TestMemoryGate(t *testing.T) {
+	heapInUse = fakeHeap.Load
+	defer func() { heapInUse = readHeapInUse }()
+	path := filepath.Join(t.TempDir(), "memory.json")
+	record := &MemoryGate{Baseline: path, Record: true}
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuiteMemoryTester{allocate: 1 << 20}, WithMemoryGate(record))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	baseline, err := record.load()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1<<20), baseline["SuiteMemoryTester"].PeakHeapGrowth)
+
+	gate := &MemoryGate{Baseline: path, Tolerance: 0.2}
+	ok, output, err = runDetachedSuiteWithOutputCapture(&SuiteMemoryTester{allocate: 64 << 20}, WithMemoryGate(gate))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	// The peak RSS growth may exceed its baseline as well as the peak heap
+	// growth, depending on what the process freed before.
+	assert.Regexp(t, `suite: SuiteMemoryTester: peak (heap|RSS) growth of \S+ exceeds the baseline of \S+ \(\+\d+%\).*, beyond the tolerance of 20%`, output)
+	assert.Contains(t, output, "peak heap growth of 64.0MiB exceeds the baseline of 1.0MiB (+6300%)")
+}
+
+func TestStatusSize(t *testing.T) {
+	status := "Name:\tsuite.test\nVmPeak:\t  812344 kB\nVmHWM:\t   20480 kB\nVmRSS:\t   18000 kB\n"
+	assert.Equal(t, uint64(20480*1024), statusSize(bufio.NewScanner(strings.NewReader(status)), "VmHWM:"))
+	assert.Equal(t, uint64(18000*1024), statusSize(bufio.NewScanner(strings.NewReader(status)), "VmRSS:"))
+	assert.Zero(t, statusSize(bufio.NewScanner(strings.NewReader("Name:\tsuite.test\n")), "VmHWM:"))
+}
+
+func TestMemoryUsageIsRelative(t *testing.T) {
+	// Memory used before the suite started isn't counted.
+	memoryBallast = make([]byte, 64<<20)
+	defer func() { memoryBallast = nil }()
+	recorder := new(recordingReporter)
+	gate := &MemoryGate{Baseline: filepath.Join(t.TempDir(), "memory.json"), Record: true}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteMemoryTester), WithMemoryGate(gate), WithReporter(recorder))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	require.Len(t, recorder.Suites, 1)
+	assert.Less(t, recorder.Suites[0].Memory.PeakHeapGrowth, uint64(32<<20))
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512B", formatBytes(512))
+	assert.Equal(t, "1.5KiB", formatBytes(1536))
+	assert.Equal(t, "256.0MiB", formatBytes(256<<20))
+}
@@ -0,0 +1,129 @@
+package suite
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Metadata describes a test, for the benefit of whoever has to deal with
+// it failing. It is passed to reporters and logged when the test fails.
+type Metadata struct {
+	// ID identifies the test independently of its name, so that external
+	// dashboards and quarantine lists keep working when the test method is
+	// renamed. IDs must be unique within a suite.
+	ID string `json:"id,omitempty"`
+	// Owner is the person or team responsible for the test.
+	Owner string `json:"owner,omitempty"`
+	// Issue references the issue tracking the test or the behavior it
+	// verifies.
+	Issue string `json:"issue,omitempty"`
+	// Description explains what the test verifies.
+	Description string `json:"description,omitempty"`
+	// Requirements lists the IDs of the requirements the test verifies,
+	// see NewTraceabilityReporter.
+	Requirements []string `json:"requirements,omitempty"`
+	// Tags classify the test. The tag norace skips the test when the test
+	// binary is built with -race, e.g. because it is too slow with the race
+	// detector enabled. The tag osthread runs the test on a dedicated OS
+	// thread, see OSThreadSuite.
+	Tags []string `json:"tags,omitempty"`
+	// Faults are injected into the fixtures of the suite while the test
+	// runs, see FaultInjector.
+	Faults *Faults `json:"faults,omitempty"`
+}
+
+func (m Metadata) String() string {
+	var parts []string
+	if m.ID != "" {
+		parts = append(parts, "id: "+m.ID)
+	}
+	if m.Owner != "" {
+		parts = append(parts, "owner: "+m.Owner)
+	}
+	if m.Issue != "" {
+		parts = append(parts, "issue: "+m.Issue)
+	}
+	if m.Description != "" {
+		parts = append(parts, "description: "+m.Description)
+	}
+	if len(m.Requirements) > 0 {
+		parts = append(parts, "requirements: "+strings.Join(m.Requirements, " "))
+	}
+	if len(m.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(m.Tags, " "))
+	}
+	if m.Faults != nil {
+		parts = append(parts, "faults: "+m.Faults.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hasTag reports whether the test is tagged with tag.
+func (m Metadata) hasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isZero reports whether no metadata is set.
+func (m Metadata) isZero() bool {
+	return m.String() == ""
+}
+
+// MetadataSuite has a Metadata method returning the metadata of its tests,
+// keyed by the name of the test method.
+type MetadataSuite interface {
+	Metadata() map[string]Metadata
+}
+
+// Describe sets the metadata of the test method named test, overriding
+// metadata returned by the suite's Metadata method. It is typically called
+// in SetupSuite.
+func (suite *Suite) Describe(test string, metadata Metadata) {
+	if suite.metadata == nil {
+		suite.metadata = make(map[string]Metadata)
+	}
+	suite.metadata[test] = metadata
+}
+
+// metadata returns the metadata of the test method name.
+func (r *suiteRun) metadata(name string) Metadata {
+	if b, ok := r.suite.(suiteBase); ok {
+		if metadata, ok := b.base().metadata[name]; ok {
+			return metadata
+		}
+	}
+	if s, ok := r.suite.(MetadataSuite); ok {
+		return s.Metadata()[name]
+	}
+	return Metadata{}
+}
+
+// checkIDs returns an error if several test methods of the suite have the
+// same ID.
+func (r *suiteRun) checkIDs() error {
+	methods := make(map[string][]string)
+	typ := reflect.TypeOf(r.suite)
+	for i := 0; i < typ.NumMethod(); i++ {
+		name := typ.Method(i).Name
+		if id := r.metadata(name).ID; id != "" {
+			methods[id] = append(methods[id], name)
+		}
+	}
+	var duplicates []string
+	for id, names := range methods {
+		if len(names) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%q is used by %s", id, strings.Join(names, ", ")))
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	sort.Strings(duplicates)
+	return fmt.Errorf("duplicate test IDs: %s", strings.Join(duplicates, "; "))
+}
@@ -0,0 +1,85 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteMetadataTester struct {
+	Suite
+}
+
+func (s *SuiteMetadataTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{
+		"TestFail": {Owner: "team-a", Description: "overridden by Describe"},
+		"TestSkip": {Owner: "team-b"},
+	}
+}
+
+func (s *SuiteMetadataTester) SetupSuite() {
+	s.Describe("TestFail", Metadata{ID: "metadata-1", Owner: "team-c", Issue: "BUG-1"})
+}
+
+func (s *SuiteMetadataTester) TestFail() {
+	s.T().Fail()
+}
+
+func (s *SuiteMetadataTester) TestPass() {}
+
+func (s *SuiteMetadataTester) TestSkip() {
+	s.SkipUnlessEnv("SUITE_TEST_UNSET_VARIABLE")
+}
+
+func TestSuiteMetadataReporters(t *testing.T) {
+	var report bytes.Buffer
+	dir := t.TempDir()
+	_, output, err := runDetachedSuiteWithOutputCapture(new(SuiteMetadataTester), WithReporter(NewJSONReporter(&report)), WithReporter(NewJUnitReporter(dir)))
+	require.NoError(t, err)
+	assert.Contains(t, output, "suite: failed test metadata: id: metadata-1, owner: team-c, issue: BUG-1")
+
+	var doc jsonSuite
+	require.NoError(t, json.Unmarshal(report.Bytes(), &doc))
+	assert.Equal(t, "SuiteMetadataTester", doc.Suite)
+	assert.Equal(t, "fail", doc.Status)
+	require.Len(t, doc.Tests, 3)
+	assert.Equal(t, "metadata-1", doc.Tests[0].ID)
+	assert.Equal(t, &Metadata{ID: "metadata-1", Owner: "team-c", Issue: "BUG-1"}, doc.Tests[0].Metadata)
+	assert.Nil(t, doc.Tests[1].Metadata)
+	assert.Equal(t, "skip", doc.Tests[2].Status)
+	assert.Equal(t, "environment variable SUITE_TEST_UNSET_VARIABLE is not set", doc.Tests[2].SkipReason)
+
+	junit, err := os.ReadFile(filepath.Join(dir, "TEST-SuiteMetadataTester.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testsuite name="SuiteMetadataTester" tests="3" failures="1" skipped="1"`)
+	assert.Contains(t, string(junit), `<property name="id" value="metadata-1"></property>`)
+	assert.Contains(t, string(junit), `<property name="issue" value="BUG-1"></property>`)
+	assert.Contains(t, string(junit), `<skipped message="environment variable SUITE_TEST_UNSET_VARIABLE is not set" type="environment"></skipped>`)
+}
+
+type SuiteDuplicateIDTester struct {
+	Suite
+}
+
+func (s *SuiteDuplicateIDTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{
+		"TestOne": {ID: "same"},
+		"TestTwo": {ID: "same"},
+	}
+}
+
+func (s *SuiteDuplicateIDTester) TestOne() {}
+
+func (s *SuiteDuplicateIDTester) TestTwo() {}
+
+func TestSuiteDuplicateIDs(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteDuplicateIDTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, `duplicate test IDs: "same" is used by TestOne, TestTwo`)
+}
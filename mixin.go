@@ -0,0 +1,132 @@
+package suite
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Use registers mixins: helpers whose hooks the runner calls in addition to
+// the suite's own hooks. A mixin implements any of the hook interfaces
+// (SetupTestSuite, TearDownTestSuite, etc.) and, if it implements
+// TestingSuite, receives the current *testing.T along with the suite.
+//
+// Setup hooks and BeforeTest of the mixins are called in registration
+// order, before the suite's own. AfterTest and teardown hooks are called in
+// reverse: first the suite's own, then the mixins' in reverse registration
+// order.
+//
+// Use exists because Go's method promotion only lets one embedded helper
+// provide a hook: when two embedded fields at the same depth both declare
+// SetupTest, neither is promoted and the hook is silently not called. The
+// runner fails such suites. Use is typically called before running the
+// suite:
+//
+//	s := new(ExampleTestSuite)
+//	s.Use(&s.Database, &s.HTTPServer)
+//	suite.Run(t, s)
+func (suite *Suite) Use(mixins ...interface{}) {
+	suite.mixins = append(suite.mixins, mixins...)
+}
+
+// hookTargets returns the values whose hooks are called: the mixins
+// registered with Use in registration order, followed by the suite.
+func (r *suiteRun) hookTargets() []interface{} {
+	var targets []interface{}
+	if b, ok := r.suite.(suiteBase); ok {
+		targets = append(targets, b.base().mixins...)
+	}
+	return append(targets, r.suite)
+}
+
+// callHooks calls the hook name of every hook target for which hook
+// returns a function, in registration order or, for teardown hooks, in
+// reverse. The hooks of embedded suites extended through ExtendsHooks are
+// called along with the extending ones.
+func (r *suiteRun) callHooks(t *testing.T, name string, reverse bool, hook func(target interface{}) func()) {
+	t.Helper()
+	var targets []interface{}
+	for _, target := range r.hookTargets() {
+		targets = append(targets, superHooks(target, name)...)
+		targets = append(targets, target)
+	}
+	if reverse {
+		for i, j := 0, len(targets)-1; i < j; i, j = i+1, j-1 {
+			targets[i], targets[j] = targets[j], targets[i]
+		}
+	}
+	for _, target := range targets {
+		if fn := hook(target); fn != nil {
+			r.callHook(t, typeName(target), name, fn)
+		}
+	}
+}
+
+// setT sets the current *testing.T of the suite and its mixins.
+func (r *suiteRun) setT(t *testing.T) {
+	for _, target := range r.hookTargets() {
+		if s, ok := target.(TestingSuite); ok {
+			s.SetT(t)
+		}
+	}
+}
+
+// typeName returns the name of the type v points to.
+func typeName(v interface{}) string {
+	typ := reflect.TypeOf(v)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Name()
+}
+
+// checkHookResolution returns an error describing hooks that would
+// silently not be called: hooks provided by several embedded fields at the
+// same depth, and hooks of mixins that are also promoted to the suite,
+// which would be called twice.
+func (r *suiteRun) checkHookResolution() error {
+	var problems []string
+	typ := reflect.TypeOf(r.suite)
+	for _, name := range hookNames {
+		if providers := ambiguousProviders(typ, name); len(providers) > 0 {
+			problems = append(problems, fmt.Sprintf("%s is declared by %s at the same depth, so Go promotes none of them; register them with Use instead", name, strings.Join(providers, " and ")))
+		}
+	}
+	for _, mixin := range r.hookTargets()[:len(r.hookTargets())-1] {
+		for _, name := range hookNames {
+			if owner := hookOwner(typ, name); owner != nil && owner == reflect.TypeOf(mixin) {
+				problems = append(problems, fmt.Sprintf("%s of mixin %s is also promoted to %s and would be called twice", name, typeName(mixin), r.suiteName))
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("ambiguous hooks:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// ambiguousProviders returns the names of the embedded fields of typ that
+// all declare the method name at the shallowest depth at which it is
+// declared, if there is more than one of them.
+func ambiguousProviders(typ reflect.Type, name string) []string {
+	if _, ok := typ.MethodByName(name); ok {
+		return nil
+	}
+	level := embeddedFields(typ)
+	for len(level) > 0 {
+		var providers []string
+		var next []reflect.Type
+		for _, field := range level {
+			if declaresMethod(field, name) {
+				providers = append(providers, field.Elem().String())
+			}
+			next = append(next, embeddedFields(field)...)
+		}
+		if len(providers) > 1 {
+			return providers
+		}
+		level = next
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// calls records the order in which hooks are called.
+type calls []string
+
+type databaseMixin struct {
+	Suite
+	calls *calls
+}
+
+func (m *databaseMixin) SetupTest() {
+	*m.calls = append(*m.calls, "database.SetupTest "+m.T().Name())
+}
+
+func (m *databaseMixin) TearDownTest() {
+	*m.calls = append(*m.calls, "database.TearDownTest")
+}
+
+type serverMixin struct {
+	calls *calls
+}
+
+func (m *serverMixin) SetupTest() {
+	*m.calls = append(*m.calls, "server.SetupTest")
+}
+
+func (m *serverMixin) TearDownTest() {
+	*m.calls = append(*m.calls, "server.TearDownTest")
+}
+
+type SuiteMixinTester struct {
+	Suite
+	calls calls
+}
+
+func (s *SuiteMixinTester) SetupTest() {
+	s.calls = append(s.calls, "suite.SetupTest")
+}
+
+func (s *SuiteMixinTester) TearDownTest() {
+	s.calls = append(s.calls, "suite.TearDownTest")
+}
+
+func (s *SuiteMixinTester) TestMixins() {}
+
+// SuiteAmbiguousTester embeds two helpers declaring SetupTest, so Go
+// promotes neither.
+type SuiteAmbiguousTester struct {
+	Suite
+	databaseMixin
+	serverMixin
+}
+
+func (s *SuiteAmbiguousTester) TestNothing() {}
+
+func TestSuiteMixins(t *testing.T) {
+	s := new(SuiteMixinTester)
+	s.Use(&databaseMixin{calls: &s.calls}, &serverMixin{calls: &s.calls})
+	Run(t, s)
+
+	assert.Equal(t, calls{
+		"database.SetupTest TestSuiteMixins/TestMixins",
+		"server.SetupTest",
+		"suite.SetupTest",
+		"suite.TearDownTest",
+		"server.TearDownTest",
+		"database.TearDownTest",
+	}, s.calls)
+}
+
+func TestSuiteAmbiguousHooks(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteAmbiguousTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "SetupTest is declared by suite.databaseMixin and suite.serverMixin at the same depth")
+}
+
+type baseSuite struct {
+	Suite
+	calls calls
+}
+
+func (s *baseSuite) SetupTest() {
+	s.calls = append(s.calls, "base.SetupTest")
+}
+
+func (s *baseSuite) TearDownTest() {
+	s.calls = append(s.calls, "base.TearDownTest")
+}
+
+type SuiteExtendingTester struct {
+	baseSuite
+	ExtendsHooks
+}
+
+func (s *SuiteExtendingTester) SetupTest() {
+	s.calls = append(s.calls, "derived.SetupTest")
+}
+
+func (s *SuiteExtendingTester) TestExtends() {
+	s.calls = append(s.calls, "TestExtends")
+}
+
+func TestSuiteExtendsHooks(t *testing.T) {
+	s := new(SuiteExtendingTester)
+	Run(t, s)
+
+	assert.Equal(t, calls{
+		"base.SetupTest",
+		"derived.SetupTest",
+		"TestExtends",
+		"base.TearDownTest",
+	}, s.calls, "TearDownTest is only promoted, so it must be called once")
+}
@@ -0,0 +1,220 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var detectMutations = flag.Bool("testify.detect-mutations", false, "warn about testify suite tests changing fields of the suite they didn't reset")
+
+// WithMutationDetection snapshots the fields of the suite after
+// SetupSuite and compares them after every test, including what they
+// point to, logging a warning naming the fields a test changed without
+// resetting them. Such fields couple the tests of the suite, which is
+// worth finding before shuffling the tests or running them in parallel.
+// Fields of the embedded Suite aren't compared, nor are the unexported
+// fields of types declared in other packages than the suite's, or what
+// values with their own synchronization point to, such as *sql.DB,
+// *http.Client or structs holding a mutex, which background goroutines
+// may be using.
+//
+// Detection is disabled for parallel suites, as comparing the fields
+// would race with the other tests. It is also enabled for all suites by
+// the -testify.detect-mutations flag.
+func WithMutationDetection() Option {
+	return func(o *options) {
+		o.detectMutations = true
+	}
+}
+
+// maxStateDepth is the depth up to which the state of the suite is
+// compared, following pointers, maps and slices.
+const maxStateDepth = 8
+
+// snapshotState records the state of the fields of the suite, if mutation
+// detection is enabled.
+func (r *suiteRun) snapshotState() {
+	if !r.opts.detectMutations {
+		return
+	}
+	if r.opts.parallel {
+		r.infof(r.suiteT, "mutation detection is disabled, as the tests of %s run in parallel", r.suiteName)
+		return
+	}
+	r.state = suiteState(r.suite)
+}
+
+// checkState logs a warning to t naming the fields of the suite that the
+// test method name changed since the last snapshot, and takes a new one.
+func (r *suiteRun) checkState(t *testing.T, name string) {
+	if r.state == nil {
+		return
+	}
+	state := suiteState(r.suite)
+	var changed []string
+	for name, value := range state {
+		if r.state[name] != value {
+			changed = append(changed, name)
+		}
+	}
+	r.state = state
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		r.logf(t, "suite: warning: %s changed fields of the suite it didn't reset: %s", name, strings.Join(changed, ", "))
+	}
+}
+
+// suiteState returns a description of the value of every field of the
+// suite, except the embedded Suite, by field name.
+func suiteState(suite TestingSuite) map[string]string {
+	v := reflect.ValueOf(suite)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	state := make(map[string]string)
+	if v.Kind() != reflect.Struct {
+		return state
+	}
+	base := reflect.TypeOf(Suite{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type == base || field.Type == reflect.PtrTo(base) {
+			continue
+		}
+		var b strings.Builder
+		describeValue(&b, v.Field(i), v.Type().PkgPath(), make(map[uintptr]bool), 0)
+		state[field.Name] = b.String()
+	}
+	return state
+}
+
+// synchronizedTypes are types with their own synchronization, besides
+// those of package sync and structs holding a mutex.
+var synchronizedTypes = map[string]bool{
+	"database/sql.DB": true,
+	"net/http.Client": true,
+}
+
+// synchronized reports whether values of type t have their own
+// synchronization, so that they may be used by other goroutines while
+// they are described.
+func synchronized(t reflect.Type) bool {
+	if t.PkgPath() == "sync" || t.PkgPath() == "sync/atomic" || synchronizedTypes[t.PkgPath()+"."+t.Name()] {
+		return true
+	}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i).Type; f.PkgPath() == "sync" && (f.Name() == "Mutex" || f.Name() == "RWMutex") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeValue writes a description of v to b, following pointers up to
+// maxStateDepth. Values are read without calling Interface, so that
+// unexported fields of the types of package pkg, the suite's, can be
+// described; those of other packages aren't. Values with their own
+// synchronization are described by their address, or type.
+func describeValue(b *strings.Builder, v reflect.Value, pkg string, seen map[uintptr]bool, depth int) {
+	if depth > maxStateDepth {
+		b.WriteString("…")
+		return
+	}
+	if v.IsValid() && v.Kind() != reflect.Ptr && synchronized(v.Type()) {
+		b.WriteString(v.Type().String())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		b.WriteString("<invalid>")
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		b.WriteString(strconv.FormatComplex(v.Complex(), 'g', -1, 128))
+	case reflect.String:
+		b.WriteString(strconv.Quote(v.String()))
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		fmt.Fprintf(b, "%s(%#x)", v.Kind(), v.Pointer())
+	case reflect.Ptr:
+		if v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		if synchronized(v.Type().Elem()) {
+			fmt.Fprintf(b, "%s(%#x)", v.Type(), v.Pointer())
+			return
+		}
+		if seen[v.Pointer()] {
+			fmt.Fprintf(b, "cycle(%#x)", v.Pointer())
+			return
+		}
+		seen[v.Pointer()] = true
+		defer delete(seen, v.Pointer())
+		b.WriteString("&")
+		describeValue(b, v.Elem(), pkg, seen, depth+1)
+	case reflect.Interface:
+		if v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		b.WriteString(v.Elem().Type().String())
+		b.WriteString(":")
+		describeValue(b, v.Elem(), pkg, seen, depth+1)
+	case reflect.Struct:
+		b.WriteString("{")
+		described := 0
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() && v.Type().PkgPath() != pkg {
+				continue
+			}
+			if described > 0 {
+				b.WriteString(", ")
+			}
+			describeValue(b, v.Field(i), pkg, seen, depth+1)
+			described++
+		}
+		b.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		b.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			describeValue(b, v.Index(i), pkg, seen, depth+1)
+		}
+		b.WriteString("]")
+	case reflect.Map:
+		if v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			describeValue(&entry, iter.Key(), pkg, seen, depth+1)
+			entry.WriteString(": ")
+			describeValue(&entry, iter.Value(), pkg, seen, depth+1)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		b.WriteString("map[" + strings.Join(entries, ", ") + "]")
+	}
+}
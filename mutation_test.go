@@ -0,0 +1,92 @@
+package suite
+
+import (
+	"database/sql"
+	"flag"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mutationCache struct {
+	entries map[string]int
+}
+
+type SuiteMutationTester struct {
+	Suite
+
+	cache   *mutationCache
+	counter int
+	scratch []string
+}
+
+func (s *SuiteMutationTester) SetupSuite() {
+	s.cache = &mutationCache{entries: map[string]int{"a": 1}}
+}
+
+func (s *SuiteMutationTester) TearDownTest() {
+	s.scratch = nil
+}
+
+func (s *SuiteMutationTester) TestCache() {
+	s.cache.entries["b"] = 2
+}
+
+func (s *SuiteMutationTester) TestCounter() {
+	s.counter++
+}
+
+func (s *SuiteMutationTester) TestScratch() {
+	s.scratch = append(s.scratch, "reset by TearDownTest")
+}
+
+func TestMutationDetection(t *testing.T) {
+	verbose := flag.Lookup("test.v").Value
+	defer verbose.Set(verbose.String())
+	verbose.Set("true")
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteMutationTester), WithMutationDetection())
+	require.NoError(t, err)
+	assert.True(t, ok, "mutations are only warned about")
+	assert.Contains(t, output, "suite: warning: TestCache changed fields of the suite it didn't reset: cache")
+	assert.Contains(t, output, "suite: warning: TestCounter changed fields of the suite it didn't reset: counter")
+	assert.NotContains(t, output, "TestScratch changed")
+}
+
+type describedNode struct {
+	next *describedNode
+	name string
+}
+
+type SuiteDescribeTester struct {
+	Suite
+	n      *describedNode
+	m      map[int][]bool
+	mu     sync.Mutex
+	db     *sql.DB
+	client *http.Client
+	header *http.Request
+}
+
+func TestDescribeValue(t *testing.T) {
+	cycle := &describedNode{name: "a"}
+	cycle.next = cycle
+	state := suiteState(&SuiteDescribeTester{
+		n:      cycle,
+		m:      map[int][]bool{2: {true}, 1: nil},
+		db:     new(sql.DB),
+		client: new(http.Client),
+		header: &http.Request{Method: "GET"},
+	})
+	assert.Regexp(t, `^&\{cycle\(0x[0-9a-f]+\), "a"\}$`, state["n"])
+	assert.Equal(t, "map[1: nil, 2: [true]]", state["m"])
+	// Values with their own synchronization aren't read.
+	assert.Equal(t, "sync.Mutex", state["mu"])
+	assert.Regexp(t, `^\*sql\.DB\(0x[0-9a-f]+\)$`, state["db"])
+	assert.Regexp(t, `^\*http\.Client\(0x[0-9a-f]+\)$`, state["client"])
+	// Only the exported fields of types of other packages are read.
+	assert.Contains(t, state["header"], `"GET"`)
+}
@@ -0,0 +1,45 @@
+package suite
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Namer returns the name under which a test method is run as a subtest
+// of the test function that called Run.
+type Namer func(suiteName, methodName string) string
+
+// MethodNamer names subtests after the test method alone, e.g.
+// "TestExample". This is the default.
+func MethodNamer(suiteName, methodName string) string {
+	return methodName
+}
+
+// QualifiedNamer names subtests after the suite type and the test method,
+// e.g. "ExampleTestSuite.TestExample", so that tests of different suites
+// run from the same wrapper function are grouped predictably by
+// `go tool test2json` consumers and IDEs.
+func QualifiedNamer(suiteName, methodName string) string {
+	return suiteName + "." + methodName
+}
+
+// WithNamer sets the function used to name the subtests of a suite.
+// Names it returns are sanitized so that they remain usable in
+// `go test -run` patterns.
+func WithNamer(namer Namer) Option {
+	return func(o *options) {
+		o.namer = namer
+	}
+}
+
+// sanitizeName replaces characters that have a special meaning for
+// `go test -run` (the "/" subtest separator) or that the testing package
+// would rewrite itself (spaces, non-printables) with underscores.
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,11 @@
+//go:build !race
+
+package suite
+
+// raceEnabled reports whether the test binary was built with -race.
+const raceEnabled = false
+
+// raceErrors returns the number of data races reported so far.
+func raceErrors() int {
+	return 0
+}
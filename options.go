@@ -0,0 +1,192 @@
+package suite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Option configures how Run executes a suite.
+type Option func(*options)
+
+// options holds the effective configuration of a single Run.
+type options struct {
+	namer     Namer
+	reporters []Reporter
+	verbosity Verbosity
+	seed      int64
+	prefixes  []string
+	// seedOption is set if the seed was set with WithSeed, which the
+	// -testify.seed flag doesn't override.
+	seedOption bool
+
+	leakDetectors []LeakDetector
+	// order lists the names of the test methods to run, in the order to
+	// run them, if set.
+	order []string
+	// only is the name of the single test method to run, see RunOne.
+	only string
+
+	filters      []func(methodName string) bool
+	shuffle      *int64
+	failFast     bool
+	interceptors []Interceptor
+	timeout      time.Duration
+	parallel     bool
+	maxParallel  int
+
+	artifactDir string
+	retention   Retention
+	diagnostics bool
+
+	goroutineDump       bool
+	goroutineDumpIgnore []string
+	secretsDir          string
+	reaperDir           string
+
+	properties []propertyChecker
+
+	warnNoAssertions bool
+	outputPrefix     bool
+
+	gitSHA         string
+	fingerprintEnv []string
+
+	newFS    func(t *testing.T) afero.Fs
+	newQueue func(t *testing.T) QueueFixture
+
+	flagProvider FlagProvider
+
+	noTimeouts    bool
+	strict        bool
+	teardownGrace time.Duration
+	// handleInterrupts is set if the contexts of the suite are canceled
+	// on interrupts, see WithInterruptHandling.
+	handleInterrupts bool
+
+	invalidMethodsTest bool
+	detectStaleT       bool
+	detectMutations    bool
+
+	benchGate  *BenchmarkGate
+	memoryGate *MemoryGate
+}
+
+// newOptions builds the configuration of a Run from the command-line
+// flags, overridden by opts.
+func newOptions(opts []Option) *options {
+	o := &options{
+		namer:     MethodNamer,
+		verbosity: verbosityFromFlags(),
+		seed:      seedFromFlags(),
+		prefixes:  []string{"Test"},
+
+		artifactDir:   *artifactDir,
+		retention:     retainFlag,
+		diagnostics:   *diagnostics,
+		goroutineDump: *goroutineDump,
+		secretsDir:    *secretsDir,
+		reaperDir:     *reaperDir,
+
+		warnNoAssertions: *warnNoAssertions,
+		outputPrefix:     *outputPrefix,
+		noTimeouts:       *noTimeouts || debuggerAttached(),
+		maxParallel:      *maxParallel,
+		strict:           *strict,
+		teardownGrace:    *teardownGrace,
+		handleInterrupts: *handleInterrupts,
+		detectStaleT:     *detectStaleT,
+		detectMutations:  *detectMutations,
+		benchGate:        benchGateFromFlags(),
+		memoryGate:       memoryGateFromFlags(),
+	}
+	o.reporters = reportersFromFlags()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.noTimeouts {
+		o.timeout = 0
+	}
+	return o
+}
+
+// WithPrefix sets the prefixes of the names of the methods that are run as
+// tests, "Test" by default, e.g. WithPrefix("Spec") or
+// WithPrefix("Test", "Should").
+func WithPrefix(prefixes ...string) Option {
+	return func(o *options) {
+		o.prefixes = prefixes
+	}
+}
+
+// WithFilter selects the test methods to run with filter, in addition to
+// the prefixes set with WithPrefix and the -testify.m flag, e.g. to run the
+// tests carrying a tag or the tests suited to the environment. When several
+// filters are set, a method runs only if all of them select it.
+func WithFilter(filter func(methodName string) bool) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, filter)
+	}
+}
+
+// WithShuffle runs the test methods in a random order derived from seed
+// instead of in the order of their names, e.g. to find tests depending on
+// each other. The seed is logged, so that an order can be reproduced.
+func WithShuffle(seed int64) Option {
+	return func(o *options) {
+		o.shuffle = &seed
+	}
+}
+
+// WithFailFast skips the remaining tests of the suite once a test failed.
+func WithFailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+// Interceptor wraps the call of every test method of a suite, e.g. to
+// trace the tests or to recover from panics. It must call next to call the
+// test method named testName.
+type Interceptor func(t *testing.T, testName string, next func())
+
+// WithInterceptor adds interceptors wrapping the calls of test methods.
+// The first interceptor added is the outermost one. Interceptors don't
+// wrap the per-test hooks.
+func WithInterceptor(interceptors ...Interceptor) Option {
+	return func(o *options) {
+		o.interceptors = append(o.interceptors, interceptors...)
+	}
+}
+
+// WithTimeout limits the duration of every test of the suite. The context
+// of the test (see Suite.Context) is cancelled after d, and the test fails
+// if its method is still running then. The method is given five more
+// seconds to return, e.g. as Eventually does once the context is done, and
+// the teardown hooks of the test run once it has. If it doesn't return, it
+// is abandoned: it keeps running in the background, the teardown hooks of
+// the test are skipped so as not to run concurrently with it, and it must
+// not use the test's T anymore.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithParallel runs the tests of the suite in parallel with each other,
+// as if every test called t.Parallel. Every test runs on a shallow copy of
+// the suite made after SetupSuite, so that tests can keep their state in
+// fields of the suite, while what SetupSuite stored in pointers, maps or
+// mixins is shared and must be safe for concurrent use. The TearDownSuite
+// hooks run once all tests have finished. The fixtures of the suite are
+// safe for concurrent use, and the tests using the blob store or the mail
+// sink, which every test expects to find empty, don't run at the same
+// time, see BlobStore and MailSink. Tests that must not run at the
+// same time can be put in serial groups, see GroupedSuite, and the number
+// of tests running at the same time can be limited with WithMaxParallel.
+func WithParallel() Option {
+	return func(o *options) {
+		o.parallel = true
+	}
+}
@@ -0,0 +1,176 @@
+package suite
+
+import (
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteOrderRecorder struct {
+	Suite
+	order []string
+	fail  bool
+}
+
+func (s *SuiteOrderRecorder) BeforeTest(suiteName, testName string) {
+	s.order = append(s.order, testName)
+}
+
+func (s *SuiteOrderRecorder) TestA() {
+	if s.fail {
+		s.T().Fail()
+	}
+}
+func (s *SuiteOrderRecorder) TestB() {}
+func (s *SuiteOrderRecorder) TestC() {}
+func (s *SuiteOrderRecorder) TestD() {}
+func (s *SuiteOrderRecorder) TestE() {}
+
+func TestWithShuffle(t *testing.T) {
+	orders := map[int64][]string{}
+	for _, seed := range []int64{1, 2, 1} {
+		s := new(SuiteOrderRecorder)
+		Run(t, s, WithShuffle(seed))
+		assert.ElementsMatch(t, []string{"TestA", "TestB", "TestC", "TestD", "TestE"}, s.order)
+		if previous, ok := orders[seed]; ok {
+			assert.Equal(t, previous, s.order, "the same seed must shuffle the same way")
+		}
+		orders[seed] = s.order
+	}
+	assert.NotEqual(t, orders[1], orders[2])
+}
+
+func TestWithFailFast(t *testing.T) {
+	s := &SuiteOrderRecorder{fail: true}
+	reporter := &recordingReporter{}
+	ok, _, err := runDetachedSuiteWithOutputCapture(s, WithFailFast(), WithReporter(reporter))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"TestA"}, s.order)
+	if assert.Len(t, reporter.Finished, 5) {
+		assert.Equal(t, StatusFailed, reporter.Finished[0].Status)
+		for _, result := range reporter.Finished[1:] {
+			assert.Equal(t, StatusSkipped, result.Status)
+			assert.Equal(t, "an earlier test of the suite failed", result.SkipReason)
+		}
+	}
+}
+
+type SuiteInterceptorTester struct {
+	Suite
+	calls []string
+}
+
+func (s *SuiteInterceptorTester) SetupTest() {
+	s.calls = append(s.calls, "SetupTest")
+}
+
+func (s *SuiteInterceptorTester) TestMethod() {
+	s.calls = append(s.calls, "TestMethod")
+}
+
+func TestWithInterceptor(t *testing.T) {
+	s := new(SuiteInterceptorTester)
+	interceptor := func(name string) Interceptor {
+		return func(t *testing.T, testName string, next func()) {
+			s.calls = append(s.calls, name+" "+testName)
+			next()
+			s.calls = append(s.calls, name+" done")
+		}
+	}
+	Run(t, s, WithInterceptor(interceptor("outer")), WithInterceptor(interceptor("inner")))
+	assert.Equal(t, []string{"SetupTest", "outer TestMethod", "inner TestMethod", "TestMethod", "inner done", "outer done"}, s.calls)
+}
+
+type SuiteTimeoutTester struct {
+	Suite
+	release  chan struct{}
+	tornDown []string
+}
+
+func (s *SuiteTimeoutTester) AfterTest(_, testName string) {
+	s.tornDown = append(s.tornDown, testName)
+}
+
+func (s *SuiteTimeoutTester) TestFast() {}
+
+func (s *SuiteTimeoutTester) TestHanging() {
+	<-s.release
+}
+
+func TestWithTimeout(t *testing.T) {
+	defer func(wait time.Duration) { timeoutWait = wait }(timeoutWait)
+	timeoutWait = 10 * time.Millisecond
+	s := &SuiteTimeoutTester{release: make(chan struct{})}
+	defer close(s.release)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithTimeout(50*time.Millisecond))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: TestHanging timed out after 50ms")
+	assert.Contains(t, output, "suite: TestHanging still running 10ms after timing out, skipping the teardown of the test")
+	assert.NotContains(t, output, "--- FAIL: DetachedSuite/TestFast")
+	// The teardown of TestHanging would run concurrently with it.
+	assert.Equal(t, []string{"TestFast"}, s.tornDown)
+}
+
+type SuiteParallelTester struct {
+	Suite
+	arrived  *sync.WaitGroup
+	name     string
+	finished *[]string
+	mu       *sync.Mutex
+}
+
+func (s *SuiteParallelTester) SetupSuite() {
+	s.arrived = &sync.WaitGroup{}
+	s.arrived.Add(3)
+	s.finished = &[]string{}
+	s.mu = &sync.Mutex{}
+}
+
+func (s *SuiteParallelTester) TearDownSuite() {
+	assert.Len(s.T(), *s.finished, 3)
+}
+
+func (s *SuiteParallelTester) test(name string) {
+	s.name = name
+	s.arrived.Done()
+	// Every test waits for all others to have started.
+	done := make(chan struct{})
+	go func() {
+		s.arrived.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		s.T().Fatal("tests don't run in parallel")
+	}
+	assert.Equal(s.T(), name, s.name)
+	s.mu.Lock()
+	*s.finished = append(*s.finished, name)
+	s.mu.Unlock()
+}
+
+func (s *SuiteParallelTester) TestA() { s.test("a") }
+func (s *SuiteParallelTester) TestB() { s.test("b") }
+func (s *SuiteParallelTester) TestC() { s.test("c") }
+
+func TestWithParallel(t *testing.T) {
+	// The detached run allows as many parallel tests as the flag says,
+	// which defaults to the number of CPUs.
+	parallel := flag.Lookup("test.parallel").Value
+	defer parallel.Set(parallel.String())
+	parallel.Set("3")
+
+	collector := &resultCollector{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteParallelTester), WithParallel(), WithReporter(collector))
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	if assert.Len(t, collector.results, 1) {
+		assert.Len(t, collector.results[0].Tests, 3)
+	}
+}
@@ -0,0 +1,162 @@
+package suite
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// withOrder runs the test methods named in order, in that order, instead
+// of all test methods.
+func withOrder(order []string) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// inOrder returns the methods named in order, in that order.
+func inOrder(methods []reflect.Method, order []string) []reflect.Method {
+	byName := make(map[string]reflect.Method, len(methods))
+	for _, method := range methods {
+		byName[method.Name] = method
+	}
+	ordered := make([]reflect.Method, 0, len(order))
+	for _, name := range order {
+		if method, ok := byName[name]; ok {
+			ordered = append(ordered, method)
+		}
+	}
+	return ordered
+}
+
+// DetectOrderDependence diagnoses tests of a suite that pass or fail
+// depending on the tests run before them, e.g. because a test leaves
+// behind state that another one doesn't expect.
+//
+// The suite created by newSuite is run runs times, each time with its test
+// methods in a different random order derived from the seed (see
+// WithSeed). When a test passes in some orders and fails in others, the
+// orders are bisected to find the test causing the failure, which is
+// reported as an error:
+//
+//	suite: TestList fails when run after TestCreate
+//
+// Tests that only pass when another test ran before them are reported
+// likewise. Every run is a subtest of t, so the runs that fail are reported
+// as failures too. A new suite is created for every run.
+func DetectOrderDependence(t *testing.T, newSuite func() TestingSuite, runs int, opts ...Option) {
+	t.Helper()
+	o := newOptions(opts)
+	var names []string
+	for _, method := range (&suiteRun{opts: o, suite: newSuite()}).testMethods() {
+		names = append(names, method.Name)
+	}
+	d := &orderDetector{t: t, newSuite: newSuite, opts: opts}
+
+	// The outcome of every test, and an order in which it passed and one in
+	// which it failed.
+	passed := make(map[string][]string)
+	failed := make(map[string][]string)
+	random := rand.New(rand.NewSource(o.seed))
+	for i := 0; i < runs; i++ {
+		order := append([]string(nil), names...)
+		random.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		for name, status := range d.run(fmt.Sprintf("order-%d", i+1), order) {
+			switch status {
+			case StatusPassed:
+				passed[name] = order
+			case StatusFailed:
+				failed[name] = order
+			}
+		}
+	}
+
+	var victims []string
+	for name := range failed {
+		if _, ok := passed[name]; ok {
+			victims = append(victims, name)
+		}
+	}
+	sort.Strings(victims)
+	if len(victims) == 0 {
+		t.Logf("suite: no order dependence found in %d runs", runs)
+		return
+	}
+	for _, victim := range victims {
+		t.Error(d.bisect(victim, passed[victim], failed[victim]))
+	}
+}
+
+// orderDetector runs a suite in given orders.
+type orderDetector struct {
+	t        *testing.T
+	newSuite func() TestingSuite
+	opts     []Option
+	runs     int
+}
+
+// run runs the test methods named in order and returns their statuses.
+func (d *orderDetector) run(name string, order []string) map[string]Status {
+	d.runs++
+	collector := &resultCollector{}
+	opts := append(append([]Option(nil), d.opts...), withOrder(order), WithReporter(collector))
+	d.t.Run(name, func(t *testing.T) {
+		t.Logf("suite: running %s", strings.Join(order, ", "))
+		Run(t, d.newSuite(), opts...)
+	})
+	statuses := make(map[string]Status)
+	for _, suite := range collector.results {
+		for _, test := range suite.Tests {
+			statuses[test.Name] = test.Status
+		}
+	}
+	return statuses
+}
+
+// bisect finds the test making victim fail when run before it, or the test
+// making it pass if it fails on its own, and returns a description of the
+// culprit.
+func (d *orderDetector) bisect(victim string, passingOrder, failingOrder []string) string {
+	alone := d.run("bisect-"+victim, []string{victim})[victim]
+	// The status the culprit causes, and the order in which it precedes
+	// victim.
+	want, order, relation := StatusFailed, failingOrder, "fails when run after"
+	if alone == StatusFailed {
+		want, order, relation = StatusPassed, passingOrder, "only passes when run after"
+	}
+	candidates := predecessors(order, victim)
+	if len(candidates) == 0 {
+		return fmt.Sprintf("suite: %s passes and fails regardless of the order", victim)
+	}
+	reproduces := func(tests []string) bool {
+		order := append(append([]string(nil), tests...), victim)
+		return d.run(fmt.Sprintf("bisect-%d", d.runs), order)[victim] == want
+	}
+	for len(candidates) > 1 {
+		half := len(candidates) / 2
+		if reproduces(candidates[:half]) {
+			candidates = candidates[:half]
+		} else if reproduces(candidates[half:]) {
+			candidates = candidates[half:]
+		} else {
+			// The outcome depends on several tests together.
+			break
+		}
+	}
+	return fmt.Sprintf("suite: %s %s %s", victim, relation, strings.Join(candidates, " and "))
+}
+
+// predecessors returns the tests run before test in order.
+func predecessors(order []string, test string) []string {
+	for i, name := range order {
+		if name == test {
+			return order[:i]
+		}
+	}
+	return nil
+}
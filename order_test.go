@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderState is the state shared between the tests of SuiteOrderTester,
+// which makes them depend on their order.
+type orderState struct {
+	polluted, prepared bool
+}
+
+type SuiteOrderTester struct {
+	Suite
+	state *orderState
+}
+
+func (s *SuiteOrderTester) TestA() {}
+func (s *SuiteOrderTester) TestB() {}
+func (s *SuiteOrderTester) TestC() {}
+func (s *SuiteOrderTester) TestD() {}
+
+func (s *SuiteOrderTester) TestPollute() {
+	s.state.polluted = true
+}
+
+func (s *SuiteOrderTester) TestPrepare() {
+	s.state.prepared = true
+}
+
+func (s *SuiteOrderTester) TestVictim() {
+	assert.False(s.T(), s.state.polluted)
+}
+
+func (s *SuiteOrderTester) TestPrepared() {
+	assert.True(s.T(), s.state.prepared)
+}
+
+func TestDetectOrderDependence(t *testing.T) {
+	newSuite := func() TestingSuite {
+		return &SuiteOrderTester{state: &orderState{}}
+	}
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		DetectOrderDependence(t, newSuite, 10, WithSeed(1))
+	})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: TestVictim fails when run after TestPollute\n")
+	assert.Contains(t, output, "suite: TestPrepared only passes when run after TestPrepare\n")
+}
+
+type SuiteIndependentTester struct {
+	Suite
+}
+
+func (s *SuiteIndependentTester) TestA() {}
+func (s *SuiteIndependentTester) TestB() {}
+
+func TestDetectOrderDependenceIndependent(t *testing.T) {
+	DetectOrderDependence(t, func() TestingSuite { return new(SuiteIndependentTester) }, 3)
+}
@@ -0,0 +1,46 @@
+package suite
+
+import "runtime"
+
+// osThreadTag is the tag of the tests running on a dedicated OS thread,
+// see OSThreadSuite.
+const osThreadTag = "osthread"
+
+// OSThreadSuite has a NeedsOSThread method reporting whether the suite
+// needs to run on dedicated OS threads, e.g. because it changes the
+// thread-local state of the OS, such as the namespaces of the thread, or
+// calls C libraries that keep state per thread.
+//
+// The hooks of the suite then run on an OS thread locked to them, and
+// each test, with its hooks, runs on another one, see
+// runtime.LockOSThread. The threads are terminated once the suite or the
+// test has finished instead of being reused by other goroutines, so that
+// their state can't leak. Single tests can be run on a dedicated thread by
+// tagging them osthread, see Metadata.
+//
+// Tests running on a dedicated thread aren't abandoned when they exceed
+// the timeout set with WithTimeout, which would move them to another
+// thread; their context is canceled, and the test is failed once it
+// returns.
+type OSThreadSuite interface {
+	NeedsOSThread() bool
+}
+
+// needsOSThread reports whether the test method name, or the suite if name
+// is empty, runs on a dedicated OS thread.
+func (r *suiteRun) needsOSThread(name string) bool {
+	if s, ok := r.suite.(OSThreadSuite); ok && s.NeedsOSThread() {
+		return true
+	}
+	return name != "" && r.metadata(name).hasTag(osThreadTag)
+}
+
+// lockOSThread locks the calling goroutine, running the test method name
+// or the suite if name is empty, to its OS thread if it needs a dedicated
+// thread. The thread is never unlocked, so that it is terminated when the
+// goroutine exits.
+func (r *suiteRun) lockOSThread(name string) {
+	if r.needsOSThread(name) {
+		runtime.LockOSThread()
+	}
+}
@@ -0,0 +1,93 @@
+//go:build linux
+
+package suite
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteOSThreadTester struct {
+	Suite
+	suiteWide bool
+	threads   map[string][]int
+}
+
+func (s *SuiteOSThreadTester) NeedsOSThread() bool {
+	return s.suiteWide
+}
+
+func (s *SuiteOSThreadTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{"TestTagged": {Tags: []string{"osthread"}}}
+}
+
+func (s *SuiteOSThreadTester) record(name string) {
+	if s.threads == nil {
+		s.threads = make(map[string][]int)
+	}
+	s.threads[name] = append(s.threads[name], syscall.Gettid())
+}
+
+func (s *SuiteOSThreadTester) SetupSuite()    { s.record("suite") }
+func (s *SuiteOSThreadTester) TearDownSuite() { s.record("suite") }
+func (s *SuiteOSThreadTester) SetupTest()     { s.record(s.T().Name()) }
+func (s *SuiteOSThreadTester) TearDownTest()  { s.record(s.T().Name()) }
+
+func (s *SuiteOSThreadTester) TestTagged() {
+	time.Sleep(time.Millisecond)
+	s.record(s.T().Name())
+}
+
+func (s *SuiteOSThreadTester) TestUntagged() {
+	s.record(s.T().Name())
+}
+
+// sameThread asserts that all threads are the same one, returning it.
+func sameThread(t *testing.T, threads []int) int {
+	t.Helper()
+	require.Len(t, threads, 3)
+	assert.Equal(t, []int{threads[0], threads[0], threads[0]}, threads)
+	return threads[0]
+}
+
+func TestOSThreadSuite(t *testing.T) {
+	s := &SuiteOSThreadTester{suiteWide: true}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithTimeout(time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	require.Len(t, s.threads["suite"], 2)
+	suite := s.threads["suite"][0]
+	assert.Equal(t, suite, s.threads["suite"][1])
+	tagged := sameThread(t, s.threads["DetachedSuite/TestTagged"])
+	untagged := sameThread(t, s.threads["DetachedSuite/TestUntagged"])
+	assert.NotEqual(t, suite, tagged)
+	assert.NotEqual(t, suite, untagged)
+	assert.NotEqual(t, tagged, untagged)
+}
+
+func TestOSThreadTag(t *testing.T) {
+	s := new(SuiteOSThreadTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithTimeout(time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	sameThread(t, s.threads["DetachedSuite/TestTagged"])
+}
+
+type SuiteOSThreadTimeoutTester struct{ Suite }
+
+func (s *SuiteOSThreadTimeoutTester) NeedsOSThread() bool { return true }
+
+func (s *SuiteOSThreadTimeoutTester) TestSlow() {
+	<-s.Context().Done()
+}
+
+func TestOSThreadTimeout(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteOSThreadTimeoutTester), WithTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: TestSlow timed out after 10ms")
+}
@@ -0,0 +1,146 @@
+package suite
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var update = flag.Bool("testify.update", false, "update the golden files of testify suites instead of comparing against them")
+
+// outputMu serializes the replacement of os.Stdout and os.Stderr across
+// suites.
+var outputMu sync.Mutex
+
+// CaptureOutput calls fn and returns what it wrote to os.Stdout and
+// os.Stderr. Both are restored when fn returns, also if it fails the test
+// or panics.
+//
+// Since os.Stdout and os.Stderr are global, concurrent calls of
+// CaptureOutput and the output assertions wait for each other.
+func (suite *Suite) CaptureOutput(fn func()) (stdout, stderr string) {
+	suite.T().Helper()
+	return suite.capture(fn, false)
+}
+
+// OutputContains asserts that the output fn writes to os.Stdout and
+// os.Stderr contains substr.
+func (suite *Suite) OutputContains(fn func(), substr string, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	output, _ := suite.capture(fn, true)
+	if !strings.Contains(output, substr) {
+		suite.errorf(msgAndArgs, "Output does not contain %q:\n%s", substr, output)
+		return false
+	}
+	return true
+}
+
+// OutputMatches asserts that the output fn writes to os.Stdout and
+// os.Stderr matches rx, a *regexp.Regexp or a string compiled into one.
+func (suite *Suite) OutputMatches(fn func(), rx interface{}, msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	r, ok := rx.(*regexp.Regexp)
+	if !ok {
+		var err error
+		if r, err = regexp.Compile(fmt.Sprint(rx)); err != nil {
+			suite.errorf(msgAndArgs, "Invalid regular expression %q: %v", rx, err)
+			return false
+		}
+	}
+	output, _ := suite.capture(fn, true)
+	if !r.MatchString(output) {
+		suite.errorf(msgAndArgs, "Output does not match %q:\n%s", r, output)
+		return false
+	}
+	return true
+}
+
+// OutputGolden asserts that the output fn writes to os.Stdout and os.Stderr
+// equals the golden file testdata/<name>.golden, showing a diff otherwise.
+// With the -testify.update flag the golden file is written instead.
+func (suite *Suite) OutputGolden(name string, fn func(), msgAndArgs ...interface{}) bool {
+	suite.T().Helper()
+	suite.countAssertion()
+	output, _ := suite.capture(fn, true)
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			suite.T().Fatalf("suite: failed to update golden file: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			suite.T().Fatalf("suite: failed to update golden file: %v", err)
+		}
+		return true
+	}
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		suite.errorf(msgAndArgs, "Failed to read golden file, run with -testify.update to create it: %v", err)
+		return false
+	}
+	if string(golden) != output {
+		suite.errorf(msgAndArgs, "Output differs from %s, run with -testify.update to update it:\n%s", path, diffLines(string(golden), output))
+		return false
+	}
+	return true
+}
+
+// capture calls fn and returns what it wrote to os.Stdout and os.Stderr.
+// If combined is set, both are written to stdout in the order they were
+// written.
+func (suite *Suite) capture(fn func(), combined bool) (stdout, stderr string) {
+	suite.T().Helper()
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		suite.T().Fatalf("suite: failed to capture output: %v", err)
+	}
+	outC, errC, errW := readPipe(outR), (<-chan string)(nil), outW
+	if !combined {
+		var errR *os.File
+		if errR, errW, err = os.Pipe(); err != nil {
+			outW.Close()
+			suite.T().Fatalf("suite: failed to capture output: %v", err)
+		}
+		errC = readPipe(errR)
+	}
+
+	func() {
+		origOut, origErr := os.Stdout, os.Stderr
+		os.Stdout, os.Stderr = outW, errW
+		defer func() {
+			os.Stdout, os.Stderr = origOut, origErr
+			outW.Close()
+			if !combined {
+				errW.Close()
+			}
+		}()
+		fn()
+	}()
+	stdout = <-outC
+	if !combined {
+		stderr = <-errC
+	}
+	return stdout, stderr
+}
+
+// readPipe reads r until all its writers are closed.
+func readPipe(r *os.File) <-chan string {
+	c := make(chan string, 1)
+	go func() {
+		defer r.Close()
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		c <- buf.String()
+	}()
+	return c
+}
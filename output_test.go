@@ -0,0 +1,49 @@
+package suite
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteOutputTester struct {
+	Suite
+}
+
+func greet(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: greet NAME")
+		fmt.Fprintln(os.Stderr, "error: missing name")
+		return
+	}
+	fmt.Printf("Hello, %s!\n", args[0])
+}
+
+func (s *SuiteOutputTester) TestCaptureOutput() {
+	stdout, stderr := s.CaptureOutput(func() { greet(nil) })
+	assert.Equal(s.T(), "Usage: greet NAME\n", stdout)
+	assert.Equal(s.T(), "error: missing name\n", stderr)
+}
+
+func (s *SuiteOutputTester) TestOutputAssertions() {
+	s.OutputContains(func() { greet([]string{"gopher"}) }, "Hello, gopher!")
+	s.OutputMatches(func() { greet(nil) }, `(?m)^error: missing name$`)
+	s.OutputGolden("SuiteOutputTester_usage", func() { greet(nil) })
+}
+
+func (s *SuiteOutputTester) TestOutputGoldenMismatch() {
+	s.OutputGolden("SuiteOutputTester_usage", func() { greet([]string{"gopher"}) })
+}
+
+func TestOutputAssertions(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteOutputTester))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "--- FAIL: DetachedSuite/TestOutputAssertions")
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/TestOutputGoldenMismatch")
+	assert.Contains(t, output, "Output differs from testdata/SuiteOutputTester_usage.golden, run with -testify.update to update it:")
+	assert.Contains(t, output, "-Usage: greet NAME")
+	assert.Contains(t, output, "+Hello, gopher!")
+}
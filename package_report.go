@@ -0,0 +1,101 @@
+package suite
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var packageReport = flag.String("testify.package-report", "", "write a JSON report of all testify suites of the package to this file, see suite.Main")
+
+// PackageResult aggregates the results of the suites of a package run by
+// Main.
+type PackageResult struct {
+	// Name is the name of the package.
+	Name string
+	// Suites are the results of the suites, in the order they finished.
+	Suites []SuiteResult
+}
+
+// Summary returns the rollup of the results of the tests of all suites,
+// listing the slowest tests of the package. Its duration is the sum of the
+// durations of the suites.
+func (p PackageResult) Summary() Summary {
+	var tests []TestResult
+	var duration time.Duration
+	for _, suite := range p.Suites {
+		tests = append(tests, suite.Tests...)
+		duration += suite.Duration
+	}
+	return summarizeTests(p.Name, tests, duration)
+}
+
+type jsonPackage struct {
+	Package    string         `json:"package"`
+	Status     string         `json:"status"`
+	DurationMS float64        `json:"duration_ms"`
+	Tests      int            `json:"tests"`
+	Counts     map[string]int `json:"counts"`
+	Slowest    []jsonSlowTest `json:"slowest"`
+	Suites     []jsonSuite    `json:"suites"`
+}
+
+type jsonSlowTest struct {
+	Suite      string  `json:"suite"`
+	Name       string  `json:"name"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// writeReport writes the JSON report of the package to path.
+func (p PackageResult) writeReport(path string) error {
+	summary := p.Summary()
+	doc := jsonPackage{
+		Package:    p.Name,
+		Status:     StatusPassed.String(),
+		DurationMS: milliseconds(summary.Duration),
+		Tests:      summary.Tests,
+		Counts:     make(map[string]int),
+		Slowest:    []jsonSlowTest{},
+		Suites:     []jsonSuite{},
+	}
+	if summary.Counts[StatusFailed] > 0 {
+		doc.Status = StatusFailed.String()
+	}
+	for status, n := range summary.Counts {
+		doc.Counts[status.String()] = n
+	}
+	for _, test := range summary.Slowest {
+		doc.Slowest = append(doc.Slowest, jsonSlowTest{Suite: test.Suite, Name: test.Name, DurationMS: milliseconds(test.Duration)})
+	}
+	for _, suite := range p.Suites {
+		doc.Suites = append(doc.Suites, newJSONSuite(suite))
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// reportPackage reports the results of the suites run while Main ran, as
+// enabled by the -testify.package-report and -testify.summary flags.
+func reportPackage(result PackageResult) {
+	if *packageReport != "" {
+		if err := result.writeReport(*packageReport); err != nil {
+			fmt.Fprintf(os.Stderr, "testify: failed to write package report: %v\n", err)
+		}
+	}
+	if *summary && len(result.Suites) > 1 {
+		fmt.Fprint(os.Stdout, result.Summary())
+	}
+}
+
+// packageName returns the name of the package under test, from the name
+// of the test binary.
+func packageName() string {
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".test")
+}
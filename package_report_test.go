@@ -0,0 +1,75 @@
+package suite
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageResult(t *testing.T) {
+	result := PackageResult{
+		Name: "store",
+		Suites: []SuiteResult{
+			{
+				Name:     "UsersSuite",
+				Duration: time.Second,
+				Tests: []TestResult{
+					{Suite: "UsersSuite", Name: "TestCreate", Status: StatusPassed, Duration: 300 * time.Millisecond},
+					{Suite: "UsersSuite", Name: "TestDelete", Status: StatusFailed, Duration: 10 * time.Millisecond},
+				},
+			},
+			{
+				Name:     "OrdersSuite",
+				Duration: 2 * time.Second,
+				Tests: []TestResult{
+					{Suite: "OrdersSuite", Name: "TestCheckout", Status: StatusPassed, Duration: 900 * time.Millisecond},
+					{Suite: "OrdersSuite", Name: "TestRefund", Status: StatusPassed, Duration: 20 * time.Millisecond},
+				},
+			},
+		},
+	}
+	assert.Equal(t, `store: 4 tests in 3s: 3 passed, 1 failed
+  slowest  OrdersSuite.TestCheckout  900ms
+           UsersSuite.TestCreate     300ms
+           OrdersSuite.TestRefund    20ms
+`, result.Summary().String())
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, result.writeReport(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var doc jsonPackage
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, "store", doc.Package)
+	assert.Equal(t, "fail", doc.Status)
+	assert.Equal(t, 3000.0, doc.DurationMS)
+	assert.Equal(t, map[string]int{"pass": 3, "fail": 1}, doc.Counts)
+	assert.Equal(t, jsonSlowTest{Suite: "OrdersSuite", Name: "TestCheckout", DurationMS: 900}, doc.Slowest[0])
+	require.Len(t, doc.Suites, 2)
+	assert.Equal(t, "UsersSuite", doc.Suites[0].Suite)
+	assert.Equal(t, "fail", doc.Suites[0].Status)
+}
+
+func TestPackageResultsRecordedInMain(t *testing.T) {
+	registry.mu.Lock()
+	registry.inMain = true
+	registry.mu.Unlock()
+	defer func() {
+		registry.mu.Lock()
+		registry.inMain = false
+		registry.results = nil
+		registry.mu.Unlock()
+	}()
+
+	NewRunner().Run(t, new(SuiteSummaryTester), new(SuiteOrderRecorder))
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	require.Len(t, registry.results, 2)
+	assert.Equal(t, "SuiteSummaryTester", registry.results[0].Name)
+	assert.Equal(t, "SuiteOrderRecorder", registry.results[1].Name)
+}
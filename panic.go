@@ -0,0 +1,86 @@
+package suite
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// PanicHandler has a HandlePanic method, which will run when a test or a
+// hook panics, with the name of the test, or of the suite-level hook if no
+// test is running, the recovered value and the stack trace of the panic.
+// It runs before the panic fails the test, e.g. to report the crash to an
+// external service. Mixins can implement PanicHandler too.
+type PanicHandler interface {
+	HandlePanic(testName string, recovered interface{}, stack []byte)
+}
+
+// recoveredPanic is a panic of a test or a hook recovered by the runner.
+type recoveredPanic struct {
+	value interface{}
+	stack []byte
+	// location is the file and line that panicked.
+	location string
+}
+
+// catchPanic calls f, returning its panic if it panics.
+func catchPanic(f func()) (p *recoveredPanic) {
+	defer func() {
+		if v := recover(); v != nil {
+			p = &recoveredPanic{value: v, stack: debug.Stack(), location: panicLocation()}
+		}
+	}()
+	f()
+	return nil
+}
+
+// failOnPanic calls the PanicHandler hooks for the panic p of what and fails
+// t. It is called once the panic has been recovered, rather than while
+// panicking, so that the testing package attributes the failure to the
+// caller of Run instead of the runtime, and the failure message reports
+// the line that panicked.
+func (r *suiteRun) failOnPanic(t *testing.T, what string, p *recoveredPanic) {
+	t.Helper()
+	testName := r.test
+	if testName == "" {
+		testName = what
+	}
+	for _, target := range r.hookTargets() {
+		if handler, ok := target.(PanicHandler); ok {
+			handler.HandlePanic(testName, p.value, p.stack)
+		}
+	}
+	if p.location != "" {
+		what += " at " + p.location
+	}
+	message := fmt.Sprintf("suite: %s panicked: %v\n%s", what, p.value, p.stack)
+	if b, ok := r.suite.(suiteBase); ok && t != r.suiteT {
+		b.base().test.failures = append(b.base().test.failures, message)
+	}
+	t.Error(message)
+	t.FailNow()
+}
+
+// panicLocation returns the file and line of the code that panicked, in the
+// format of the testing package, when called by a deferred function while
+// panicking, or "" if it can't be found.
+func panicLocation() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package suite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type handledPanic struct {
+	test      string
+	recovered interface{}
+	stack     string
+}
+
+type SuitePanicTester struct {
+	Suite
+	panics   []handledPanic
+	tornDown []string
+}
+
+func (s *SuitePanicTester) HandlePanic(testName string, recovered interface{}, stack []byte) {
+	s.panics = append(s.panics, handledPanic{testName, recovered, string(stack)})
+}
+
+func (s *SuitePanicTester) BeforeTest(suiteName, testName string) {
+	if testName == "TestSetupPanics" {
+		panic("broken fixture")
+	}
+}
+
+func (s *SuitePanicTester) AfterTest(suiteName, testName string) {
+	s.tornDown = append(s.tornDown, testName)
+}
+
+func (s *SuitePanicTester) TestPanics() {
+	var m map[string]int
+	m["boom"]++
+}
+
+func (s *SuitePanicTester) TestSetupPanics() {
+	s.T().Error("must not run")
+}
+
+func (s *SuitePanicTester) TestPasses() {}
+
+func TestHandlePanic(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithTimeout(time.Minute)}} {
+		s := new(SuitePanicTester)
+		reporter := &recordingReporter{}
+		ok, output, err := runDetachedSuiteWithOutputCapture(s, append(opts, WithReporter(reporter))...)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.Len(t, s.panics, 2)
+		assert.Equal(t, "TestPanics", s.panics[0].test)
+		assert.EqualError(t, s.panics[0].recovered.(error), "assignment to entry in nil map")
+		assert.Contains(t, s.panics[0].stack, "(*SuitePanicTester).TestPanics")
+		assert.Equal(t, "TestSetupPanics", s.panics[1].test)
+		assert.Equal(t, "broken fixture", s.panics[1].recovered)
+
+		// The failures are attributed to the call of Run, and report the
+		// line that panicked.
+		assert.Regexp(t, `suite_test.go:\d+: suite: TestPanics at panic_test.go:\d+ panicked: assignment to entry in nil map`, output)
+		assert.Regexp(t, `suite_test.go:\d+: suite: SuitePanicTester.BeforeTest at panic_test.go:\d+ panicked: broken fixture`, output)
+		assert.NotContains(t, output, "must not run")
+		assert.Equal(t, []string{"TestPanics", "TestPasses"}, s.tornDown)
+		statuses := map[string]Status{}
+		for _, result := range reporter.Finished {
+			statuses[result.Name] = result.Status
+		}
+		assert.Equal(t, map[string]Status{"TestPanics": StatusFailed, "TestPasses": StatusPassed, "TestSetupPanics": StatusFailed}, statuses)
+	}
+}
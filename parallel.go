@@ -0,0 +1,38 @@
+package suite
+
+import "flag"
+
+var maxParallel = flag.Int("testify.parallel", 0, "maximum number of tests of a testify suite run with WithParallel to run at the same time (default: no limit besides -test.parallel)")
+
+// WithMaxParallel limits the number of tests of the suite running at the
+// same time when they run in parallel (see WithParallel) to n, including
+// their per-test hooks. Unlike go test's -parallel flag, the limit only
+// applies to the tests of the suite, e.g. to keep a suite from opening
+// more connections than its database accepts while the other tests of the
+// package run with full parallelism. A limit of 0 or less leaves the
+// number of tests limited by -parallel only. The limit is also set for all
+// suites by the -testify.parallel flag.
+func WithMaxParallel(n int) Option {
+	return func(o *options) {
+		o.maxParallel = n
+	}
+}
+
+// parallelSlots returns the semaphore limiting the number of tests of the
+// suite running at the same time, or nil if it's unlimited.
+func (r *suiteRun) parallelSlots() chan struct{} {
+	if !r.opts.parallel || r.opts.maxParallel <= 0 {
+		return nil
+	}
+	return make(chan struct{}, r.opts.maxParallel)
+}
+
+// acquireSlot waits until the test may run without exceeding the limit of
+// WithMaxParallel, and returns the function ending its turn.
+func (r *suiteRun) acquireSlot() func() {
+	if r.slots == nil {
+		return func() {}
+	}
+	r.slots <- struct{}{}
+	return func() { <-r.slots }
+}
@@ -0,0 +1,58 @@
+package suite
+
+import (
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteMaxParallelTester struct {
+	Suite
+	mu                  *sync.Mutex
+	running, maxRunning *int
+}
+
+func (s *SuiteMaxParallelTester) SetupSuite() {
+	s.mu = &sync.Mutex{}
+	s.running, s.maxRunning = new(int), new(int)
+}
+
+func (s *SuiteMaxParallelTester) SetupTest() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.running++
+	*s.maxRunning = max(*s.maxRunning, *s.running)
+}
+
+func (s *SuiteMaxParallelTester) TearDownTest() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.running--
+}
+
+func (s *SuiteMaxParallelTester) TestOne()   { time.Sleep(20 * time.Millisecond) }
+func (s *SuiteMaxParallelTester) TestTwo()   { time.Sleep(20 * time.Millisecond) }
+func (s *SuiteMaxParallelTester) TestThree() { time.Sleep(20 * time.Millisecond) }
+func (s *SuiteMaxParallelTester) TestFour()  { time.Sleep(20 * time.Millisecond) }
+func (s *SuiteMaxParallelTester) TestFive()  { time.Sleep(20 * time.Millisecond) }
+
+func TestMaxParallel(t *testing.T) {
+	parallel := flag.Lookup("test.parallel").Value
+	defer parallel.Set(parallel.String())
+	parallel.Set("5")
+
+	s := new(SuiteMaxParallelTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithParallel(), WithMaxParallel(2))
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 2, *s.maxRunning)
+
+	s = new(SuiteMaxParallelTester)
+	ok, output, err = runDetachedSuiteWithOutputCapture(s, WithParallel())
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Greater(t, *s.maxRunning, 2)
+}
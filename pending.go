@@ -0,0 +1,27 @@
+package suite
+
+// pendingPrefixes mark pending test methods when prepended to a test
+// prefix, e.g. XTestLogin or PendingTestLogin. Pending tests are reported
+// with StatusPending without running them or their hooks, so that
+// unfinished tests stay visible in the reports instead of being commented
+// out.
+var pendingPrefixes = []string{"X", "Pending"}
+
+// pendingReason is the skip reason of pending tests.
+const pendingReason = "pending"
+
+// pendingTestPrefixes returns the prefixes of the pending test methods.
+func pendingTestPrefixes(prefixes []string) []string {
+	pending := make([]string, 0, len(pendingPrefixes)*len(prefixes))
+	for _, marker := range pendingPrefixes {
+		for _, prefix := range prefixes {
+			pending = append(pending, marker+prefix)
+		}
+	}
+	return pending
+}
+
+// pending reports whether the test method name is pending.
+func (r *suiteRun) pending(name string) bool {
+	return hasAnyPrefix(name, pendingTestPrefixes(r.opts.prefixes))
+}
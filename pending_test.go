@@ -0,0 +1,57 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuitePendingTester struct {
+	Suite
+
+	setups int
+}
+
+func (s *SuitePendingTester) SetupTest() {
+	s.setups++
+}
+
+func (s *SuitePendingTester) TestDone() {}
+
+func (s *SuitePendingTester) XTestUnfinished() {
+	s.T().Fatal("pending tests must not run")
+}
+
+func (s *SuitePendingTester) PendingTestUnwritten() {
+	s.T().Fatal("pending tests must not run")
+}
+
+func TestPendingTests(t *testing.T) {
+	recorder := new(recordingReporter)
+	var report, progress bytes.Buffer
+	s := new(SuitePendingTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReporter(recorder), WithReporter(NewJSONReporter(&report)), WithReporter(NewProgressReporter(&progress)))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 1, s.setups, "the hooks of pending tests must not run")
+
+	require.Len(t, recorder.Finished, 3)
+	statuses := make(map[string]Status)
+	for _, result := range recorder.Finished {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"PendingTestUnwritten": StatusPending,
+		"TestDone":             StatusPassed,
+		"XTestUnfinished":      StatusPending,
+	}, statuses)
+
+	var doc jsonSuite
+	require.NoError(t, json.Unmarshal(report.Bytes(), &doc))
+	assert.Equal(t, "pass", doc.Status)
+	assert.Contains(t, report.String(), `"status":"pending"`)
+	assert.Contains(t, progress.String(), "○ SuitePendingTester.XTestUnfinished (")
+}
@@ -0,0 +1,81 @@
+package suite
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// unixSystems are the values of runtime.GOOS matched by the unix key of a
+// PlatformFixture, as by the unix build constraint.
+var unixSystems = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+	"linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+// PlatformFixture is a fixture with alternate implementations per
+// operating system, e.g. a server listening on a Unix socket or on a named
+// pipe on Windows, so that tests don't have to branch on runtime.GOOS:
+//
+//	var server = &suite.PlatformFixture[*Server]{
+//		Name: "server",
+//		Create: map[string]func() (*Server, error){
+//			"unix":    startUnixSocketServer,
+//			"windows": startNamedPipeServer,
+//		},
+//		Destroy: (*Server).Close,
+//	}
+//
+//	func (s *ClientSuite) SetupSuite() {
+//		s.server = server.Get(s)
+//	}
+type PlatformFixture[F any] struct {
+	// Name describes the fixture in failures and skip reasons.
+	Name string
+	// Create maps operating systems to the function creating the fixture
+	// on them. Keys are values of runtime.GOOS, "unix", matching the
+	// Unix-like systems like the unix build constraint, or "default",
+	// matching the others. An exact match takes precedence over unix,
+	// which takes precedence over default.
+	Create  map[string]func() (F, error)
+	Destroy func(F)
+}
+
+// Get creates the implementation of the fixture for the operating system
+// the tests run on, destroying it when the current test finishes, or the
+// suite when called in SetupSuite. The current test, or the suite, is
+// skipped if the fixture has no implementation for the system, and fails
+// if it can't be created. The suite must embed Suite.
+func (f *PlatformFixture[F]) Get(s TestingSuite) F {
+	b, ok := s.(suiteBase)
+	if !ok {
+		panic(fmt.Sprintf("suite: platform fixture %q requested by %T, which doesn't embed suite.Suite", f.Name, s))
+	}
+	suite := b.base()
+	t := suite.T()
+	t.Helper()
+	create := f.implementation(runtime.GOOS)
+	if create == nil {
+		suite.skip(SkipPlatform, fmt.Sprintf("fixture %s is not supported on %s", f.Name, runtime.GOOS))
+	}
+	value, err := create()
+	if err != nil {
+		t.Fatalf("suite: failed to create fixture %s: %v", f.Name, err)
+	}
+	if f.Destroy != nil {
+		t.Cleanup(func() { f.Destroy(value) })
+	}
+	return value
+}
+
+// implementation returns the function creating the fixture on goos, or
+// nil if it isn't supported there.
+func (f *PlatformFixture[F]) implementation(goos string) func() (F, error) {
+	if create, ok := f.Create[goos]; ok {
+		return create
+	}
+	if create, ok := f.Create["unix"]; ok && unixSystems[goos] {
+		return create
+	}
+	return f.Create["default"]
+}
@@ -0,0 +1,76 @@
+package suite
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlatformFixture(keys ...string) *PlatformFixture[string] {
+	f := &PlatformFixture[string]{Name: "server", Create: make(map[string]func() (string, error))}
+	for _, key := range keys {
+		key := key
+		f.Create[key] = func() (string, error) { return key, nil }
+	}
+	return f
+}
+
+func TestPlatformFixtureImplementation(t *testing.T) {
+	f := newPlatformFixture("linux", "unix", "windows", "default")
+	for goos, want := range map[string]string{"linux": "linux", "darwin": "unix", "windows": "windows", "plan9": "default"} {
+		got, err := f.implementation(goos)()
+		require.NoError(t, err)
+		assert.Equal(t, want, got, goos)
+	}
+	assert.Nil(t, newPlatformFixture("windows").implementation("linux"))
+}
+
+type SuitePlatformFixtureTester struct {
+	Suite
+	fixture   *PlatformFixture[string]
+	got       string
+	ran       bool
+	destroyed []string
+}
+
+func (s *SuitePlatformFixtureTester) SetupSuite() {
+	s.fixture.Destroy = func(v string) { s.destroyed = append(s.destroyed, v) }
+	s.got = s.fixture.Get(s)
+}
+
+func (s *SuitePlatformFixtureTester) TestUse() {
+	s.ran = true
+	s.Equal(0, len(s.destroyed))
+}
+
+func TestPlatformFixture(t *testing.T) {
+	s := &SuitePlatformFixtureTester{fixture: newPlatformFixture(runtime.GOOS)}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, runtime.GOOS, s.got)
+	assert.Equal(t, []string{runtime.GOOS}, s.destroyed)
+}
+
+func TestPlatformFixtureUnsupported(t *testing.T) {
+	s := &SuitePlatformFixtureTester{fixture: newPlatformFixture("no-such-os")}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.False(t, s.ran)
+	assert.Equal(t, "", s.got)
+}
+
+func TestPlatformFixtureError(t *testing.T) {
+	f := &PlatformFixture[string]{Name: "server", Create: map[string]func() (string, error){
+		"default": func() (string, error) { return "", errors.New("port in use") },
+		"unix":    func() (string, error) { return "", errors.New("port in use") },
+	}}
+	ok, output, err := runDetachedSuiteWithOutputCapture(&SuitePlatformFixtureTester{fixture: f})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: failed to create fixture server: port in use")
+}
@@ -0,0 +1,104 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var outputPrefix = flag.Bool("testify.prefix", false, "prefix the failures and logs of testify suite tests with [Suite/Test]")
+
+// WithOutputPrefix prefixes the failures of the suite's assertions and the
+// lines logged with Suite.Log and Suite.Logf with [SuiteName/TestName], or
+// [SuiteName] outside of tests, which makes interleaved output of suites
+// running in parallel attributable. It is also enabled for all suites by the
+// -testify.prefix flag.
+func WithOutputPrefix() Option {
+	return func(o *options) {
+		o.outputPrefix = true
+	}
+}
+
+// Log formats its arguments like fmt.Sprintln and logs them to the current
+// test, prefixed as set with WithOutputPrefix. The output of tests running
+// in parallel is buffered until they finish, so that it isn't interleaved
+// with that of other tests.
+func (suite *Suite) Log(args ...interface{}) {
+	t := suite.T()
+	t.Helper()
+	line := suite.redact(suite.prefix + strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	if suite.test.log.buffer(t, line, 0) {
+		return
+	}
+	t.Log(line)
+}
+
+// Logf formats its arguments like fmt.Sprintf and logs them to the current
+// test like Log.
+func (suite *Suite) Logf(format string, args ...interface{}) {
+	t := suite.T()
+	t.Helper()
+	line := suite.redact(fmt.Sprintf(suite.prefix+format, args...))
+	if suite.test.log.buffer(t, line, 0) {
+		return
+	}
+	t.Log(line)
+}
+
+// assertT returns the current test for the suite's assertions, recording
+// their failures for the reporters, prefixing them as set with
+// WithOutputPrefix, redacting secrets from them, see Redact, and dumping
+// the goroutines, see WithGoroutineDump. During an attempt of a retried
+// setup hook, their failures are recorded by the attempt instead, see
+// RetryableSetup.
+func (suite *Suite) assertT() require.TestingT {
+	if attempt := suite.test.setupAttempt; attempt != nil {
+		return &attemptT{attempt: attempt}
+	}
+	return &prefixedT{T: suite.T(), suite: suite}
+}
+
+// prefixedT records, prefixes and redacts the failures of a test, and
+// counts its assertions.
+type prefixedT struct {
+	*testing.T
+	suite *Suite
+}
+
+// Helper counts an assertion of the test: every assertion of testify calls
+// Helper first, and so do the assertions it calls, which aren't counted.
+// The assertions aren't marked as helpers of the test then, but once one
+// fails, see markTestifyHelpers.
+func (t *prefixedT) Helper() {
+	pcs := make([]uintptr, 8)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	frames.Next()
+	if caller, _ := frames.Next(); !strings.HasPrefix(caller.Function, testifyPrefix) {
+		t.suite.countAssertion()
+	}
+}
+
+func (t *prefixedT) Errorf(format string, args ...interface{}) {
+	t.T.Helper()
+	markTestifyHelpers(t.T)
+	message := t.suite.redact(t.suite.prefix + fmt.Sprintf(format, args...))
+	t.suite.test.failures = append(t.suite.test.failures, message)
+	t.T.Error(message)
+	t.suite.dumpGoroutines()
+}
+
+// outputPrefix returns the prefix of the failures and logs of the test
+// method name, or of the suite if name is empty.
+func (r *suiteRun) outputPrefix(name string) string {
+	if !r.opts.outputPrefix {
+		return ""
+	}
+	if name == "" {
+		return "[" + r.suiteName + "] "
+	}
+	return "[" + r.suiteName + "/" + name + "] "
+}
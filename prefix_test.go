@@ -0,0 +1,38 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteOutputPrefixTester struct {
+	Suite
+}
+
+func (s *SuiteOutputPrefixTester) SetupSuite() {
+	s.Log("setting up")
+}
+
+func (s *SuiteOutputPrefixTester) TestFails() {
+	s.Logf("checking %d", 42)
+	s.Assert().Equal(1, 2)
+	s.Equal("a", "b")
+}
+
+func TestWithOutputPrefix(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteOutputPrefixTester), WithOutputPrefix())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "[SuiteOutputPrefixTester] setting up")
+	assert.Contains(t, output, "[SuiteOutputPrefixTester/TestFails] checking 42")
+	// The failures are still attributed to the assertions in the test.
+	assert.Regexp(t, `prefix_test.go:\d+: \[SuiteOutputPrefixTester/TestFails\] \n\s+Error Trace:`, output)
+	assert.Regexp(t, `prefix_test.go:\d+: \[SuiteOutputPrefixTester/TestFails\] `, output)
+
+	_, output, err = runDetachedSuiteWithOutputCapture(new(SuiteOutputPrefixTester))
+	require.NoError(t, err)
+	assert.Contains(t, output, "setting up")
+	assert.NotContains(t, output, "[SuiteOutputPrefixTester")
+}
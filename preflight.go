@@ -0,0 +1,20 @@
+package suite
+
+import "fmt"
+
+// preflight calls the Preflight method of the suite, skipping the suite if
+// it reports that the environment is unusable, see PreflightSuite.
+func (r *suiteRun) preflight() {
+	s, ok := r.suite.(PreflightSuite)
+	if !ok {
+		return
+	}
+	r.suiteT.Helper()
+	var err error
+	if p := catchPanic(func() { err = s.Preflight() }); p != nil {
+		r.failOnPanic(r.suiteT, r.suiteName+".Preflight", p)
+	}
+	if err != nil {
+		r.skipSuite(SkipPreflight, fmt.Sprintf("preflight failed: %v", err))
+	}
+}
@@ -0,0 +1,50 @@
+package suite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuitePreflightTester struct {
+	Suite
+	err        error
+	setUp, ran bool
+}
+
+func (s *SuitePreflightTester) Preflight() error {
+	return s.err
+}
+
+func (s *SuitePreflightTester) SetupSuite() {
+	s.setUp = true
+}
+
+func (s *SuitePreflightTester) TestUse() {
+	s.ran = true
+}
+
+func TestPreflight(t *testing.T) {
+	s := &SuitePreflightTester{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.True(t, s.setUp)
+	assert.True(t, s.ran)
+}
+
+func TestPreflightFailed(t *testing.T) {
+	s := &SuitePreflightTester{err: errors.New("postgres is not reachable on localhost:5432")}
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReporter(recorder))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.False(t, s.setUp)
+	assert.False(t, s.ran)
+	require.Len(t, recorder.Suites, 1)
+	assert.Equal(t, "preflight failed: postgres is not reachable on localhost:5432", recorder.Suites[0].SkipReason)
+	assert.Equal(t, SkipPreflight, recorder.Suites[0].SkipCategory)
+	assert.False(t, recorder.Suites[0].Failed())
+}
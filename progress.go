@@ -0,0 +1,89 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var progress = flag.Bool("testify.progress", false, "print a progress line per test of the testify suite to stdout")
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+)
+
+// progressReporter prints one line per finished test. When writing to a
+// terminal it also shows the currently running test and colors the
+// outcome.
+type progressReporter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	tty bool
+}
+
+// NewProgressReporter returns a Reporter that prints a human-friendly
+// line per test to w, e.g. "✓ ExampleTestSuite.TestExample (12ms)".
+// Colors and the live "running" line are only used when w is a terminal
+// and the NO_COLOR environment variable is not set.
+//
+// The reporter is enabled for all suites by the -testify.progress flag.
+func NewProgressReporter(w io.Writer) Reporter {
+	return &progressReporter{w: w, tty: isTerminal(w) && os.Getenv("NO_COLOR") == ""}
+}
+
+func (r *progressReporter) SuiteStarted(suiteName string) {}
+
+func (r *progressReporter) TestStarted(suiteName, testName string) {
+	if !r.tty {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.w, "%s… %s.%s%s", colorYellow, suiteName, testName, colorReset)
+}
+
+func (r *progressReporter) TestFinished(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mark, color := "✓", colorGreen
+	switch result.Status {
+	case StatusFailed:
+		mark, color = "✗", colorRed
+	case StatusSkipped:
+		mark, color = "-", colorYellow
+	case StatusPending:
+		mark, color = "○", colorYellow
+	}
+	line := fmt.Sprintf("%s %s.%s (%v)", mark, result.Suite, result.Name, result.Duration.Round(time.Millisecond))
+	if result.SkipReason != "" {
+		line += ": " + result.SkipReason
+	}
+	line += skipCategorySuffix(result.SkipCategory)
+	if r.tty {
+		// Overwrite the "running" line printed by TestStarted.
+		fmt.Fprintf(r.w, "\r\033[K%s%s%s\n", color, line, colorReset)
+		return
+	}
+	fmt.Fprintln(r.w, line)
+}
+
+func (r *progressReporter) SuiteFinished(result SuiteResult) {}
+
+// isTerminal reports whether w is a character device, e.g. a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
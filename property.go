@@ -0,0 +1,61 @@
+package suite
+
+import (
+	"reflect"
+	"testing"
+)
+
+// propertyPrefix is the prefix of the names of property test methods.
+const propertyPrefix = "Property"
+
+// propertyChecker runs the property test methods taking an argument of
+// type argType.
+type propertyChecker struct {
+	argType reflect.Type
+	check   func(t *testing.T, property reflect.Value)
+}
+
+// WithProperties runs the methods of the suite whose names start with
+// "Property" and that take a single argument of type T as property tests,
+// using check to run them, with the hooks of the suite run around every
+// check like around any other test. check is an adapter to a property
+// testing library, e.g. for pgregory.net/rapid:
+//
+//	suite.Run(t, new(ParserSuite), suite.WithProperties(func(t *testing.T, property func(*rapid.T)) {
+//		rapid.Check(t, property)
+//	}))
+//
+// runs methods like
+//
+//	func (s *ParserSuite) PropertyRoundTrip(t *rapid.T) {
+//		input := rapid.String().Draw(t, "input")
+//		...
+//	}
+//
+// so that property tests share the fixtures of the suite's other tests.
+// The -testify.m flag and WithFilter select property test methods too.
+func WithProperties[T any](check func(t *testing.T, property func(T))) Option {
+	return func(o *options) {
+		o.properties = append(o.properties, propertyChecker{
+			argType: reflect.TypeOf((*T)(nil)).Elem(),
+			check: func(t *testing.T, property reflect.Value) {
+				check(t, property.Interface().(func(T)))
+			},
+		})
+	}
+}
+
+// propertyChecker returns the checker running method, if it is a property
+// test method.
+func (r *suiteRun) propertyChecker(method reflect.Method) (propertyChecker, bool) {
+	// The method's receiver is its first argument.
+	if method.Type.NumIn() != 2 || method.Type.NumOut() != 0 {
+		return propertyChecker{}, false
+	}
+	for _, checker := range r.opts.properties {
+		if method.Type.In(1) == checker.argType {
+			return checker, true
+		}
+	}
+	return propertyChecker{}, false
+}
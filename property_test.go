@@ -0,0 +1,89 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// propT is the T of a minimal property testing library, drawing the
+// numbers 0 to 9 on the ten checks of a property.
+type propT struct {
+	*testing.T
+	n int
+}
+
+func checkProperty(t *testing.T, property func(*propT)) {
+	for n := 0; n < 10; n++ {
+		pt := &propT{T: t, n: n}
+		property(pt)
+		if t.Failed() {
+			t.Logf("property falsified for %d", n)
+			return
+		}
+	}
+}
+
+type SuitePropertyTester struct {
+	Suite
+	calls  []string
+	checks int
+}
+
+func (s *SuitePropertyTester) SetupTest() {
+	s.calls = append(s.calls, "SetupTest "+s.T().Name())
+}
+
+func (s *SuitePropertyTester) TearDownTest() {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *SuitePropertyTester) TestExample() {
+	s.calls = append(s.calls, "TestExample")
+}
+
+func (s *SuitePropertyTester) PropertyDoubleIsEven(t *propT) {
+	s.checks++
+	if (2*t.n)%2 != 0 {
+		t.Errorf("2*%d is odd", t.n)
+	}
+}
+
+func (s *SuitePropertyTester) PropertySmall(t *propT) {
+	if t.n > 5 {
+		t.Errorf("%d is too large", t.n)
+	}
+}
+
+// PropertyOther takes an argument no property checker handles, so it isn't
+// run.
+func (s *SuitePropertyTester) PropertyOther(t fmt.Stringer) {}
+
+func TestWithProperties(t *testing.T) {
+	s := new(SuitePropertyTester)
+	reporter := &recordingReporter{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReporter(reporter), WithProperties(checkProperty))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, 10, s.checks)
+	assert.Equal(t, []string{
+		"SetupTest DetachedSuite/PropertyDoubleIsEven", "TearDownTest",
+		"SetupTest DetachedSuite/PropertySmall", "TearDownTest",
+		"SetupTest DetachedSuite/TestExample", "TestExample", "TearDownTest",
+	}, s.calls)
+	assert.Contains(t, output, "6 is too large")
+	assert.Contains(t, output, "property falsified for 6")
+	assert.Equal(t, []string{
+		"SuitePropertyTester.PropertyDoubleIsEven",
+		"SuitePropertyTester.PropertySmall",
+		"SuitePropertyTester.TestExample",
+	}, reporter.Started)
+}
+
+func TestPropertiesNotRunWithoutChecker(t *testing.T) {
+	s := new(SuitePropertyTester)
+	Run(t, s)
+	assert.Zero(t, s.checks)
+}
@@ -0,0 +1,100 @@
+// Package protoassert contains assertions for comparing protocol buffer
+// messages in suites.
+//
+// Assertions based on reflect.DeepEqual, like those of testify, compare the
+// internal state of generated messages and therefore report false
+// negatives and unreadable failures. The assertions of this package compare
+// messages with proto.Equal and report a field-level diff:
+//
+//	func (s *ExampleTestSuite) TestGetUser() {
+//	    got, err := s.client.GetUser(s.Context(), &pb.GetUserRequest{Id: 1})
+//	    require.NoError(s.T(), err)
+//	    protoassert.Equal(s.T(), &pb.User{Id: 1, Name: "gopher"}, got)
+//	}
+//
+// The package lives apart from package suite so that suites which don't use
+// protocol buffers don't depend on them.
+package protoassert
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// TestingT is the subset of *testing.T used by the assertions.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Equal asserts that two messages are equal according to proto.Equal. On
+// failure the differing fields are reported.
+func Equal(t TestingT, expected, actual proto.Message, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if proto.Equal(expected, actual) {
+		return true
+	}
+	diff := cmp.Diff(expected, actual, protocmp.Transform())
+	t.Errorf("Messages are not equal (-expected +actual):\n%s%s", diff, formatMessage(msgAndArgs))
+	return false
+}
+
+// NotEqual asserts that two messages are not equal according to
+// proto.Equal.
+func NotEqual(t TestingT, expected, actual proto.Message, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if !proto.Equal(expected, actual) {
+		return true
+	}
+	t.Errorf("Messages should not be equal: %v%s", actual, formatMessage(msgAndArgs))
+	return false
+}
+
+// ElementsMatch asserts that two slices contain equal messages, ignoring
+// their order.
+func ElementsMatch(t TestingT, expected, actual []proto.Message, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	diff := cmp.Diff(sorted(expected), sorted(actual), protocmp.Transform())
+	if diff == "" {
+		return true
+	}
+	t.Errorf("Elements do not match (-expected +actual):\n%s%s", diff, formatMessage(msgAndArgs))
+	return false
+}
+
+// sorted returns a copy of messages sorted by their text representation,
+// so that they can be compared regardless of their order.
+func sorted(messages []proto.Message) []proto.Message {
+	s := append([]proto.Message(nil), messages...)
+	sort.SliceStable(s, func(i, j int) bool {
+		return prototext.Format(s[i]) < prototext.Format(s[j])
+	})
+	return s
+}
+
+func formatMessage(msgAndArgs []interface{}) string {
+	switch len(msgAndArgs) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("\nMessages: %v", msgAndArgs[0])
+	}
+	if format, ok := msgAndArgs[0].(string); ok {
+		return "\nMessages: " + fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return "\nMessages: " + fmt.Sprint(msgAndArgs...)
+}
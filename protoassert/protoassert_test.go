@@ -0,0 +1,45 @@
+package protoassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	expected, _ := structpb.NewStruct(map[string]interface{}{"name": "gopher", "age": 10})
+	same, _ := structpb.NewStruct(map[string]interface{}{"age": 10, "name": "gopher"})
+	different, _ := structpb.NewStruct(map[string]interface{}{"name": "gopher", "age": 11})
+
+	rt := new(recordingT)
+	assert.True(t, Equal(rt, expected, same))
+	assert.False(t, NotEqual(rt, expected, same, "call %d", 1))
+	assert.False(t, Equal(rt, expected, different))
+	assert.True(t, NotEqual(rt, expected, different))
+
+	if assert.Len(t, rt.errors, 2) {
+		assert.Contains(t, rt.errors[0], "Messages: call 1")
+		assert.Contains(t, rt.errors[1], "-expected +actual")
+		assert.Contains(t, rt.errors[1], "11")
+	}
+}
+
+func TestElementsMatch(t *testing.T) {
+	rt := new(recordingT)
+	a, b, c := wrapperspb.String("a"), wrapperspb.String("b"), wrapperspb.String("c")
+	assert.True(t, ElementsMatch(rt, []proto.Message{a, b}, []proto.Message{b, a}))
+	assert.False(t, ElementsMatch(rt, []proto.Message{a, b}, []proto.Message{c, a}))
+	assert.Len(t, rt.errors, 1)
+}
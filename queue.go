@@ -0,0 +1,117 @@
+package suite
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// QueueFixture is a message queue, such as a topic of a message broker.
+// Code under test publishing or consuming messages through this interface,
+// or through an adapter of it, can be tested against the in-memory
+// MemQueue of every test, see Suite.Queue, while adapters of real brokers
+// implement it for integration runs, see WithQueue.
+type QueueFixture interface {
+	Publish(ctx context.Context, topic string, body []byte) error
+	// Consume returns the oldest message of topic not consumed yet,
+	// waiting for one until ctx is done.
+	Consume(ctx context.Context, topic string) ([]byte, error)
+	// Purge drops the messages of topic not consumed yet.
+	Purge(ctx context.Context, topic string) error
+}
+
+// WithQueue sets the function creating the queue returned by Suite.Queue
+// for every test, MemQueueFixture by default, e.g. to run a suite against
+// a real broker:
+//
+//	suite.Run(t, new(PipelineSuite), suite.WithQueue(newKafkaQueue))
+//
+// The function can register the cleanup of the queue with t.Cleanup.
+func WithQueue(newQueue func(t *testing.T) QueueFixture) Option {
+	return func(o *options) {
+		o.newQueue = newQueue
+	}
+}
+
+// MemQueueFixture returns a new in-memory queue.
+func MemQueueFixture(t *testing.T) QueueFixture {
+	return NewMemQueue()
+}
+
+// Queue returns the queue of the current test, created by the function
+// set with WithQueue on the first call of each test, so that tests don't
+// see each other's messages.
+func (suite *Suite) Queue() QueueFixture {
+	if suite.test.queue == nil {
+		newQueue := suite.newQueue
+		if newQueue == nil {
+			newQueue = MemQueueFixture
+		}
+		suite.test.queue = newQueue(suite.T())
+	}
+	return suite.test.queue
+}
+
+// MemQueue is an in-memory QueueFixture. It also keeps every published
+// message, so that tests can assert on the messages emitted by the code
+// under test without consuming them. It is safe for concurrent use.
+type MemQueue struct {
+	mu        sync.Mutex
+	pending   map[string][][]byte
+	published map[string][][]byte
+	// changed is closed when a message is published.
+	changed chan struct{}
+}
+
+// NewMemQueue returns an empty queue.
+func NewMemQueue() *MemQueue {
+	return &MemQueue{
+		pending:   make(map[string][][]byte),
+		published: make(map[string][][]byte),
+		changed:   make(chan struct{}),
+	}
+}
+
+func (q *MemQueue) Publish(ctx context.Context, topic string, body []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	body = append([]byte(nil), body...)
+	q.pending[topic] = append(q.pending[topic], body)
+	q.published[topic] = append(q.published[topic], body)
+	close(q.changed)
+	q.changed = make(chan struct{})
+	return nil
+}
+
+func (q *MemQueue) Consume(ctx context.Context, topic string) ([]byte, error) {
+	for {
+		q.mu.Lock()
+		if pending := q.pending[topic]; len(pending) > 0 {
+			q.pending[topic] = pending[1:]
+			q.mu.Unlock()
+			return pending[0], nil
+		}
+		changed := q.changed
+		q.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (q *MemQueue) Purge(ctx context.Context, topic string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, topic)
+	return nil
+}
+
+// Published returns the messages published to topic, consumed or not, in
+// the order they were published.
+func (q *MemQueue) Published(topic string) [][]byte {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([][]byte(nil), q.published[topic]...)
+}
@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteQueueTester struct {
+	Suite
+	queues []QueueFixture
+}
+
+func (s *SuiteQueueTester) TestPublish() {
+	s.queues = append(s.queues, s.Queue())
+	go s.Queue().Publish(s.Context(), "orders", []byte("order-1"))
+	body, err := s.Queue().Consume(s.Context(), "orders")
+	s.Require().NoError(err)
+	s.Equal("order-1", string(body))
+	s.Require().NoError(s.Queue().Publish(s.Context(), "orders", []byte("order-2")))
+}
+
+func (s *SuiteQueueTester) TestIsolated() {
+	s.queues = append(s.queues, s.Queue())
+	ctx, cancel := context.WithTimeout(s.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.Queue().Consume(ctx, "orders")
+	s.Assert().ErrorIs(err, context.DeadlineExceeded, "messages of other tests aren't delivered")
+}
+
+func TestQueue(t *testing.T) {
+	s := new(SuiteQueueTester)
+	Run(t, s)
+	require.Len(t, s.queues, 2)
+	assert.NotSame(t, s.queues[0], s.queues[1])
+	assert.Equal(t, [][]byte{[]byte("order-1"), []byte("order-2")}, s.queues[1].(*MemQueue).Published("orders"))
+
+	var created []string
+	s = new(SuiteQueueTester)
+	Run(t, s, WithQueue(func(t *testing.T) QueueFixture {
+		created = append(created, t.Name())
+		return NewMemQueue()
+	}))
+	assert.Equal(t, []string{"TestQueue/TestIsolated#01", "TestQueue/TestPublish#01"}, created)
+}
+
+func TestMemQueuePurge(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemQueue()
+	require.NoError(t, q.Publish(ctx, "a", []byte("1")))
+	require.NoError(t, q.Publish(ctx, "b", []byte("2")))
+	require.NoError(t, q.Purge(ctx, "a"))
+	body, err := q.Consume(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(body))
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = q.Consume(canceled, "a")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, q.Published("a"), 1)
+}
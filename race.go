@@ -0,0 +1,13 @@
+//go:build race
+
+package suite
+
+import "runtime"
+
+// raceEnabled reports whether the test binary was built with -race.
+const raceEnabled = true
+
+// raceErrors returns the number of data races reported so far.
+func raceErrors() int {
+	return runtime.RaceErrors()
+}
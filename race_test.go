@@ -0,0 +1,42 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteRaceTester struct {
+	Suite
+}
+
+func (s *SuiteRaceTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{
+		"TestSlow": {Tags: []string{"norace"}},
+	}
+}
+
+func (s *SuiteRaceTester) TestSlow() {}
+
+func (s *SuiteRaceTester) TestFast() {}
+
+func TestRaceAwareReporting(t *testing.T) {
+	reporter := &recordingReporter{}
+	ok, _, err := runDetachedSuiteWithOutputCapture(new(SuiteRaceTester), WithReporter(reporter))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	if assert.Len(t, reporter.Suites, 1) {
+		assert.Equal(t, raceEnabled, reporter.Suites[0].Race)
+	}
+	statuses := map[string]Status{}
+	for _, result := range reporter.Finished {
+		statuses[result.Name] = result.Status
+		assert.False(t, result.RaceDetected)
+	}
+	expected := map[string]Status{"TestSlow": StatusPassed, "TestFast": StatusPassed}
+	if raceEnabled {
+		expected["TestSlow"] = StatusSkipped
+	}
+	assert.Equal(t, expected, statuses)
+}
@@ -0,0 +1,50 @@
+package suite
+
+import (
+	"flag"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+var seedFlag = flag.Int64("testify.seed", 0, "seed of the random sources of testify suites (default: random per process)")
+
+// processSeed is the seed used when none is given, shared by all suites of
+// the test binary so that a single value reproduces a whole run.
+var processSeed = time.Now().UnixNano()
+
+// WithSeed sets the seed from which the random sources returned by
+// Suite.Rand are derived. It overrides the -testify.seed flag.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+		o.seedOption = true
+	}
+}
+
+// seedFromFlags returns the -testify.seed flag if it was set, and the
+// per-process random seed otherwise.
+func seedFromFlags() int64 {
+	seed := processSeed
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "testify.seed" {
+			seed = *seedFlag
+		}
+	})
+	return seed
+}
+
+// Rand returns a random source for the current test. It is seeded
+// deterministically from the suite's seed and the name of the test, so
+// that a test sees the same random values whenever it is run with the same
+// seed, regardless of which other tests run. The seed is logged when a test
+// that used Rand fails.
+func (suite *Suite) Rand() *rand.Rand {
+	if suite.rand == nil {
+		h := fnv.New64a()
+		h.Write([]byte(suite.T().Name()))
+		suite.rand = rand.New(rand.NewSource(suite.seed ^ int64(h.Sum64())))
+		suite.test.randUsed = true
+	}
+	return suite.rand
+}
@@ -0,0 +1,45 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteRandTester struct {
+	Suite
+
+	Values map[string]int64
+}
+
+func (s *SuiteRandTester) TestOne() {
+	s.Values[s.T().Name()] = s.Rand().Int63()
+}
+
+func (s *SuiteRandTester) TestTwo() {
+	s.Values[s.T().Name()] = s.Rand().Int63()
+	s.T().Fail()
+}
+
+func TestSuiteRand(t *testing.T) {
+	first := &SuiteRandTester{Values: map[string]int64{}}
+	_, output, err := runDetachedSuiteWithOutputCapture(first, WithSeed(42))
+	require.NoError(t, err)
+	assert.Contains(t, output, "suite: test used random seed 42 set with WithSeed, change the WithSeed call to run it with another seed")
+	assert.NotContains(t, output, "-testify.seed=42", "the flag doesn't override WithSeed")
+	assert.NotEqual(t, first.Values["DetachedSuite/TestOne"], first.Values["DetachedSuite/TestTwo"])
+
+	second := &SuiteRandTester{Values: map[string]int64{}}
+	runDetachedSuiteWithOutputCapture(second, WithSeed(42))
+	assert.Equal(t, first.Values, second.Values, "the same seed must produce the same values")
+
+	third := &SuiteRandTester{Values: map[string]int64{}}
+	runDetachedSuiteWithOutputCapture(third, WithSeed(43))
+	assert.NotEqual(t, first.Values, third.Values)
+
+	_, output, err = runDetachedSuiteWithOutputCapture(&SuiteRandTester{Values: map[string]int64{}})
+	require.NoError(t, err)
+	assert.Contains(t, output, fmt.Sprintf("suite: test used random seed %d, rerun with -testify.seed=%d", processSeed, processSeed))
+}
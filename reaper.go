@@ -0,0 +1,232 @@
+package suite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var reaperDir = flag.String("testify.reaper-dir", "", "record the external resources of testify tests in this directory, for cmd/suitereap (default: go-suite-reaper in the user's cache directory)")
+
+// defaultReapTTL is the time after which resources registered without a
+// TTL are considered orphaned.
+const defaultReapTTL = time.Hour
+
+// WithReaperDir sets the directory in which the external resources
+// registered with Suite.Reap are recorded until they are deleted, so that
+// cmd/suitereap can delete the resources left behind by crashed runs. It
+// defaults to the -testify.reaper-dir flag, or to go-suite-reaper in the
+// user's cache directory, see DefaultReaperDir. CI jobs sharing an
+// environment, e.g. a staging cluster, should share the directory too, as
+// long as they run as the same user: cmd/suitereap doesn't run the delete
+// commands recorded by other users.
+func WithReaperDir(dir string) Option {
+	return func(o *options) {
+		o.reaperDir = dir
+	}
+}
+
+// Resource is an external resource created by a test, such as a bucket or
+// a namespace in a staging environment, see Suite.Reap.
+type Resource struct {
+	// Name describes the resource, e.g. "bucket e2e-4f2a".
+	Name string
+	// Delete deletes the resource once the test has finished.
+	Delete func(ctx context.Context) error
+	// Command is the command deleting the resource, run by cmd/suitereap
+	// if the test run crashed before Delete was called, e.g.
+	// []string{"gsutil", "rm", "-r", "gs://e2e-4f2a"}. Without a command,
+	// cmd/suitereap only reports the orphaned resource.
+	Command []string
+	// TTL is the time after which cmd/suitereap considers the resource
+	// orphaned, one hour by default. It should exceed the duration of the
+	// test run.
+	TTL time.Duration
+}
+
+// Reap registers the external resource r, deleting it when the current
+// test finishes, or the suite when called in SetupSuite, unless the
+// retention policy of the suite keeps it for inspection, see Dispose.
+// Until then, the resource is recorded in the directory set with
+// WithReaperDir, from which cmd/suitereap deletes the resources of runs
+// that crashed or kept them once their TTL has expired.
+func (suite *Suite) Reap(r Resource) {
+	t := suite.T()
+	t.Helper()
+	entry, err := recordResource(suite.reaperDirectory(), t.Name(), r)
+	if err != nil {
+		t.Fatalf("suite: failed to record %s: %v", r.Name, err)
+	}
+	suite.disposeFor(t, r.Name, RetainDefault, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := r.Delete(ctx); err != nil {
+			t.Errorf("suite: failed to delete %s, left for suitereap: %v", r.Name, err)
+			return
+		}
+		if err := entry.Forget(); err != nil {
+			t.Logf("suite: %v", err)
+		}
+	})
+}
+
+// reaperDirectory returns the directory in which external resources are
+// recorded.
+func (suite *Suite) reaperDirectory() string {
+	if suite.reaperDir != "" {
+		return suite.reaperDir
+	}
+	return DefaultReaperDir()
+}
+
+// DefaultReaperDir returns the directory in which external resources are
+// recorded when none is set with WithReaperDir or -testify.reaper-dir:
+// go-suite-reaper in the user's cache directory, or a directory of the
+// user in the temporary directory if there is none. It is private to the
+// user, as the commands recorded in it are run by cmd/suitereap.
+func DefaultReaperDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "go-suite-reaper")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("go-suite-reaper-%d", os.Getuid()))
+}
+
+// ReaperEntry is the record of an external resource registered with
+// Suite.Reap that wasn't deleted yet.
+type ReaperEntry struct {
+	Name    string    `json:"name"`
+	Test    string    `json:"test"`
+	Command []string  `json:"command,omitempty"`
+	Host    string    `json:"host"`
+	PID     int       `json:"pid"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+	// path is the file recording the resource.
+	path string
+	// foreign is set if the file is owned by another user.
+	foreign bool
+}
+
+// recordResource writes the record of the resource r created by test to
+// dir.
+func recordResource(dir, test string, r Resource) (*ReaperEntry, error) {
+	if r.Delete == nil {
+		return nil, errors.New("Delete is nil")
+	}
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = defaultReapTTL
+	}
+	host, _ := os.Hostname()
+	now := time.Now()
+	entry := &ReaperEntry{
+		Name:    r.Name,
+		Test:    test,
+		Command: r.Command,
+		Host:    host,
+		PID:     os.Getpid(),
+		Created: now,
+		Expires: now.Add(ttl),
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	entry.path = filepath.Join(dir, hex.EncodeToString(id[:])+".json")
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	tmp := entry.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return nil, err
+	}
+	return entry, os.Rename(tmp, entry.path)
+}
+
+// ReaperEntries returns the resources recorded in dir, oldest first.
+func ReaperEntries(dir string) ([]*ReaperEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []*ReaperEntry
+	for _, path := range paths {
+		data, owned, err := readOwnedFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Deleted by its test in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := &ReaperEntry{path: path, foreign: !owned}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+	return entries, nil
+}
+
+// Expired reports whether the TTL of the resource has expired at now, so
+// that it is considered orphaned.
+func (e *ReaperEntry) Expired(now time.Time) bool {
+	return !now.Before(e.Expires)
+}
+
+// Reap runs the command deleting the resource and forgets it if the
+// command succeeds. It refuses to run the command of a resource recorded
+// by another user, which could have recorded any command.
+func (e *ReaperEntry) Reap(ctx context.Context) error {
+	if e.foreign {
+		return fmt.Errorf("%s was recorded by another user in %s, delete it manually", e.Name, e.path)
+	}
+	if len(e.Command) == 0 {
+		return fmt.Errorf("%s has no delete command, delete it manually", e.Name)
+	}
+	out, err := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %s: %v\n%s", e.Name, strings.Join(e.Command, " "), err, out)
+	}
+	return e.Forget()
+}
+
+// Forget removes the record of the resource, once it has been deleted.
+func (e *ReaperEntry) Forget() error {
+	if err := os.Remove(e.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to forget %s: %v", e.Name, err)
+	}
+	return nil
+}
+
+// readOwnedFile returns the content of the file path and whether it is
+// owned by the current user.
+func readOwnedFile(path string) ([]byte, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := io.ReadAll(f)
+	return data, ownedByCurrentUser(info), err
+}
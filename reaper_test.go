@@ -0,0 +1,105 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteReaperTester struct {
+	Suite
+	deleted []string
+	entries int
+}
+
+func (s *SuiteReaperTester) resource(name string, err error) Resource {
+	return Resource{
+		Name: name,
+		Delete: func(ctx context.Context) error {
+			s.deleted = append(s.deleted, name)
+			return err
+		},
+		TTL: time.Minute,
+	}
+}
+
+func (s *SuiteReaperTester) SetupSuite() {
+	s.Reap(s.resource("namespace", nil))
+}
+
+func (s *SuiteReaperTester) TestA_Deleted() {
+	s.Reap(s.resource("bucket", nil))
+	entries, err := ReaperEntries(s.reaperDir)
+	s.Require().NoError(err)
+	s.entries = len(entries)
+}
+
+func (s *SuiteReaperTester) TestB_FailedDelete() {
+	s.Reap(s.resource("topic", errors.New("permission denied")))
+}
+
+func TestReap(t *testing.T) {
+	dir := t.TempDir()
+	s := new(SuiteReaperTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReaperDir(dir), WithRetention(RetainNever))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: failed to delete topic, left for suitereap: permission denied")
+	assert.Equal(t, []string{"bucket", "topic", "namespace"}, s.deleted)
+	assert.Equal(t, 2, s.entries)
+
+	entries, err := ReaperEntries(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "topic", entry.Name)
+	assert.Equal(t, "DetachedSuite/TestB_FailedDelete", entry.Test)
+	assert.Equal(t, time.Minute, entry.Expires.Sub(entry.Created))
+	assert.False(t, entry.Expired(entry.Created))
+	assert.True(t, entry.Expired(entry.Expires))
+	assert.EqualError(t, entry.Reap(context.Background()), "topic has no delete command, delete it manually")
+	require.NoError(t, entry.Forget())
+	entries, err = ReaperEntries(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestReaperDirIsPrivate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reaper")
+	entry, err := recordResource(dir, "TestA", Resource{Name: "bucket", Delete: func(context.Context) error { return nil }})
+	require.NoError(t, err)
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	info, err = os.Stat(entry.path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestReapForeignEntry(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() != 0 {
+		t.Skip("changing the owner of a file requires root")
+	}
+	dir := t.TempDir()
+	entry, err := recordResource(dir, "TestA", Resource{
+		Name:    "bucket",
+		Delete:  func(context.Context) error { return nil },
+		Command: []string{"true"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.Chown(entry.path, 4242, 4242))
+
+	entries, err := ReaperEntries(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.EqualError(t, entries[0].Reap(context.Background()), "bucket was recorded by another user in "+entry.path+", delete it manually")
+	_, err = os.Stat(entry.path)
+	assert.NoError(t, err)
+}
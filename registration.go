@@ -0,0 +1,57 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+)
+
+// registeredHooks are the hooks registered with BeforeAllTests, BeforeEach
+// and AfterEach.
+type registeredHooks struct {
+	beforeAll  []func()
+	beforeEach []func()
+	afterEach  []func()
+}
+
+// BeforeAllTests registers fn to be called once before the first test of
+// the suite, after the SetupSuite hooks. Functions registered with
+// BeforeAllTests are called in registration order.
+//
+// BeforeAllTests, BeforeEach and AfterEach let helper libraries attach
+// behavior to a suite without the suite implementing more hook interfaces.
+// They are typically called in SetupSuite.
+func (suite *Suite) BeforeAllTests(fn func()) {
+	suite.registered.beforeAll = append(suite.registered.beforeAll, fn)
+}
+
+// BeforeEach registers fn to be called before every test, after the
+// SetupTest and BeforeTest hooks. Functions registered with BeforeEach are
+// called in registration order.
+func (suite *Suite) BeforeEach(fn func()) {
+	suite.registered.beforeEach = append(suite.registered.beforeEach, fn)
+}
+
+// AfterEach registers fn to be called after every test, before the
+// AfterTest and TearDownTest hooks. Functions registered with AfterEach are
+// called in reverse registration order, like deferred calls.
+func (suite *Suite) AfterEach(fn func()) {
+	suite.registered.afterEach = append(suite.registered.afterEach, fn)
+}
+
+// callRegistered calls the functions registered under name, in order or in
+// reverse.
+func (r *suiteRun) callRegistered(t *testing.T, name string, reverse bool, hooks func(registered registeredHooks) []func()) {
+	t.Helper()
+	b, ok := r.suite.(suiteBase)
+	if !ok {
+		return
+	}
+	fns := hooks(b.base().registered)
+	for i := range fns {
+		index := i
+		if reverse {
+			index = len(fns) - 1 - i
+		}
+		r.callHook(t, r.suiteName, fmt.Sprintf("%s#%d", name, index+1), fns[index])
+	}
+}
@@ -0,0 +1,51 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteRegistrationTester struct {
+	Suite
+	calls []string
+}
+
+func (s *SuiteRegistrationTester) record(call string) func() {
+	return func() { s.calls = append(s.calls, call) }
+}
+
+func (s *SuiteRegistrationTester) SetupSuite() {
+	s.BeforeAllTests(s.record("BeforeAllTests 1"))
+	s.BeforeAllTests(s.record("BeforeAllTests 2"))
+	s.BeforeEach(s.record("BeforeEach 1"))
+	s.BeforeEach(s.record("BeforeEach 2"))
+	s.AfterEach(s.record("AfterEach 1"))
+	s.AfterEach(s.record("AfterEach 2"))
+}
+
+func (s *SuiteRegistrationTester) SetupTest() {
+	s.calls = append(s.calls, "SetupTest")
+}
+
+func (s *SuiteRegistrationTester) TearDownTest() {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *SuiteRegistrationTester) TestA() {
+	s.calls = append(s.calls, "TestA")
+}
+
+func (s *SuiteRegistrationTester) TestB() {
+	s.calls = append(s.calls, "TestB")
+}
+
+func TestRegisteredHooks(t *testing.T) {
+	s := new(SuiteRegistrationTester)
+	Run(t, s)
+	assert.Equal(t, []string{
+		"BeforeAllTests 1", "BeforeAllTests 2",
+		"SetupTest", "BeforeEach 1", "BeforeEach 2", "TestA", "AfterEach 2", "AfterEach 1", "TearDownTest",
+		"SetupTest", "BeforeEach 1", "BeforeEach 2", "TestB", "AfterEach 2", "AfterEach 1", "TearDownTest",
+	}, s.calls)
+}
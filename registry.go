@@ -0,0 +1,134 @@
+package suite
+
+import (
+	"os"
+	"sync"
+	"testing"
+)
+
+// registry keeps track of the state shared by all suites of the test
+// binary.
+var registry = &suiteRegistry{
+	fixtures: make(map[string]*sharedEntry),
+}
+
+type suiteRegistry struct {
+	mu       sync.Mutex
+	fixtures map[string]*sharedEntry
+	// inMain is set while Main runs the tests of the package, and holds a
+	// reference to every shared fixture until they have all run.
+	inMain bool
+	// results are the results of the suites that finished while Main ran.
+	results []SuiteResult
+}
+
+// sharedEntry is a shared fixture and the number of suites holding it.
+type sharedEntry struct {
+	mu      sync.Mutex
+	created bool
+	value   interface{}
+	destroy func()
+	refs    int
+}
+
+// Main runs the tests of the package like m.Run does, and exits with its
+// result. It is called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		suite.Main(m)
+//	}
+//
+// While Main runs, shared fixtures (see SharedFixture) are kept alive until
+// all tests of the package have run, so that suites running one after
+// another share them instead of each creating their own.
+//
+// Main also aggregates the results of all suites of the package. The
+// -testify.package-report flag writes them to a JSON file once all tests
+// have run, with the totals of the package and its slowest tests, and the
+// -testify.summary flag prints the summary of the package after those of
+// its suites.
+func Main(m *testing.M) {
+	registry.mu.Lock()
+	registry.inMain = true
+	registry.mu.Unlock()
+
+	code := m.Run()
+
+	registry.mu.Lock()
+	registry.inMain = false
+	result := PackageResult{Name: packageName(), Suites: registry.results}
+	registry.results = nil
+	var idle []*sharedEntry
+	for name, entry := range registry.fixtures {
+		if entry.refs == 0 {
+			idle = append(idle, entry)
+			delete(registry.fixtures, name)
+		}
+	}
+	registry.mu.Unlock()
+	for _, entry := range idle {
+		entry.close()
+	}
+	reportPackage(result)
+	os.Exit(code)
+}
+
+// record keeps the result of a finished suite while Main runs.
+func (r *suiteRegistry) record(result SuiteResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.inMain {
+		r.results = append(r.results, result)
+	}
+}
+
+// acquire returns the shared fixture name, creating it with create if
+// needed, and adds a reference to it.
+func (r *suiteRegistry) acquire(name string, create func() (interface{}, func(), error)) (interface{}, error) {
+	r.mu.Lock()
+	entry, ok := r.fixtures[name]
+	if !ok {
+		entry = &sharedEntry{}
+		r.fixtures[name] = entry
+	}
+	entry.refs++
+	r.mu.Unlock()
+
+	entry.mu.Lock()
+	if !entry.created {
+		value, destroy, err := create()
+		if err != nil {
+			entry.mu.Unlock()
+			r.release(name)
+			return nil, err
+		}
+		entry.value, entry.destroy, entry.created = value, destroy, true
+	}
+	value := entry.value
+	entry.mu.Unlock()
+	return value, nil
+}
+
+// release drops a reference to the shared fixture name, destroying it when
+// it was the last one, unless Main is keeping it alive.
+func (r *suiteRegistry) release(name string) {
+	r.mu.Lock()
+	entry := r.fixtures[name]
+	entry.refs--
+	if entry.refs > 0 || r.inMain {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.fixtures, name)
+	r.mu.Unlock()
+	entry.close()
+}
+
+func (e *sharedEntry) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.created && e.destroy != nil {
+		e.destroy()
+	}
+	e.created = false
+}
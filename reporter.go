@@ -0,0 +1,180 @@
+package suite
+
+import (
+	"flag"
+	"os"
+	"time"
+)
+
+// Status is the outcome of a single suite test.
+type Status int
+
+const (
+	// StatusPassed is reported for tests that neither failed nor skipped.
+	StatusPassed Status = iota
+	// StatusFailed is reported for tests that were marked as failed.
+	StatusFailed
+	// StatusSkipped is reported for tests that were skipped.
+	StatusSkipped
+	// StatusPending is reported for pending tests, whose method names
+	// start with X or Pending followed by a test prefix, e.g. XTestLogin.
+	// Pending tests are skipped without running them or their hooks.
+	StatusPending
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPassed:
+		return "pass"
+	case StatusFailed:
+		return "fail"
+	case StatusSkipped:
+		return "skip"
+	case StatusPending:
+		return "pending"
+	}
+	return "unknown"
+}
+
+// TestResult describes the outcome of a single test of a suite.
+type TestResult struct {
+	// Suite is the name of the suite type.
+	Suite string
+	// Name is the name of the test method.
+	Name string
+	// FullName is the full name of the subtest, as reported by go test.
+	FullName string
+	Status   Status
+	// Duration is the wall time of the test, including its setup and
+	// teardown hooks.
+	Duration time.Duration
+	// CPU is the CPU time the process spent over Duration, on systems
+	// where it is available. It is zero for the tests of suites running
+	// them in parallel, see WithParallel, as the CPU time of the process
+	// can't be attributed to them.
+	CPU CPUTime
+	// SkipReason is the reason given to the suite's skip helpers, such as
+	// SkipIfShort, for skipping the test.
+	SkipReason string
+	// SkipCategory classifies the reason for skipping the test, see
+	// SkipCategory. It is SkipExplicit for tests skipped without the skip
+	// helpers of Suite.
+	SkipCategory SkipCategory
+	// Assertions is the number of assertions the test made through the
+	// assertion helpers of Suite, such as Equal and JSONEq, and through
+	// Assert and Require.
+	Assertions int
+	// RaceDetected reports whether the race detector reported a data race
+	// while the test ran.
+	RaceDetected bool
+	// Allocs are the heap allocations made by the test method, excluding
+	// its setup and teardown hooks. Like CPU, they are zero for tests
+	// running in parallel.
+	Allocs Allocs
+	// Failures are the messages of the failures reported through the
+	// suite: those of its assertion helpers, such as Equal and Eventually,
+	// of Assert and Require, and the panics of the test and its hooks.
+	// Failures reported on the test's T directly, e.g. with t.Error,
+	// aren't included.
+	Failures []string
+	Metadata Metadata
+}
+
+// SuiteResult describes the outcome of a whole suite run.
+type SuiteResult struct {
+	// Name is the name of the suite type.
+	Name string
+	// Tests are the results of the tests of the suite, sorted by name
+	// whatever the order they ran in, so that reports of different runs
+	// can be compared.
+	Tests    []TestResult
+	Duration time.Duration
+	// SkipReason is the reason for skipping the whole suite, e.g. as
+	// returned by ConditionalSuite.
+	SkipReason string
+	// SkipCategory classifies the reason for skipping the whole suite, if
+	// it has one.
+	SkipCategory SkipCategory
+	// Race reports whether the test binary was built with -race.
+	Race bool
+	// Environment describes the conditions the suite ran in.
+	Environment Environment
+	// Memory is the peak memory usage of the suite, only measured with
+	// WithMemoryGate.
+	Memory MemoryUsage
+}
+
+// Failed reports whether any test of the suite failed.
+func (r SuiteResult) Failed() bool {
+	for _, test := range r.Tests {
+		if test.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+var jsonReport = flag.String("testify.json", "", "append a JSON report of every testify suite to this file")
+var junitDir = flag.String("testify.junit-dir", "", "write a JUnit XML report of every testify suite to this directory")
+var htmlDir = flag.String("testify.html-dir", "", "write an HTML report of every testify suite to this directory")
+var traceability = flag.String("testify.traceability", "", "write a CSV matrix of requirements and the tests verifying them to this file")
+
+// Reporter receives events while a suite runs. Reporters are called from
+// the goroutines running the suite's tests and must not assume that the
+// events of different suites are not interleaved.
+type Reporter interface {
+	SuiteStarted(suiteName string)
+	TestStarted(suiteName, testName string)
+	TestFinished(result TestResult)
+	SuiteFinished(result SuiteResult)
+}
+
+// WithReporter adds a reporter that is notified about the progress of
+// the suite.
+func WithReporter(reporter Reporter) Option {
+	return func(o *options) {
+		o.reporters = append(o.reporters, reporter)
+	}
+}
+
+// reportersFromFlags returns the reporters enabled on the command line.
+func reportersFromFlags() []Reporter {
+	var reporters []Reporter
+	if *progress {
+		reporters = append(reporters, NewProgressReporter(os.Stdout))
+	}
+	if *jsonReport != "" {
+		reporters = append(reporters, NewJSONReporter(&appendFile{path: *jsonReport}))
+	}
+	if *junitDir != "" {
+		reporters = append(reporters, NewJUnitReporter(*junitDir))
+	}
+	if *htmlDir != "" {
+		reporters = append(reporters, NewHTMLReporter(*htmlDir))
+	}
+	if *traceability != "" {
+		reporters = append(reporters, traceabilityFromFlags)
+	}
+	if *webhook != "" {
+		reporters = append(reporters, webhookFromFlags())
+	}
+	if *summary {
+		reporters = append(reporters, NewSummaryReporter(os.Stdout))
+	}
+	return reporters
+}
+
+// appendFile is a writer appending every write to a file, so that the
+// suites of several test binaries can report to the same file.
+type appendFile struct {
+	path string
+}
+
+func (f *appendFile) Write(p []byte) (int, error) {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return file.Write(p)
+}
@@ -0,0 +1,77 @@
+package suite
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter keeps all events it receives.
+type recordingReporter struct {
+	Started  []string
+	Finished []TestResult
+	Suites   []SuiteResult
+}
+
+func (r *recordingReporter) SuiteStarted(suiteName string) {}
+
+func (r *recordingReporter) TestStarted(suiteName, testName string) {
+	r.Started = append(r.Started, suiteName+"."+testName)
+}
+
+func (r *recordingReporter) TestFinished(result TestResult) {
+	r.Finished = append(r.Finished, result)
+}
+
+func (r *recordingReporter) SuiteFinished(result SuiteResult) {
+	r.Suites = append(r.Suites, result)
+}
+
+type SuiteReportingTester struct {
+	Suite
+}
+
+func (s *SuiteReportingTester) TestPass() {}
+
+func (s *SuiteReportingTester) TestFail() {
+	s.T().Fail()
+}
+
+func (s *SuiteReportingTester) TestSkip() {
+	s.T().Skip()
+}
+
+func TestReporters(t *testing.T) {
+	recorder := new(recordingReporter)
+	var progress bytes.Buffer
+	runDetachedSuiteWithOutputCapture(new(SuiteReportingTester), WithReporter(recorder), WithReporter(NewProgressReporter(&progress)))
+
+	assert.Equal(t, []string{"SuiteReportingTester.TestFail", "SuiteReportingTester.TestPass", "SuiteReportingTester.TestSkip"}, recorder.Started)
+	require.Len(t, recorder.Suites, 1)
+	assert.True(t, recorder.Suites[0].Failed())
+	require.Len(t, recorder.Finished, 3)
+	assert.Equal(t, StatusFailed, recorder.Finished[0].Status)
+	assert.Equal(t, StatusPassed, recorder.Finished[1].Status)
+	assert.Equal(t, StatusSkipped, recorder.Finished[2].Status)
+	assert.Equal(t, "DetachedSuite/TestPass", recorder.Finished[1].FullName)
+
+	assert.Contains(t, progress.String(), "✗ SuiteReportingTester.TestFail (")
+	assert.Contains(t, progress.String(), "✓ SuiteReportingTester.TestPass (")
+	assert.Regexp(t, `- SuiteReportingTester.TestSkip \(\S+\) \[explicit\]`, progress.String())
+	assert.NotContains(t, progress.String(), colorReset, "colors must only be used on terminals")
+}
+
+func TestReportersSortResults(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		recorder := new(recordingReporter)
+		runDetachedSuiteWithOutputCapture(new(SuiteReportingTester), WithReporter(recorder), WithShuffle(seed))
+		require.Len(t, recorder.Suites, 1)
+		var names []string
+		for _, test := range recorder.Suites[0].Tests {
+			names = append(names, test.Name)
+		}
+		assert.Equal(t, []string{"TestFail", "TestPass", "TestSkip"}, names, "seed %d", seed)
+	}
+}
@@ -0,0 +1,67 @@
+package suite
+
+import (
+	"flag"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// reproIgnoredFlags are the flags of the suite that don't affect the
+// outcome of tests, and are left out of reproduction commands.
+var reproIgnoredFlags = map[string]bool{
+	"testify.m":              true,
+	"testify.seed":           true,
+	"testify.progress":       true,
+	"testify.json":           true,
+	"testify.junit-dir":      true,
+	"testify.traceability":   true,
+	"testify.webhook":        true,
+	"testify.webhook-format": true,
+	"testify.update":         true,
+	"testify.artifact-dir":   true,
+	"testify.diagnostics":    true,
+}
+
+// reproCommand returns the go test command running only the test t of the
+// suite, for the test method name, with the configuration it ran with.
+func (r *suiteRun) reproCommand(t *testing.T, name string) string {
+	var elems []string
+	for _, elem := range strings.Split(t.Name(), "/") {
+		elems = append(elems, "^"+regexp.QuoteMeta(elem)+"$")
+	}
+	args := []string{"go", "test"}
+	if raceEnabled {
+		args = append(args, "-race")
+	}
+	if testing.Short() {
+		args = append(args, "-short")
+	}
+	args = append(args,
+		"-run", shellQuote(strings.Join(elems, "/")),
+		"-testify.m", shellQuote("^"+regexp.QuoteMeta(name)+"$"),
+	)
+	// A seed set with WithSeed applies to the command already, and can't
+	// be overridden by the flag.
+	if !r.opts.seedOption {
+		args = append(args, "-testify.seed="+strconv.FormatInt(r.opts.seed, 10))
+	}
+	var flags []string
+	flag.Visit(func(f *flag.Flag) {
+		if strings.HasPrefix(f.Name, "testify.") && !reproIgnoredFlags[f.Name] {
+			flags = append(flags, "-"+f.Name+"="+shellQuote(f.Value.String()))
+		}
+	})
+	sort.Strings(flags)
+	return strings.Join(append(args, flags...), " ")
+}
+
+// shellQuote quotes s for POSIX shells if needed.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,/:@+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
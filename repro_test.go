@@ -0,0 +1,44 @@
+package suite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteReproTester struct {
+	Suite
+}
+
+func (s *SuiteReproTester) TestFails() {
+	s.T().Fail()
+}
+
+func (s *SuiteReproTester) TestPasses() {}
+
+func TestReproCommand(t *testing.T) {
+	_, output, _ := runDetachedSuiteWithOutputCapture(new(SuiteReproTester), WithSeed(99),
+		WithNamer(func(suiteName, methodName string) string { return "it's " + methodName }))
+	race := ""
+	if raceEnabled {
+		race = "-race "
+	}
+	assert.Contains(t, output, "suite: reproduce with: go test "+race+`-run '^DetachedSuite$/^it'\''s_TestFails$' -testify.m '^TestFails$'`)
+	assert.NotContains(t, output, "-testify.seed", "the flag doesn't override WithSeed")
+	assert.Equal(t, 1, strings.Count(output, "reproduce with"))
+
+	_, output, _ = runDetachedSuiteWithOutputCapture(new(SuiteReproTester))
+	assert.Contains(t, output, fmt.Sprintf(`-testify.m '^TestFails$' -testify.seed=%d`, processSeed))
+
+	_, output, _ = runDetachedSuiteWithOutputCapture(new(SuiteReproTester), WithVerbosity(VerbosityQuiet))
+	assert.NotContains(t, output, "reproduce with")
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "-testify.seed=1", shellQuote("-testify.seed=1"))
+	assert.Equal(t, "''", shellQuote(""))
+	assert.Equal(t, "'^Test$'", shellQuote("^Test$"))
+	assert.Equal(t, `'a'\''b'`, shellQuote("a'b"))
+}
@@ -0,0 +1,118 @@
+package suite
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// resources are the external resources locked by tests, shared by all
+// suites of the test binary.
+var resources = &resourceLocks{locks: make(map[string]*resourceLock)}
+
+type resourceLocks struct {
+	mu    sync.Mutex
+	locks map[string]*resourceLock
+}
+
+// resourceLock is a lock held by at most one test at a time.
+type resourceLock struct {
+	sem chan struct{}
+	// holder is the name of the test holding the lock, if any.
+	holder string
+}
+
+// ResourceSuite has a Requires method returning the names of the external
+// resources the suite needs exclusive access to, see Suite.LockResource.
+// They are locked before SetupSuite, in sorted order, and released after
+// TearDownSuite.
+type ResourceSuite interface {
+	Requires() []string
+}
+
+// LockResource gives the current test exclusive access to the external
+// resource name, e.g. a database or a device shared by all tests, until it
+// finishes: other tests locking the resource, of this suite or of others,
+// wait until then. When called in SetupSuite, the suite and all of its
+// tests hold the resource until the suite has finished.
+//
+// Tests locking several resources should lock them in the same order to
+// avoid deadlocks.
+func (suite *Suite) LockResource(name string) {
+	t := suite.T()
+	t.Helper()
+	if release := resources.lock(name, t.Name()); release != nil {
+		t.Cleanup(release)
+	}
+}
+
+// lockRequired locks the resources required by the suite, returning the
+// function releasing them.
+func (r *suiteRun) lockRequired() func() {
+	s, ok := r.suite.(ResourceSuite)
+	if !ok {
+		return func() {}
+	}
+	names := append([]string(nil), s.Requires()...)
+	sort.Strings(names)
+	var releases []func()
+	for _, name := range names {
+		if release := resources.lock(name, r.suiteT.Name()); release != nil {
+			releases = append(releases, release)
+		}
+	}
+	return func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+}
+
+// lock waits until the resource name is free and locks it for the test
+// named test, returning the function releasing it, or nil if the test or
+// one of its parents already holds it.
+func (l *resourceLocks) lock(name, test string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &resourceLock{sem: make(chan struct{}, 1)}
+		l.locks[name] = lock
+	}
+	holder := lock.holder
+	l.mu.Unlock()
+	if holder != "" && (test == holder || strings.HasPrefix(test, holder+"/")) {
+		return nil
+	}
+
+	lock.sem <- struct{}{}
+	l.mu.Lock()
+	lock.holder = test
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		lock.holder = ""
+		l.mu.Unlock()
+		<-lock.sem
+	}
+}
+
+// lockSharedFixture gives the current test of a suite running in parallel
+// exclusive access to the fixtures of the suite shared by its tests, such
+// as the blob store and the mail sink, until it finishes. It reports
+// whether the fixture whose per-test flag is locked wasn't locked by the
+// test yet, for the caller to prepare it for the test. It does nothing
+// when the tests don't run in parallel, as the hooks of the fixtures
+// prepare them then.
+func (suite *Suite) lockSharedFixture(locked *bool) bool {
+	t := suite.T()
+	t.Helper()
+	if !suite.parallel || t == suite.suiteT || *locked {
+		return false
+	}
+	if !suite.test.fixturesLocked {
+		suite.LockResource("suite:fixtures:" + suite.suiteT.Name())
+		suite.test.fixturesLocked = true
+	}
+	*locked = true
+	return true
+}
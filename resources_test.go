@@ -0,0 +1,86 @@
+package suite
+
+import (
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// database tracks the tests using a fake external database.
+var database = struct {
+	mu          sync.Mutex
+	users, peak int
+}{}
+
+func useDatabase() {
+	database.mu.Lock()
+	database.users++
+	database.peak = max(database.peak, database.users)
+	database.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	database.mu.Lock()
+	database.users--
+	database.mu.Unlock()
+}
+
+type SuiteResourceTester struct {
+	Suite
+}
+
+func (s *SuiteResourceTester) TestOne() {
+	s.LockResource("database")
+	useDatabase()
+}
+
+func (s *SuiteResourceTester) TestTwo() {
+	s.LockResource("database")
+	// Locking a resource again doesn't wait for itself.
+	s.LockResource("database")
+	useDatabase()
+}
+
+type SuiteRequiresTester struct {
+	Suite
+}
+
+func (s *SuiteRequiresTester) Requires() []string {
+	return []string{"database"}
+}
+
+func (s *SuiteRequiresTester) TestOne() {
+	// The suite already holds the resource.
+	s.LockResource("database")
+	useDatabase()
+}
+
+func (s *SuiteRequiresTester) TestTwo() {
+	useDatabase()
+}
+
+func TestLockResource(t *testing.T) {
+	parallel := flag.Lookup("test.parallel").Value
+	defer parallel.Set(parallel.String())
+	parallel.Set("4")
+	database.peak = 0
+
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		for _, s := range []TestingSuite{new(SuiteResourceTester), new(SuiteRequiresTester), new(SuiteResourceTester)} {
+			s := s
+			t.Run(typeName(s), func(t *testing.T) {
+				t.Parallel()
+				var opts []Option
+				if _, ok := s.(ResourceSuite); !ok {
+					// The tests of a suite requiring a resource share it.
+					opts = append(opts, WithParallel())
+				}
+				Run(t, s, opts...)
+			})
+		}
+	})
+	assert.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 1, database.peak)
+}
@@ -0,0 +1,124 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// Retention is the policy deciding whether the resources of a test, such
+// as temporary directories or containers, are kept for inspection after
+// the test or removed.
+type Retention int
+
+const (
+	// RetainDefault applies the policy of the suite, see WithRetention.
+	RetainDefault Retention = iota
+	// RetainNever always removes the resources.
+	RetainNever
+	// RetainOnFailure keeps the resources of failed tests. It is the
+	// default policy of suites.
+	RetainOnFailure
+	// RetainAlways always keeps the resources.
+	RetainAlways
+)
+
+var retentionNames = map[Retention]string{
+	RetainDefault:   "default",
+	RetainNever:     "never",
+	RetainOnFailure: "on-failure",
+	RetainAlways:    "always",
+}
+
+func (r Retention) String() string {
+	if name, ok := retentionNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Retention(%d)", int(r))
+}
+
+// Set sets the policy from its name, so that it can be used as a flag.
+func (r *Retention) Set(name string) error {
+	for retention, n := range retentionNames {
+		if n == name && retention != RetainDefault {
+			*r = retention
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown retention policy %q, want never, on-failure or always", name)
+}
+
+// keep reports whether the policy keeps the resources of a test that
+// failed or passed.
+func (r Retention) keep(failed bool) bool {
+	switch r {
+	case RetainNever:
+		return false
+	case RetainAlways:
+		return true
+	}
+	return failed
+}
+
+var retainFlag = RetainOnFailure
+
+func init() {
+	flag.Var(&retainFlag, "testify.retain", "keep the resources of testify tests for inspection: never, on-failure or always")
+}
+
+// WithRetention sets the policy deciding whether the resources of the
+// suite's tests are kept for inspection after the tests, see Suite.Dispose.
+// It defaults to the -testify.retain flag, which defaults to
+// RetainOnFailure.
+func WithRetention(r Retention) Option {
+	return func(o *options) {
+		o.retention = r
+	}
+}
+
+// Dispose registers cleanup, removing the resource described by what,
+// e.g. "container postgres", to be called when the current test
+// finishes, or the suite when called in SetupSuite, unless retention
+// keeps the resource for inspection. RetainDefault applies the policy of
+// the suite. Kept resources are logged.
+func (suite *Suite) Dispose(what string, retention Retention, cleanup func()) {
+	suite.disposeFor(suite.T(), what, retention, cleanup)
+}
+
+// disposeFor registers cleanup to be called when the test t finishes,
+// unless retention keeps the resource described by what.
+func (suite *Suite) disposeFor(t *testing.T, what string, retention Retention, cleanup func()) {
+	if retention == RetainDefault {
+		retention = suite.retention
+	}
+	t.Cleanup(func() {
+		if retention.keep(t.Failed()) {
+			t.Logf("suite: kept %s for inspection", what)
+			return
+		}
+		cleanup()
+	})
+}
+
+// TempDir returns a new temporary directory for the current test, removed
+// when the test finishes unless the retention policy of the suite keeps
+// it, see Dispose.
+func (suite *Suite) TempDir() string {
+	t := suite.T()
+	t.Helper()
+	return suite.tempDirFor(t)
+}
+
+// tempDirFor returns a new temporary directory for the test t.
+func (suite *Suite) tempDirFor(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", sanitizeName(t.Name())+"-*")
+	if err != nil {
+		t.Fatalf("suite: failed to create temporary directory: %v", err)
+	}
+	suite.disposeFor(t, "temporary directory "+dir, RetainDefault, func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
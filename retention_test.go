@@ -0,0 +1,83 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteRetentionTester struct {
+	Suite
+	dirs      map[string]string
+	container Retention
+	removed   []string
+}
+
+func (s *SuiteRetentionTester) SetupSuite() {
+	s.dirs = make(map[string]string)
+}
+
+func (s *SuiteRetentionTester) TestFails() {
+	s.dirs["TestFails"] = s.TempDir()
+	s.T().Fail()
+}
+
+func (s *SuiteRetentionTester) TestPasses() {
+	s.dirs["TestPasses"] = s.TempDir()
+	s.Dispose("container postgres", s.container, func() {
+		s.removed = append(s.removed, "postgres")
+	})
+}
+
+func TestRetention(t *testing.T) {
+	for _, tc := range []struct {
+		retention        Retention
+		container        Retention
+		keptFailed, kept bool
+		removed          []string
+	}{
+		{retention: RetainDefault, keptFailed: true, removed: []string{"postgres"}},
+		{retention: RetainOnFailure, keptFailed: true, removed: []string{"postgres"}},
+		{retention: RetainNever, removed: []string{"postgres"}},
+		{retention: RetainAlways, keptFailed: true, kept: true},
+		{retention: RetainAlways, container: RetainNever, keptFailed: true, kept: true, removed: []string{"postgres"}},
+	} {
+		t.Run(tc.retention.String(), func(t *testing.T) {
+			s := &SuiteRetentionTester{container: tc.container}
+			opts := []Option(nil)
+			if tc.retention != RetainDefault {
+				opts = append(opts, WithRetention(tc.retention))
+			}
+			ok, output, err := runDetachedSuiteWithOutputCapture(s, opts...)
+			require.NoError(t, err)
+			assert.False(t, ok)
+			for name, dir := range s.dirs {
+				defer os.RemoveAll(dir)
+				assert.Contains(t, filepath.Base(dir), "DetachedSuite_"+name+"-")
+			}
+			if tc.keptFailed {
+				assert.DirExists(t, s.dirs["TestFails"])
+				assert.Contains(t, output, "suite: kept temporary directory "+s.dirs["TestFails"]+" for inspection")
+			} else {
+				assert.NoDirExists(t, s.dirs["TestFails"])
+			}
+			if tc.kept {
+				assert.DirExists(t, s.dirs["TestPasses"])
+			} else {
+				assert.NoDirExists(t, s.dirs["TestPasses"])
+			}
+			assert.Equal(t, tc.removed, s.removed)
+		})
+	}
+}
+
+func TestRetentionFlag(t *testing.T) {
+	var r Retention
+	require.NoError(t, r.Set("always"))
+	assert.Equal(t, RetainAlways, r)
+	assert.EqualError(t, r.Set("default"), `unknown retention policy "default", want never, on-failure or always`)
+	assert.Equal(t, RetainAlways, r)
+}
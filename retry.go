@@ -0,0 +1,153 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Defaults of the fields of RetryPolicy.
+const (
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMultiplier = 2
+)
+
+// RetryPolicy is the policy with which a setup hook is retried, see
+// RetryableSetup:
+//
+//	func (s *StoreSuite) SetupRetryPolicy(hook string) suite.RetryPolicy {
+//		if hook == "SetupSuite" {
+//			return suite.RetryPolicy{Attempts: 5, Backoff: time.Second}
+//		}
+//		return suite.RetryPolicy{}
+//	}
+//
+// An attempt fails when one of the suite's assertions fails, including
+// those of Require, or when the hook panics. The failures of all attempts
+// but the last are logged instead of failing the test, and the hook is
+// called again after a delay growing exponentially, measured with the
+// suite's clock (see Suite.SetClock). The last attempt runs like a hook
+// that isn't retried, and so does the failed attempt after which the
+// suite's or the test's context is done, e.g. on an interrupt. Hooks calling the methods of the
+// *testing.T directly, such as Fatal, aren't retried, nor are suites that
+// don't embed Suite, except when they panic.
+//
+// The resources a failed attempt registered for cleanup are only released
+// when the test or the suite finishes, so retried hooks should be
+// idempotent.
+type RetryPolicy struct {
+	// Attempts is the maximum number of calls of the hook. The hook isn't
+	// retried if it is less than 2.
+	Attempts int
+	// Backoff is the delay before the second attempt, 100ms by default.
+	Backoff time.Duration
+	// Multiplier is the factor by which the delay grows after every
+	// attempt, 2 by default.
+	Multiplier float64
+	// MaxBackoff caps the delay between attempts, if positive.
+	MaxBackoff time.Duration
+}
+
+// delay returns the delay before the attempt following attempt.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+	d := float64(backoff)
+	for i := 1; i < attempt; i++ {
+		d *= multiplier
+		if p.MaxBackoff > 0 && d >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	return time.Duration(d)
+}
+
+// setupAttempt collects the failures of an attempt of a retried setup
+// hook.
+type setupAttempt struct {
+	failures []string
+}
+
+// errAttemptFailed is panicked by the FailNow of an attempt, stopping it.
+var errAttemptFailed = errors.New("suite: setup attempt failed")
+
+// attemptT records the failures of the assertions made through Assert and
+// Require during an attempt.
+type attemptT struct {
+	attempt *setupAttempt
+}
+
+func (t *attemptT) Errorf(format string, args ...interface{}) {
+	t.attempt.failures = append(t.attempt.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *attemptT) FailNow() {
+	panic(errAttemptFailed)
+}
+
+// retrySetup returns the setup hook name of the suite, calling hook, to be
+// retried with the policy returned by the suite's SetupRetryPolicy method,
+// see RetryableSetup. ctx stops the retries when done.
+func (r *suiteRun) retrySetup(t *testing.T, ctx context.Context, name string, hook func()) func() {
+	s, ok := r.suite.(RetryableSetup)
+	if !ok {
+		return hook
+	}
+	policy := s.SetupRetryPolicy(name)
+	if policy.Attempts < 2 {
+		return hook
+	}
+	return func() {
+		t.Helper()
+		for attempt := 1; attempt < policy.Attempts; attempt++ {
+			failures := r.attemptSetup(hook)
+			if failures == nil {
+				return
+			}
+			delay := policy.delay(attempt)
+			r.infof(t, "%s attempt %d/%d failed, retrying in %v:\n\t%s", name, attempt, policy.Attempts, delay, indentFailures(failures))
+			select {
+			case <-r.clock().After(delay):
+			case <-ctx.Done():
+				t.Errorf("suite: %s attempt %d/%d failed and wasn't retried: %v:\n\t%s", name, attempt, policy.Attempts, context.Cause(ctx), indentFailures(failures))
+				t.FailNow()
+			}
+		}
+		r.infof(t, "%s attempt %d/%d", name, policy.Attempts, policy.Attempts)
+		hook()
+	}
+}
+
+// indentFailures joins the failures of an attempt, indenting their lines
+// under the line reporting the attempt.
+func indentFailures(failures []string) string {
+	return strings.ReplaceAll(strings.Join(failures, "\n"), "\n", "\n\t")
+}
+
+// attemptSetup calls the setup hook once, returning the failures of its
+// assertions and its panic, or nil if it succeeded.
+func (r *suiteRun) attemptSetup(hook func()) (failures []string) {
+	attempt := &setupAttempt{}
+	if b, ok := r.suite.(suiteBase); ok {
+		b.base().test.setupAttempt = attempt
+		defer func() { b.base().test.setupAttempt = nil }()
+	}
+	if p := catchPanic(hook); p != nil && p.value != errAttemptFailed {
+		attempt.failures = append(attempt.failures, fmt.Sprintf("panic: %v", p.value))
+	} else if p != nil && len(attempt.failures) == 0 {
+		attempt.failures = append(attempt.failures, "FailNow called")
+	}
+	return attempt.failures
+}
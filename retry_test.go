@@ -0,0 +1,142 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Attempts: 5}
+	assert.Equal(t, 100*time.Millisecond, p.delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.delay(2))
+	assert.Equal(t, 400*time.Millisecond, p.delay(3))
+
+	p = RetryPolicy{Backoff: time.Second, Multiplier: 3, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 3*time.Second, p.delay(2))
+	assert.Equal(t, 5*time.Second, p.delay(3))
+}
+
+type SuiteRetryableSetupTester struct {
+	Suite
+	// failures is the number of attempts of SetupSuite that fail.
+	failures int
+	// backoff is the delay before the second attempt, 1ms if unset.
+	backoff       time.Duration
+	suiteAttempts int
+	testAttempts  int
+	ran           bool
+}
+
+func (s *SuiteRetryableSetupTester) SetupRetryPolicy(hook string) RetryPolicy {
+	backoff := s.backoff
+	if backoff == 0 {
+		backoff = time.Millisecond
+	}
+	return RetryPolicy{Attempts: 3, Backoff: backoff}
+}
+
+func (s *SuiteRetryableSetupTester) SetupSuite() {
+	s.suiteAttempts++
+	s.Require().NoError(s.start(), "starting the database")
+}
+
+func (s *SuiteRetryableSetupTester) start() error {
+	if s.suiteAttempts <= s.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (s *SuiteRetryableSetupTester) SetupTest() {
+	s.testAttempts++
+	if s.testAttempts == 1 {
+		panic("container not ready")
+	}
+}
+
+func (s *SuiteRetryableSetupTester) TestUse() {
+	s.ran = true
+}
+
+func TestRetryableSetup(t *testing.T) {
+	s := &SuiteRetryableSetupTester{failures: 2}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 3, s.suiteAttempts)
+	assert.Equal(t, 2, s.testAttempts)
+	assert.True(t, s.ran)
+}
+
+func TestRetryableSetupExhausted(t *testing.T) {
+	s := &SuiteRetryableSetupTester{failures: 3}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 3, s.suiteAttempts)
+	assert.False(t, s.ran)
+	assert.Contains(t, output, "suite: SetupSuite attempt 1/3 failed, retrying in 1ms:")
+	assert.Contains(t, output, "suite: SetupSuite attempt 2/3 failed, retrying in 2ms:")
+	assert.Contains(t, output, "connection refused")
+	assert.Contains(t, output, "starting the database")
+}
+
+func TestRetryableSetupOnSuiteClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := &SuiteRetryableSetupTester{failures: 2, backoff: time.Hour}
+	s.SetClock(clock)
+	advanced := make(chan struct{})
+	go func() {
+		defer close(advanced)
+		// SetupSuite is retried twice, SetupTest once.
+		for _, d := range []time.Duration{time.Hour, 2 * time.Hour, time.Hour} {
+			clock.BlockUntil(1)
+			clock.Advance(d)
+		}
+	}()
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	<-advanced
+	assert.Equal(t, 3, s.suiteAttempts)
+	assert.Equal(t, 2, s.testAttempts)
+	assert.True(t, s.ran)
+	assert.Equal(t, 4*time.Hour, clock.Since(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestRetryableSetupQuiet(t *testing.T) {
+	s := &SuiteRetryableSetupTester{failures: 3}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithVerbosity(VerbosityQuiet))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 3, s.suiteAttempts)
+	assert.NotContains(t, output, "failed, retrying in")
+	assert.Contains(t, output, "connection refused")
+}
+
+func TestRetryableSetupCanceled(t *testing.T) {
+	s := &SuiteRetryableSetupTester{failures: 3}
+	// The fake clock isn't advanced: the retry waits for the context.
+	s.SetClock(NewFakeClock(time.Now()))
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		s.SetT(t)
+		r := &suiteRun{opts: newOptions(nil), suite: s}
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(errors.New("interrupted"))
+		r.retrySetup(t, ctx, "SetupSuite", s.SetupSuite)()
+		s.ran = true
+	})
+	require.NoError(t, err)
+	assert.False(t, ok, "the canceled retry fails the test")
+	assert.Equal(t, 1, s.suiteAttempts)
+	assert.False(t, s.ran, "the canceled retry stops the test")
+	assert.Contains(t, output, "suite: SetupSuite attempt 1/3 failed and wasn't retried: interrupted:")
+	assert.Contains(t, output, "connection refused")
+	assert.Contains(t, output, "starting the database")
+}
@@ -0,0 +1,108 @@
+package suite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// timeoutWait is how long a test method that timed out is given to return
+// once its context is done, before it is abandoned.
+var timeoutWait = 5 * time.Second
+
+// callMethod calls a test method of the suite, or checks a property test
+// method with its property checker, through the interceptors, and fails the
+// test if the method is still running when ctx expires because of the
+// timeout set with WithTimeout. It reports whether the method returned,
+// which it may not have if it didn't return within timeoutWait of timing
+// out, in which case it is still running in the background.
+func (r *suiteRun) callMethod(t *testing.T, ctx context.Context, method reflect.Method) bool {
+	t.Helper()
+	call := func() {
+		method.Func.Call([]reflect.Value{reflect.ValueOf(r.suite)})
+	}
+	if checker, ok := r.propertyChecker(method); ok {
+		call = func() {
+			checker.check(t, reflect.ValueOf(r.suite).Method(method.Index))
+		}
+	}
+	for i := len(r.opts.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := r.opts.interceptors[i], call
+		call = func() { interceptor(t, method.Name, next) }
+	}
+	if r.opts.timeout <= 0 || r.needsOSThread(method.Name) {
+		if p := catchPanic(call); p != nil {
+			r.failOnPanic(t, method.Name, p)
+		}
+		if r.opts.timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+			t.Errorf("suite: %s timed out after %v", method.Name, r.opts.timeout)
+		}
+		return true
+	}
+
+	// done is closed without a value if the method calls runtime.Goexit
+	// through t.FailNow or t.SkipNow.
+	done := make(chan *recoveredPanic, 1)
+	go func() {
+		defer close(done)
+		done <- catchPanic(call)
+	}()
+	var p *recoveredPanic
+	select {
+	case p = <-done:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			t.Errorf("suite: %s timed out after %v", method.Name, r.opts.timeout)
+			timer := time.NewTimer(timeoutWait)
+			defer timer.Stop()
+			select {
+			case p = <-done:
+			case <-timer.C:
+				t.Errorf("suite: %s still running %v after timing out, skipping the teardown of the test", method.Name, timeoutWait)
+				return false
+			}
+		} else {
+			p = <-done
+		}
+	}
+	if p != nil {
+		r.failOnPanic(t, method.Name, p)
+	}
+	return true
+}
+
+// parallelCopy returns a copy of the runner running a shallow copy of the
+// suite, for a test running in parallel with the others.
+func (r *suiteRun) parallelCopy() *suiteRun {
+	c := *r
+	v := reflect.ValueOf(r.suite)
+	suite := reflect.New(v.Elem().Type())
+	suite.Elem().Set(v.Elem())
+	c.suite = suite.Interface().(TestingSuite)
+	setS(c.suite)
+	return &c
+}
+
+// failed reports whether a test of the suite failed.
+func (r *suiteRun) failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.result.Failed()
+}
+
+// deferredCalls are functions called in the reverse order of being pushed,
+// like deferred function calls.
+type deferredCalls []func()
+
+func (d *deferredCalls) push(f func()) {
+	*d = append(*d, f)
+}
+
+func (d *deferredCalls) run() {
+	// Deferring the calls runs all of them even if one calls runtime.Goexit
+	// through t.FailNow.
+	for _, f := range *d {
+		defer f()
+	}
+}
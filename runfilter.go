@@ -0,0 +1,132 @@
+package suite
+
+import (
+	"flag"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// runFilter selects the test methods of a suite matching go test's -run
+// flag, e.g. only TestFoo for -run 'TestMySuite/TestFoo', before they run,
+// so that the suite's hooks aren't run for nothing when none matches.
+type runFilter struct {
+	// alternatives are the |-separated alternatives of the pattern, split
+	// into the elements matching every level of subtest names.
+	alternatives [][]*regexp.Regexp
+}
+
+// newRunFilter returns the filter of the -run flag for the subtests of t,
+// or nil if it isn't set or is invalid, in which case go test reports it.
+// It is nil too if the flag doesn't select t itself, which happens when t
+// was run by a custom matcher, e.g. by testing.RunTests.
+func newRunFilter(t *testing.T) *runFilter {
+	f := flag.Lookup("test.run")
+	if f == nil || f.Value.String() == "" {
+		return nil
+	}
+	filter := &runFilter{}
+	for _, alternative := range splitRunPattern(f.Value.String()) {
+		var elements []*regexp.Regexp
+		for _, element := range alternative {
+			re, err := regexp.Compile(element)
+			if err != nil {
+				return nil
+			}
+			elements = append(elements, re)
+		}
+		filter.alternatives = append(filter.alternatives, elements)
+	}
+	if !filter.matches(t.Name()) {
+		return nil
+	}
+	return filter
+}
+
+// matches reports whether the subtest name of the test with the given
+// full name would run.
+func (f *runFilter) matches(fullName string) bool {
+	if f == nil {
+		return true
+	}
+	names := strings.Split(fullName, "/")
+	for _, elements := range f.alternatives {
+		matched := true
+		for i, name := range names {
+			if i >= len(elements) {
+				break
+			}
+			if !elements[i].MatchString(name) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRunPattern splits a -run pattern into its |-separated alternatives
+// and those into their /-separated elements, ignoring separators in
+// brackets and parentheses, like package testing.
+func splitRunPattern(s string) [][]string {
+	var alternatives [][]string
+	var elements []string
+	brackets, parens := 0, 0
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '[':
+			brackets++
+		case ']':
+			if brackets--; brackets < 0 {
+				brackets = 0
+			}
+		case '(':
+			if brackets == 0 {
+				parens++
+			}
+		case ')':
+			if brackets == 0 {
+				parens--
+			}
+		case '\\':
+			i++
+		case '/', '|':
+			if brackets == 0 && parens == 0 {
+				elements = append(elements, s[:i])
+				if s[i] == '|' {
+					alternatives = append(alternatives, elements)
+					elements = nil
+				}
+				s = s[i+1:]
+				i = 0
+				continue
+			}
+		}
+		i++
+	}
+	return append(alternatives, append(elements, s))
+}
+
+// runSelected reports whether go test's -run flag selects the test method
+// name of the suite.
+func (r *suiteRun) runSelected(name string) bool {
+	if r.suiteT == nil {
+		return true
+	}
+	return r.runFilter.matches(r.suiteT.Name() + "/" + sanitizeName(r.opts.namer(r.suiteName, name)))
+}
+
+// runExcludesAll reports whether go test's -run flag excludes all test
+// methods of the suite.
+func (r *suiteRun) runExcludesAll() bool {
+	if r.runFilter == nil || len(r.testMethods()) > 0 {
+		return false
+	}
+	filter := r.runFilter
+	r.runFilter = nil
+	defer func() { r.runFilter = filter }()
+	return len(r.testMethods()) > 0
+}
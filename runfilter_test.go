@@ -0,0 +1,67 @@
+package suite
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteRunFilterTester struct {
+	Suite
+	setups int
+	ran    []string
+}
+
+func (s *SuiteRunFilterTester) SetupSuite() {
+	s.setups++
+}
+
+func (s *SuiteRunFilterTester) BeforeTest(suiteName, testName string) {
+	s.ran = append(s.ran, testName)
+}
+
+func (s *SuiteRunFilterTester) TestA() {}
+
+func (s *SuiteRunFilterTester) TestB() {}
+
+func TestRunFilter(t *testing.T) {
+	run := flag.Lookup("test.run").Value
+	defer run.Set(run.String())
+
+	recorder := new(recordingReporter)
+	s := new(SuiteRunFilterTester)
+	run.Set("DetachedSuite/TestA")
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithReporter(recorder))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, []string{"TestA"}, s.ran)
+	require.Len(t, recorder.Suites, 1)
+	assert.Len(t, recorder.Suites[0].Tests, 1)
+
+	s = new(SuiteRunFilterTester)
+	run.Set("DetachedSuite/TestZ")
+	ok, output, err = runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	assert.Equal(t, 0, s.setups, "SetupSuite must not run when no test matches")
+}
+
+func TestSplitRunPattern(t *testing.T) {
+	assert.Equal(t, [][]string{{"TestSuite", "TestA"}}, splitRunPattern("TestSuite/TestA"))
+	assert.Equal(t, [][]string{{"TestSuite", "Test(A/B)"}, {"TestOther"}}, splitRunPattern("TestSuite/Test(A/B)|TestOther"))
+	assert.Equal(t, [][]string{{"Test[/|]", ""}}, splitRunPattern("Test[/|]/"))
+
+	run := flag.Lookup("test.run").Value
+	defer run.Set(run.String())
+	run.Set("TestSuite/TestA|TestOther|TestSplitRunPattern")
+	f := newRunFilter(t)
+	assert.True(t, f.matches("TestSuite/TestA"))
+	assert.True(t, f.matches("TestSuite/TestAB/sub"))
+	assert.False(t, f.matches("TestSuite/TestB"))
+	assert.True(t, f.matches("TestOther/TestB"))
+
+	run.Set("TestOther")
+	assert.Nil(t, newRunFilter(t), "the filter only applies to tests it selected")
+}
@@ -0,0 +1,130 @@
+package suite
+
+import (
+	"sync"
+	"testing"
+)
+
+// Runner runs suites with a common configuration. It keeps the results of
+// the suites it ran, and keeps the shared fixtures (see SharedFixture)
+// requested by its suites alive until it is closed, so that the suites
+// share them:
+//
+//	func TestIntegration(t *testing.T) {
+//		runner := suite.NewRunner(suite.WithFailFast(), suite.WithReporter(dashboard))
+//		defer runner.Close()
+//		runner.Run(t, new(UsersSuite), new(OrdersSuite))
+//		for _, result := range runner.Results() {
+//			...
+//		}
+//	}
+//
+// A Runner can be used by several tests, including tests running in
+// parallel.
+type Runner struct {
+	opts []Option
+
+	mu      sync.Mutex
+	results []SuiteResult
+	// fixtures are the names of the shared fixtures the runner holds a
+	// reference to.
+	fixtures map[string]bool
+	closed   bool
+}
+
+// NewRunner returns a Runner running suites with opts.
+func NewRunner(opts ...Option) *Runner {
+	return &Runner{
+		opts:     append([]Option(nil), opts...),
+		fixtures: make(map[string]bool),
+	}
+}
+
+// Run runs suites one after another. A single suite is run in t, like Run
+// does; several suites are run as subtests of t named after their types.
+func (runner *Runner) Run(t *testing.T, suites ...TestingSuite) {
+	t.Helper()
+	if len(suites) == 1 {
+		runner.run(t, suites[0])
+		return
+	}
+	subtests := make(subtestNames)
+	for _, suite := range suites {
+		suite := suite
+		t.Run(subtests.unique(typeName(suite)), func(t *testing.T) {
+			t.Helper()
+			runner.run(t, suite)
+		})
+	}
+}
+
+// Results returns the results of the suites run so far, in the order they
+// finished.
+func (runner *Runner) Results() []SuiteResult {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	return append([]SuiteResult(nil), runner.results...)
+}
+
+// Close releases the shared fixtures held by the runner. Suites finishing
+// after Close release their shared fixtures themselves.
+func (runner *Runner) Close() {
+	runner.mu.Lock()
+	runner.closed = true
+	fixtures := runner.fixtures
+	runner.fixtures = make(map[string]bool)
+	runner.mu.Unlock()
+	for name := range fixtures {
+		registry.release(name)
+	}
+}
+
+// RunOne runs the test method methodName of suite alone, wrapped in the
+// hooks of the suite like the tests run by Run, e.g. for tools rerunning a
+// failed test or distributing the tests of a suite. The method is run
+// whatever its name, even if it isn't selected by the prefixes of the
+// suite, the filters set with WithFilter or the -testify.m flag; go test's
+// -run flag still applies. t fails if the suite has no method
+// methodName.
+func (runner *Runner) RunOne(t *testing.T, suite TestingSuite, methodName string) {
+	t.Helper()
+	runner.run(t, suite, func(o *options) {
+		o.only = methodName
+	})
+}
+
+func (runner *Runner) run(suiteT *testing.T, suite TestingSuite, extra ...Option) {
+	suiteT.Helper()
+	opts := append(append([]Option(nil), runner.opts...), extra...)
+	r := &suiteRun{
+		owner:     runner,
+		opts:      newOptions(opts),
+		suite:     suite,
+		suiteT:    suiteT,
+		suiteName: typeName(suite),
+		result:    &SuiteResult{},
+		mu:        &sync.Mutex{},
+	}
+	r.run()
+}
+
+// record keeps the result of a finished suite.
+func (runner *Runner) record(result SuiteResult) {
+	runner.mu.Lock()
+	runner.results = append(runner.results, result)
+	runner.mu.Unlock()
+	registry.record(result)
+}
+
+// keepShared takes over a suite's reference to the shared fixture name,
+// reporting whether it did. It doesn't if the runner already holds a
+// reference or is closed.
+func (runner *Runner) keepShared(name string) bool {
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+	if runner.closed || runner.fixtures[name] {
+		return false
+	}
+	runner.fixtures[name] = true
+	return true
+}
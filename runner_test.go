@@ -0,0 +1,98 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteRunnerTester struct {
+	Suite
+}
+
+func (s *SuiteRunnerTester) TestPasses() {}
+
+func TestRunner(t *testing.T) {
+	databasesCreated = 0
+	runner := NewRunner(WithReporter(&recordingReporter{}))
+	first, second := new(SuiteSharedTester), new(SuiteSharedTester)
+	ok, _, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		runner.Run(t, first, new(SuiteRunnerTester), second)
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// The suites share the fixture, which the runner keeps alive.
+	assert.Equal(t, 1, databasesCreated)
+	assert.Same(t, first.db, second.db)
+	assert.False(t, first.db.closed)
+	runner.Close()
+	assert.True(t, first.db.closed)
+
+	results := runner.Results()
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"SuiteSharedTester", "SuiteRunnerTester", "SuiteSharedTester"},
+		[]string{results[0].Name, results[1].Name, results[2].Name})
+	assert.Equal(t, "DetachedSuite/SuiteSharedTester_2/TestOpen", results[2].Tests[0].FullName)
+}
+
+func TestRunnerSingleSuite(t *testing.T) {
+	runner := NewRunner()
+	defer runner.Close()
+	ok, _, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		runner.Run(t, new(SuiteRunnerTester))
+	})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.Len(t, runner.Results(), 1)
+	// A single suite runs in the test itself, like Run does.
+	assert.Equal(t, "DetachedSuite/TestPasses", runner.Results()[0].Tests[0].FullName)
+}
+
+type SuiteRunOneTester struct {
+	Suite
+	calls []string
+}
+
+func (s *SuiteRunOneTester) SetupSuite()    { s.calls = append(s.calls, "SetupSuite") }
+func (s *SuiteRunOneTester) SetupTest()     { s.calls = append(s.calls, "SetupTest") }
+func (s *SuiteRunOneTester) TearDownTest()  { s.calls = append(s.calls, "TearDownTest") }
+func (s *SuiteRunOneTester) TearDownSuite() { s.calls = append(s.calls, "TearDownSuite") }
+
+func (s *SuiteRunOneTester) TestFirst()  { s.calls = append(s.calls, "TestFirst") }
+func (s *SuiteRunOneTester) TestSecond() { s.calls = append(s.calls, "TestSecond") }
+
+// CheckUnprefixed isn't a test method of the suite, but can be run alone.
+func (s *SuiteRunOneTester) CheckUnprefixed() { s.calls = append(s.calls, "CheckUnprefixed") }
+
+func TestRunOne(t *testing.T) {
+	s := new(SuiteRunOneTester)
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		RunOne(t, s, "TestSecond", WithReporter(recorder))
+	})
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, []string{"SetupSuite", "SetupTest", "TestSecond", "TearDownTest", "TearDownSuite"}, s.calls)
+	assert.Equal(t, []string{"SuiteRunOneTester.TestSecond"}, recorder.Started)
+
+	s = new(SuiteRunOneTester)
+	ok, output, err = runDetachedWithOutputCapture(func(t *testing.T) {
+		RunOne(t, s, "CheckUnprefixed", WithFilter(func(string) bool { return false }))
+	})
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, []string{"SetupSuite", "SetupTest", "CheckUnprefixed", "TearDownTest", "TearDownSuite"}, s.calls)
+}
+
+func TestRunOneUnknownMethod(t *testing.T) {
+	s := new(SuiteRunOneTester)
+	ok, output, err := runDetachedWithOutputCapture(func(t *testing.T) {
+		RunOne(t, s, "TestMissing")
+	})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: SuiteRunOneTester has no method TestMissing")
+	assert.Empty(t, s.calls)
+}
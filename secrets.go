@@ -0,0 +1,221 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var secretsDir = flag.String("testify.secrets-dir", "", "load the secrets of testify suites from files in this directory")
+
+// redacted replaces the values of secrets in the output of tests.
+const redacted = "[REDACTED]"
+
+// minSecretLen is the length below which values aren't redacted, as
+// redacting them would garble the output more than it would protect them.
+const minSecretLen = 4
+
+// WithSecretsDir sets the directory from which the secrets of the suite are
+// loaded when they aren't set in the environment, see Secret. It
+// defaults to the -testify.secrets-dir flag.
+func WithSecretsDir(dir string) Option {
+	return func(o *options) {
+		o.secretsDir = dir
+	}
+}
+
+// Secret returns the value of the secret name, loaded from the environment
+// variable name of the test, see Env, from the file named by the
+// environment variable name_FILE, or from the file name in the secrets directory of the suite,
+// see WithSecretsDir, in that order. Trailing newlines of files are
+// dropped. The current test is skipped if the secret isn't set.
+//
+// The value is redacted from the output of the suite, see Redact.
+//
+// Secrets can also be loaded into the exported string and []byte fields
+// of the suite tagged with secret before SetupSuite. The suite is skipped
+// if one of them isn't set, unless it is marked optional:
+//
+//	type BillingSuite struct {
+//		suite.Suite
+//		APIKey string `secret:"BILLING_API_KEY"`
+//		Token  string `secret:"BILLING_TOKEN,optional"`
+//	}
+func (suite *Suite) Secret(name string) string {
+	t := suite.T()
+	t.Helper()
+	value, ok, err := loadSecret(suite.env(), name, suite.secretsDir)
+	if err != nil {
+		t.Fatalf("suite: %v", err)
+	}
+	if !ok {
+		suite.skip(SkipEnvironment, "secret "+name+" is not set")
+	}
+	suite.Redact(value)
+	return value
+}
+
+// Redact registers values to be replaced by [REDACTED] in the output of
+// the suite: the lines logged with Log and Logf, the failures of its
+// assertions, and the artifacts its helpers write, such as the requests of
+// an APIClient. Output written directly to the *testing.T or to os.Stdout
+// isn't redacted. Values shorter than 4 bytes are ignored.
+func (suite *Suite) Redact(values ...string) {
+	if suite.secrets == nil {
+		suite.secrets = &redactor{}
+	}
+	suite.secrets.add(values...)
+}
+
+// redact replaces the secrets registered with Redact in s.
+func (suite *Suite) redact(s string) string {
+	return suite.secrets.redact(s)
+}
+
+// redact replaces the secrets of the suite in s.
+func (r *suiteRun) redact(s string) string {
+	if b, ok := r.suite.(suiteBase); ok {
+		return b.base().redact(s)
+	}
+	return s
+}
+
+// redactor replaces the values of secrets in strings.
+type redactor struct {
+	mu       sync.Mutex
+	values   map[string]bool
+	replacer *strings.Replacer
+}
+
+func (r *redactor) add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, value := range values {
+		if len(value) < minSecretLen || r.values[value] {
+			continue
+		}
+		if r.values == nil {
+			r.values = make(map[string]bool)
+		}
+		r.values[value] = true
+		r.replacer = nil
+	}
+}
+
+// empty reports whether no secrets are registered.
+func (r *redactor) empty() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.values) == 0
+}
+
+func (r *redactor) redact(s string) string {
+	if r == nil {
+		return s
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.values) == 0 {
+		return s
+	}
+	if r.replacer == nil {
+		// Longer values are replaced first, so that secrets containing
+		// others are redacted as a whole.
+		values := make([]string, 0, len(r.values))
+		for value := range r.values {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if len(values[i]) != len(values[j]) {
+				return len(values[i]) > len(values[j])
+			}
+			return values[i] < values[j]
+		})
+		var oldnew []string
+		for _, value := range values {
+			oldnew = append(oldnew, value, redacted)
+		}
+		r.replacer = strings.NewReplacer(oldnew...)
+	}
+	return r.replacer.Replace(s)
+}
+
+// loadSecret loads the secret name from env, see Suite.Secret.
+func loadSecret(env *Env, name, dir string) (value string, ok bool, err error) {
+	if value := env.Get(name); value != "" {
+		return value, true, nil
+	}
+	path := env.Get(name + "_FILE")
+	if path == "" && dir != "" {
+		path = filepath.Join(dir, name)
+		if !fileExists(path) {
+			return "", false, nil
+		}
+	}
+	if path == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load secret %s: %v", name, err)
+	}
+	value = strings.TrimRight(string(data), "\r\n")
+	return value, value != "", nil
+}
+
+// loadSecrets sets the fields of the suite tagged with secret, returning
+// the names of the required secrets that aren't set.
+func (r *suiteRun) loadSecrets() (missing []string, err error) {
+	v := reflect.ValueOf(r.suite)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, nil
+	}
+	v = v.Elem()
+	dir := r.opts.secretsDir
+	var values []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, ok := field.Tag.Lookup("secret")
+		if !ok {
+			continue
+		}
+		name, option, _ := strings.Cut(tag, ",")
+		if option != "" && option != "optional" {
+			return nil, fmt.Errorf("field %s: unknown secret option %q", field.Name, option)
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s: secrets can only be loaded into exported fields", field.Name)
+		}
+		value, ok, err := loadSecret(nil, name, dir)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if option != "optional" {
+				missing = append(missing, name)
+			}
+			continue
+		}
+		switch f := v.Field(i); {
+		case f.Kind() == reflect.String:
+			f.SetString(value)
+		case f.Type() == reflect.TypeOf([]byte(nil)):
+			f.SetBytes([]byte(value))
+		default:
+			return nil, fmt.Errorf("field %s: secrets can only be loaded into string and []byte fields, not %s", field.Name, f.Type())
+		}
+		values = append(values, value)
+	}
+	if b, ok := r.suite.(suiteBase); ok {
+		b.base().Redact(values...)
+	}
+	return missing, nil
+}
@@ -0,0 +1,75 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteSecretsTester struct {
+	Suite
+	APIKey   string `secret:"SUITE_TEST_API_KEY"`
+	Password []byte `secret:"SUITE_TEST_PASSWORD"`
+	Token    string `secret:"SUITE_TEST_TOKEN,optional"`
+}
+
+func (s *SuiteSecretsTester) TestSecrets() {
+	s.Equal("key-from-env", s.APIKey)
+	s.Equal([]byte("password-from-file"), s.Password)
+	s.Equal("", s.Token)
+	s.Logf("calling the API with %s", s.APIKey)
+	s.Equal("nope", string(s.Password))
+	s.Assert().Equal("nope", s.APIKey)
+}
+
+func TestSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password"), []byte("password-from-file\n"), 0600))
+	t.Setenv("SUITE_TEST_API_KEY", "key-from-env")
+	t.Setenv("SUITE_TEST_PASSWORD_FILE", filepath.Join(dir, "password"))
+
+	s := new(SuiteSecretsTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "calling the API with [REDACTED]")
+	assert.NotContains(t, output, "key-from-env")
+	assert.NotContains(t, output, "password-from-file")
+	assert.Contains(t, output, `actual  : "[REDACTED]"`)
+}
+
+func TestSecretsMissing(t *testing.T) {
+	t.Setenv("SUITE_TEST_API_KEY", "")
+	t.Setenv("SUITE_TEST_PASSWORD", "")
+	rec := &recordingReporter{}
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteSecretsTester), WithReporter(rec))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+	require.Len(t, rec.Suites, 1)
+	assert.Equal(t, "missing secrets: SUITE_TEST_API_KEY, SUITE_TEST_PASSWORD", rec.Suites[0].SkipReason)
+}
+
+func TestSecretsDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SUITE_TEST_API_KEY"), []byte("key-from-env"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SUITE_TEST_PASSWORD"), []byte("password-from-file"), 0600))
+	t.Setenv("SUITE_TEST_API_KEY", "")
+	t.Setenv("SUITE_TEST_PASSWORD", "")
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteSecretsTester), WithSecretsDir(dir))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestSecrets/SetupSuite")
+	assert.NotContains(t, output, "skipped")
+	assert.NotContains(t, output, "key-from-env")
+}
+
+func TestRedactor(t *testing.T) {
+	r := &redactor{}
+	assert.Equal(t, "abc", r.redact("abc"))
+	r.add("abc", "secret", "secret-longer")
+	assert.Equal(t, "abc [REDACTED] and [REDACTED]", r.redact("abc secret-longer and secret"))
+}
@@ -0,0 +1,100 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// SelfTest runs suites as the self-test command of a service binary, e.g.
+// to validate the environment and the live configuration of the service
+// after deploying it:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "selftest" {
+//		cfg := loadConfig()
+//		os.Exit(suite.SelfTest(os.Args[2:], os.Stdout, []suite.TestingSuite{
+//			&DatabaseSuite{DSN: cfg.DSN},
+//			&UpstreamsSuite{URLs: cfg.Upstreams},
+//		}))
+//	}
+//
+// The suites are run one after another with RunStandalone and opts, and a
+// report is written to w. args are the arguments of the command:
+//
+//	-json      write a JSON document per suite, as NewJSONReporter does
+//	-run re    only run the suites whose names match re
+//	-v         write the output of all suites, not only of failed ones,
+//	           including the logs of passing tests
+//
+// SelfTest returns the exit code of the command: 0 if all suites passed,
+// 1 if one failed and 2 if args are invalid.
+func SelfTest(args []string, w io.Writer, suites []TestingSuite, opts ...Option) int {
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	flags.SetOutput(w)
+	jsonReport := flags.Bool("json", false, "write a JSON document per suite")
+	run := flags.String("run", "", "only run the suites whose names match this regular expression")
+	verbose := flags.Bool("v", false, "write the output of all suites")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	match, err := regexp.Compile(*run)
+	if err != nil {
+		fmt.Fprintf(w, "selftest: invalid -run: %v\n", err)
+		return 2
+	}
+	if *verbose {
+		// Passing tests only log with -test.v.
+		testing.Init()
+		v := flag.Lookup("test.v")
+		previous := v.Value.String()
+		v.Value.Set("true")
+		defer v.Value.Set(previous)
+	}
+	if *jsonReport {
+		opts = append(append([]Option(nil), opts...), WithReporter(NewJSONReporter(w)))
+	}
+
+	code := 0
+	for _, suite := range suites {
+		if !match.MatchString(typeName(suite)) {
+			continue
+		}
+		result := RunStandalone(suite, opts...)
+		if !result.Passed {
+			code = 1
+		}
+		if *jsonReport {
+			continue
+		}
+		writeSelfTestReport(w, result, *verbose || !result.Passed)
+	}
+	return code
+}
+
+// writeSelfTestReport writes a human-readable report of the standalone run
+// of a suite to w, including its output if withOutput is set.
+func writeSelfTestReport(w io.Writer, result StandaloneResult, withOutput bool) {
+	status := "PASS"
+	if !result.Passed {
+		status = "FAIL"
+	} else if result.Suite.SkipReason != "" {
+		status = "SKIP"
+	}
+	fmt.Fprintf(w, "%s %s (%v)\n", status, result.Suite.Name, result.Suite.Duration.Round(time.Millisecond))
+	for _, test := range result.Suite.Tests {
+		line := fmt.Sprintf("    %-4s %s", strings.ToUpper(test.Status.String()), test.Name)
+		if test.SkipReason != "" {
+			line += ": " + test.SkipReason
+		}
+		fmt.Fprintln(w, line)
+	}
+	if withOutput && result.Output != "" {
+		for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+			fmt.Fprintln(w, "    | "+line)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteSelfTestPasser struct {
+	Suite
+}
+
+func (s *SuiteSelfTestPasser) TestDatabase() {
+	s.Log("database reachable")
+}
+
+func (s *SuiteSelfTestPasser) TestCache() {
+	s.skip(SkipEnvironment, "no cache configured")
+}
+
+type SuiteSelfTestFailer struct {
+	Suite
+}
+
+func (s *SuiteSelfTestFailer) TestUpstream() {
+	s.T().Error("upstream unreachable")
+}
+
+func TestSelfTest(t *testing.T) {
+	suites := []TestingSuite{new(SuiteSelfTestPasser), new(SuiteSelfTestFailer)}
+
+	var out bytes.Buffer
+	assert.Equal(t, 1, SelfTest(nil, &out, suites))
+	report := out.String()
+	assert.Contains(t, report, "PASS SuiteSelfTestPasser (")
+	assert.Contains(t, report, "    SKIP TestCache: no cache configured\n")
+	assert.Contains(t, report, "    PASS TestDatabase\n")
+	assert.Contains(t, report, "FAIL SuiteSelfTestFailer (")
+	assert.Contains(t, report, "    FAIL TestUpstream\n")
+	// Only the output of failed suites is written.
+	assert.Contains(t, report, "upstream unreachable")
+	assert.NotContains(t, report, "database reachable")
+
+	out.Reset()
+	assert.Equal(t, 0, SelfTest([]string{"-run", "Passer", "-v"}, &out, suites))
+	assert.Contains(t, out.String(), "database reachable")
+	assert.NotContains(t, out.String(), "SuiteSelfTestFailer")
+
+	out.Reset()
+	assert.Equal(t, 1, SelfTest([]string{"-json"}, &out, suites))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	var doc jsonSuite
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &doc))
+	assert.Equal(t, "SuiteSelfTestFailer", doc.Suite)
+	assert.Equal(t, "fail", doc.Status)
+
+	out.Reset()
+	assert.Equal(t, 2, SelfTest([]string{"-bogus"}, &out, suites))
+}
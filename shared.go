@@ -0,0 +1,80 @@
+package suite
+
+import "fmt"
+
+// SharedFixture is an expensive resource, such as a database container,
+// shared by all suites of the test binary that request it. It is declared
+// at package level and requested by suites, typically in SetupSuite:
+//
+//	var postgres = &suite.SharedFixture[*sql.DB]{
+//		Name:    "postgres",
+//		Create:  startPostgres,
+//		Destroy: func(db *sql.DB) { db.Close() },
+//	}
+//
+//	func (s *ExampleTestSuite) SetupSuite() {
+//		s.db = postgres.Get(s)
+//	}
+//
+// The fixture is created on the first request. Each suite requesting it
+// holds a reference until the suite has finished, and the fixture is
+// destroyed once the last suite holding it has finished. Use Main to keep
+// shared fixtures alive until all tests of the package have run, so that
+// suites running one after another share them as well.
+type SharedFixture[F any] struct {
+	// Name identifies the fixture among all shared fixtures of the test
+	// binary.
+	Name    string
+	Create  func() (F, error)
+	Destroy func(F)
+}
+
+// Get returns the fixture, creating it if needed, and holds a reference
+// to it until s has finished. A failure to create the fixture fails the
+// current test of s. The suite must embed Suite.
+func (f *SharedFixture[F]) Get(s TestingSuite) F {
+	b, ok := s.(suiteBase)
+	if !ok {
+		panic(fmt.Sprintf("suite: shared fixture %q requested by %T, which doesn't embed suite.Suite", f.Name, s))
+	}
+	value, err := registry.acquire(f.Name, func() (interface{}, func(), error) {
+		value, err := f.Create()
+		if err != nil {
+			return nil, nil, err
+		}
+		return value, func() {
+			if f.Destroy != nil {
+				f.Destroy(value)
+			}
+		}, nil
+	})
+	if err != nil {
+		s.T().Fatalf("suite: failed to create shared fixture %q: %v", f.Name, err)
+	}
+	base := b.base()
+	if base.shared[f.Name] {
+		// The suite already holds a reference.
+		registry.release(f.Name)
+	} else {
+		if base.shared == nil {
+			base.shared = make(map[string]bool)
+		}
+		base.shared[f.Name] = true
+	}
+	return value.(F)
+}
+
+// releaseShared drops the references to the shared fixtures held by the
+// suite, or hands them over to the Runner running the suite.
+func (r *suiteRun) releaseShared() {
+	b, ok := r.suite.(suiteBase)
+	if !ok {
+		return
+	}
+	for name := range b.base().shared {
+		if !r.owner.keepShared(name) {
+			registry.release(name)
+		}
+	}
+	b.base().shared = nil
+}
@@ -0,0 +1,77 @@
+package suite
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDatabase struct {
+	closed bool
+}
+
+var (
+	databasesCreated int
+	sharedDatabase   = &SharedFixture[*fakeDatabase]{
+		Name: "database",
+		Create: func() (*fakeDatabase, error) {
+			databasesCreated++
+			return new(fakeDatabase), nil
+		},
+		Destroy: func(db *fakeDatabase) {
+			db.closed = true
+		},
+	}
+	brokenFixture = &SharedFixture[int]{
+		Name: "broken",
+		Create: func() (int, error) {
+			return 0, errors.New("no docker")
+		},
+	}
+)
+
+type SuiteSharedTester struct {
+	Suite
+
+	db *fakeDatabase
+}
+
+func (s *SuiteSharedTester) SetupSuite() {
+	s.db = sharedDatabase.Get(s)
+	// Requesting a fixture again doesn't take another reference.
+	sharedDatabase.Get(s)
+}
+
+func (s *SuiteSharedTester) TestOpen() {
+	assert.False(s.T(), s.db.closed)
+}
+
+type SuiteBrokenFixtureTester struct {
+	Suite
+}
+
+func (s *SuiteBrokenFixtureTester) SetupSuite() {
+	brokenFixture.Get(s)
+}
+
+func TestSharedFixture(t *testing.T) {
+	databasesCreated = 0
+	outer, inner := new(SuiteSharedTester), new(SuiteSharedTester)
+	t.Run("Outer", func(t *testing.T) {
+		outer.SetT(t)
+		outer.db = sharedDatabase.Get(outer)
+		Run(t, inner)
+		assert.Same(t, outer.db, inner.db, "suites running at the same time share the fixture")
+		assert.False(t, inner.db.closed)
+	})
+	registry.release("database")
+	assert.True(t, outer.db.closed, "the fixture is destroyed after the last suite finished")
+	assert.Equal(t, 1, databasesCreated)
+
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteBrokenFixtureTester))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, `failed to create shared fixture "broken": no docker`)
+}
@@ -0,0 +1,79 @@
+package suite
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// invalidMethodsTestName is the name of the test failing with the invalid
+// test methods of a suite, see WithInvalidMethodsTest.
+const invalidMethodsTestName = "InvalidMethods"
+
+// WithInvalidMethodsTest reports the test methods of the suite with an
+// invalid signature, such as methods taking arguments, as the failure of
+// a single test named InvalidMethods, so that reporters see it. By default
+// they are reported as a failure of the suite's test. Either way, the
+// other tests of the suite run.
+func WithInvalidMethodsTest() Option {
+	return func(o *options) {
+		o.invalidMethodsTest = true
+	}
+}
+
+// validMethods returns the test methods with a valid signature, and a
+// description of the others.
+func (r *suiteRun) validMethods(methods []reflect.Method) ([]reflect.Method, []string) {
+	var valid []reflect.Method
+	var invalid []string
+	for _, method := range methods {
+		if _, ok := r.propertyChecker(method); ok || method.Type.NumIn() == 1 {
+			valid = append(valid, method)
+			continue
+		}
+		invalid = append(invalid, fmt.Sprintf("too many arguments to method %s %s at %s", method.Name, signature(method.Type, 1), r.methodLocation(method)))
+	}
+	return valid, invalid
+}
+
+// methodLocation returns the file and line the method is declared at.
+func (r *suiteRun) methodLocation(method reflect.Method) string {
+	fn := method.Func
+	if isAutogenerated(fn) {
+		// Methods with value receivers are wrapped for the pointer type.
+		if typ := reflect.TypeOf(r.suite); typ.Kind() == reflect.Ptr {
+			if m, ok := typ.Elem().MethodByName(method.Name); ok {
+				fn = m.Func
+			}
+		}
+	}
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return "unknown location"
+	}
+	file, line := f.FileLine(f.Entry())
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
+
+// reportInvalidMethods fails the suite, or its InvalidMethods test if
+// WithInvalidMethodsTest is set, with all the invalid test methods at
+// once.
+func (r *suiteRun) reportInvalidMethods(invalid []string) {
+	r.suiteT.Helper()
+	message := "suite: " + strings.Join(invalid, "\nsuite: ")
+	if !r.opts.invalidMethodsTest {
+		r.suiteT.Error(message)
+		return
+	}
+	r.suiteT.Run(invalidMethodsTestName, func(t *testing.T) {
+		t.Helper()
+		for _, reporter := range r.opts.reporters {
+			reporter.TestStarted(r.suiteName, invalidMethodsTestName)
+		}
+		t.Error(message)
+		r.finishTest(t, TestResult{Suite: r.suiteName, Name: invalidMethodsTestName, FullName: t.Name()})
+	})
+}
@@ -0,0 +1,41 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteInvalidMethodsTester struct {
+	Suite
+}
+
+func (s *SuiteInvalidMethodsTester) TestValid() {}
+
+func (s *SuiteInvalidMethodsTester) TestOne(a int) {}
+
+func (s SuiteInvalidMethodsTester) TestTwo(a, b string) {}
+
+func TestInvalidMethods(t *testing.T) {
+	recorder := new(recordingReporter)
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteInvalidMethodsTester), WithReporter(recorder))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Regexp(t, `suite: too many arguments to method TestOne func\(int\) at signatures_test.go:16\n\s+suite: too many arguments to method TestTwo func\(string, string\) at signatures_test.go:18`, output)
+	require.Len(t, recorder.Suites, 1)
+	require.Len(t, recorder.Suites[0].Tests, 1, "the valid tests run")
+	assert.Equal(t, "TestValid", recorder.Suites[0].Tests[0].Name)
+
+	recorder = new(recordingReporter)
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteInvalidMethodsTester), WithReporter(recorder), WithInvalidMethodsTest())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/InvalidMethods")
+	require.Len(t, recorder.Suites, 1)
+	statuses := make(map[string]Status)
+	for _, result := range recorder.Suites[0].Tests {
+		statuses[result.Name] = result.Status
+	}
+	assert.Equal(t, map[string]Status{"InvalidMethods": StatusFailed, "TestValid": StatusPassed}, statuses)
+}
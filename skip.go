@@ -0,0 +1,107 @@
+package suite
+
+import (
+	"runtime"
+	"testing"
+)
+
+// SkipCategory classifies the reason for skipping a test or a suite, so
+// that reports can tell e.g. tests skipped in short mode from quarantined
+// tests.
+type SkipCategory string
+
+const (
+	// SkipExplicit is the category of tests skipped by calling the Skip
+	// methods of the *testing.T directly, whose reason isn't recorded.
+	SkipExplicit SkipCategory = "explicit"
+	// SkipShort is the category of tests skipped in short mode, see
+	// SkipIfShort.
+	SkipShort SkipCategory = "short"
+	// SkipEnvironment is the category of tests and suites skipped because
+	// a setting of their environment is missing, such as an environment
+	// variable or a secret.
+	SkipEnvironment SkipCategory = "environment"
+	// SkipPlatform is the category of tests skipped on the operating
+	// system or build they run on, e.g. by SkipOnOS.
+	SkipPlatform SkipCategory = "platform"
+	// SkipCondition is the category of suites skipped by their ShouldRun
+	// method, see ConditionalSuite.
+	SkipCondition SkipCategory = "condition"
+	// SkipPreflight is the category of suites skipped because their
+	// Preflight method failed, see PreflightSuite.
+	SkipPreflight SkipCategory = "preflight"
+	// SkipPending is the category of pending tests.
+	SkipPending SkipCategory = "pending"
+	// SkipFailFast is the category of tests skipped because an earlier
+	// test failed, see WithFailFast.
+	SkipFailFast SkipCategory = "fail-fast"
+	// SkipQuarantined is the category of tests skipped because they are
+	// known to be broken or flaky, see SkipBecause.
+	SkipQuarantined SkipCategory = "quarantined"
+)
+
+// SkipIfShort skips the current test when running with -short.
+func (suite *Suite) SkipIfShort(reason string) {
+	suite.T().Helper()
+	if testing.Short() {
+		suite.skip(SkipShort, "short mode: "+reason)
+	}
+}
+
+// SkipUnlessEnv skips the current test unless the environment variable
+// name is set to a non-empty value in the environment of the test, e.g.
+// SkipUnlessEnv("INTEGRATION"). See Env.
+func (suite *Suite) SkipUnlessEnv(name string) {
+	suite.T().Helper()
+	if suite.env().Get(name) == "" {
+		suite.skip(SkipEnvironment, "environment variable "+name+" is not set")
+	}
+}
+
+// SkipOnOS skips the current test when running on one of the given
+// operating systems, as named by runtime.GOOS.
+func (suite *Suite) SkipOnOS(goos ...string) {
+	suite.T().Helper()
+	for _, name := range goos {
+		if runtime.GOOS == name {
+			suite.skip(SkipPlatform, "not supported on "+name)
+		}
+	}
+}
+
+// SkipBecause skips the current test for reason, reported with category,
+// e.g. SkipBecause(SkipQuarantined, "flaky, see #123"). Categories other
+// than those of this package can be used for reasons specific to a project.
+func (suite *Suite) SkipBecause(category SkipCategory, reason string) {
+	suite.T().Helper()
+	suite.skip(category, reason)
+}
+
+// skip skips the current test, recording its category and reason for the
+// reporters.
+func (suite *Suite) skip(category SkipCategory, reason string) {
+	suite.T().Helper()
+	suite.test.skipCategory = category
+	suite.test.skipReason = reason
+	suite.T().Skip("suite: skipped: " + reason)
+}
+
+// skipTest skips the current test of the suite for reason, recording the
+// category and the reason if the suite embeds Suite.
+func (r *suiteRun) skipTest(t *testing.T, category SkipCategory, reason string) {
+	t.Helper()
+	if b, ok := r.suite.(suiteBase); ok {
+		b.base().test.skipCategory = category
+		b.base().test.skipReason = reason
+	}
+	t.Skip("suite: skipped: " + reason)
+}
+
+// skipSuite skips the whole suite for reason, recording the category and
+// the reason in the result of the suite.
+func (r *suiteRun) skipSuite(category SkipCategory, reason string) {
+	r.suiteT.Helper()
+	r.result.SkipCategory = category
+	r.result.SkipReason = reason
+	r.suiteT.Skip("suite: skipped: " + reason)
+}
@@ -0,0 +1,73 @@
+package suite
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteSkipHelpersTester struct {
+	Suite
+
+	Ran []string
+}
+
+func (s *SuiteSkipHelpersTester) TestSkipOnOS() {
+	s.SkipOnOS("plan9", runtime.GOOS)
+	s.Ran = append(s.Ran, "TestSkipOnOS")
+}
+
+func (s *SuiteSkipHelpersTester) TestSkipUnlessEnv() {
+	s.SkipUnlessEnv("SUITE_TEST_UNSET_VARIABLE")
+	s.Ran = append(s.Ran, "TestSkipUnlessEnv")
+}
+
+func (s *SuiteSkipHelpersTester) TestSkipUnlessEnvSet() {
+	s.SkipUnlessEnv("SUITE_TEST_SET_VARIABLE")
+	s.Ran = append(s.Ran, "TestSkipUnlessEnvSet")
+}
+
+func TestSuiteSkipHelpers(t *testing.T) {
+	t.Setenv("SUITE_TEST_SET_VARIABLE", "1")
+	s := new(SuiteSkipHelpersTester)
+	recorder := new(recordingReporter)
+	Run(t, s, WithReporter(recorder))
+
+	assert.Equal(t, []string{"TestSkipUnlessEnvSet"}, s.Ran)
+	require.Len(t, recorder.Finished, 3)
+	assert.Equal(t, "not supported on "+runtime.GOOS, recorder.Finished[0].SkipReason)
+	assert.Equal(t, SkipPlatform, recorder.Finished[0].SkipCategory)
+	assert.Equal(t, "environment variable SUITE_TEST_UNSET_VARIABLE is not set", recorder.Finished[1].SkipReason)
+	assert.Equal(t, SkipEnvironment, recorder.Finished[1].SkipCategory)
+	assert.Equal(t, StatusPassed, recorder.Finished[2].Status)
+	assert.Equal(t, SkipCategory(""), recorder.Finished[2].SkipCategory)
+}
+
+type SuiteSkipCategoryTester struct {
+	Suite
+}
+
+func (s *SuiteSkipCategoryTester) TestExplicit() {
+	s.T().Skip("not today")
+}
+
+func (s *SuiteSkipCategoryTester) TestQuarantined() {
+	s.SkipBecause(SkipQuarantined, "flaky, see #123")
+}
+
+func TestSkipCategories(t *testing.T) {
+	recorder := new(recordingReporter)
+	var buf bytes.Buffer
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteSkipCategoryTester), WithReporter(recorder), WithReporter(NewJSONReporter(&buf)))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	require.Len(t, recorder.Finished, 2)
+	assert.Equal(t, SkipExplicit, recorder.Finished[0].SkipCategory)
+	assert.Equal(t, "", recorder.Finished[0].SkipReason)
+	assert.Equal(t, SkipQuarantined, recorder.Finished[1].SkipCategory)
+	assert.Equal(t, "flaky, see #123", recorder.Finished[1].SkipReason)
+	assert.Contains(t, buf.String(), `"skip_reason":"flaky, see #123","skip_category":"quarantined"`)
+}
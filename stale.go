@@ -0,0 +1,116 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var detectStaleT = flag.Bool("testify.detect-stale-t", false, "report calls of T by goroutines outliving the testify suite test that started them")
+
+// WithStaleTDetection reports the calls of Suite.T, including those made by
+// the assertion methods of Suite, by goroutines that were started by a
+// test and outlived it. Such goroutines get the T of whatever test runs at
+// the time, so that their failures are attributed to the wrong test or
+// lost. The call is reported as a failure of the current test, pointing
+// at the call site and naming the test that started the goroutine.
+//
+// The goroutines still running after a test are only told apart from
+// those of other tests when the tests of the suite don't run in parallel,
+// so detection is disabled for parallel suites. It is also enabled for all
+// suites by the -testify.detect-stale-t flag.
+func WithStaleTDetection() Option {
+	return func(o *options) {
+		o.detectStaleT = true
+	}
+}
+
+// staleGoroutines tracks the goroutines started by the finished tests of a
+// suite that are still running.
+type staleGoroutines struct {
+	mu sync.Mutex
+	// tests are the names of the tests that started the goroutines, by
+	// goroutine ID.
+	tests map[string]string
+}
+
+// newStaleGoroutines returns the tracker of the stale goroutines of the
+// suite, or nil if detection is disabled.
+func (r *suiteRun) newStaleGoroutines() *staleGoroutines {
+	if !r.opts.detectStaleT {
+		return nil
+	}
+	if r.opts.parallel {
+		r.infof(r.suiteT, "stale T detection is disabled, as the tests of %s run in parallel", r.suiteName)
+		return nil
+	}
+	return &staleGoroutines{tests: make(map[string]string)}
+}
+
+// track takes a snapshot of the running goroutines. The returned function
+// records those started since and still running as stale goroutines of
+// the test name.
+func (s *staleGoroutines) track(name string) func() {
+	if s == nil {
+		return func() {}
+	}
+	before := goroutines()
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for id := range goroutines() {
+			if _, ok := before[id]; !ok {
+				s.tests[id] = name
+			}
+		}
+	}
+}
+
+// check fails t if the calling goroutine is a stale goroutine, once per
+// goroutine.
+func (s *staleGoroutines) check(t *testing.T) {
+	if s == nil || t == nil {
+		return
+	}
+	id := goroutineID()
+	s.mu.Lock()
+	test, ok := s.tests[id]
+	delete(s.tests, id)
+	s.mu.Unlock()
+	if ok {
+		t.Errorf("suite: T called at %s by a goroutine started by %s, which has finished", staleCallSite(), test)
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine.
+func goroutineID() string {
+	buf := make([]byte, 64)
+	// The stack starts with a header like "goroutine 7 [running]:".
+	fields := strings.Fields(string(buf[:runtime.Stack(buf, false)]))
+	if len(fields) > 1 {
+		return fields[1]
+	}
+	return ""
+}
+
+// staleCallSite returns the location of the call of Suite.T, or of the
+// method of Suite calling it, that check reports.
+func staleCallSite() string {
+	prefix := reflect.TypeOf(Suite{}).PkgPath() + "."
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, prefix+"(*Suite).") && !strings.HasPrefix(frame.Function, prefix+"(*staleGoroutines).") {
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return "unknown location"
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package suite
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteStaleTTester struct {
+	Suite
+
+	release chan struct{}
+	done    sync.WaitGroup
+}
+
+func (s *SuiteStaleTTester) SetupSuite() {
+	s.release = make(chan struct{})
+}
+
+func (s *SuiteStaleTTester) TestA() {
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		<-s.release
+		s.Equal(1, 1)
+	}()
+}
+
+func (s *SuiteStaleTTester) TestB() {
+	close(s.release)
+	s.done.Wait()
+}
+
+func TestStaleTDetection(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteStaleTTester))
+	require.NoError(t, err)
+	assert.True(t, ok, "stale T calls are only reported with WithStaleTDetection")
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteStaleTTester), WithStaleTDetection())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "--- FAIL: DetachedSuite/TestB")
+	assert.Regexp(t, `suite: T called at stale_test.go:27 by a goroutine started by TestA, which has finished`, output)
+}
@@ -0,0 +1,95 @@
+package suite
+
+import (
+	"flag"
+	"os"
+	"sync"
+	"testing"
+)
+
+// standaloneMu serializes standalone runs, which replace os.Stdout and
+// os.Stderr.
+var standaloneMu sync.Mutex
+
+// StandaloneResult is the outcome of RunStandalone.
+type StandaloneResult struct {
+	// Passed reports whether no test of the suite failed.
+	Passed bool
+	Suite  SuiteResult
+	// Output is what the testing package and the tests wrote to os.Stdout
+	// and os.Stderr while the suite ran.
+	Output string
+}
+
+// RunStandalone runs suite outside of go test, e.g. in a smoke-test binary
+// or a self-test endpoint of a service, and returns its results:
+//
+//	result := suite.RunStandalone(new(DependenciesSuite))
+//	if !result.Passed {
+//		log.Fatalf("self-test failed:\n%s", result.Output)
+//	}
+//
+// The suite runs as a test named after its type, driven by the testing
+// package as go test would. The flags of the testing package, such as
+// -test.v and -test.timeout, are registered by RunStandalone if needed and
+// apply to the run; use WithFilter to select the tests to run. If the
+// command line hasn't been parsed yet, an empty one is parsed, as the
+// testing package requires it.
+//
+// os.Stdout and os.Stderr are redirected while the suite runs, so
+// standalone runs are serialized.
+func RunStandalone(suite TestingSuite, opts ...Option) StandaloneResult {
+	testing.Init()
+	if !flag.Parsed() {
+		flag.CommandLine.Parse(nil)
+	}
+	standaloneMu.Lock()
+	defer standaloneMu.Unlock()
+
+	runner := NewRunner(opts...)
+	defer runner.Close()
+	tests := []testing.InternalTest{{
+		Name: typeName(suite),
+		F: func(t *testing.T) {
+			runner.Run(t, suite)
+		},
+	}}
+	var result StandaloneResult
+	result.Output = redirectOutput(func() {
+		// The test named after the suite always runs: the -test.run
+		// pattern of a go test run applies to the tests of the binary,
+		// not to the suite run within one of them.
+		result.Passed = testing.RunTests(matchAll, tests)
+	})
+	if results := runner.Results(); len(results) > 0 {
+		result.Suite = results[0]
+	}
+	return result
+}
+
+// redirectOutput calls fn and returns what it wrote to os.Stdout and
+// os.Stderr, or "" if they can't be redirected.
+func redirectOutput(fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		fn()
+		return ""
+	}
+	output := readPipe(r)
+	func() {
+		origOut, origErr := os.Stdout, os.Stderr
+		os.Stdout, os.Stderr = w, w
+		defer func() {
+			os.Stdout, os.Stderr = origOut, origErr
+			w.Close()
+		}()
+		fn()
+	}()
+	return <-output
+}
+
+// matchAll is the matcher of the tests of standalone runs, which runs all
+// of them.
+func matchAll(_, _ string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package suite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteStandaloneTester struct {
+	Suite
+}
+
+func (s *SuiteStandaloneTester) TestPasses() {}
+
+func (s *SuiteStandaloneTester) TestFails() {
+	s.T().Error("STANDALONE FAILURE " + strings.Repeat("x", 100000))
+}
+
+func TestRunStandalone(t *testing.T) {
+	result := RunStandalone(new(SuiteStandaloneTester))
+	assert.False(t, result.Passed)
+	assert.Equal(t, "SuiteStandaloneTester", result.Suite.Name)
+	require.Len(t, result.Suite.Tests, 2)
+	assert.Equal(t, "SuiteStandaloneTester/TestFails", result.Suite.Tests[0].FullName)
+	assert.Equal(t, StatusFailed, result.Suite.Tests[0].Status)
+	assert.Equal(t, StatusPassed, result.Suite.Tests[1].Status)
+	// Output larger than the buffer of a pipe is captured.
+	assert.Contains(t, result.Output, "STANDALONE FAILURE xxx")
+	assert.Contains(t, result.Output, "--- FAIL: SuiteStandaloneTester/TestFails")
+
+	result = RunStandalone(new(SuiteStandaloneTester), WithFilter(func(name string) bool { return name == "TestPasses" }))
+	assert.True(t, result.Passed)
+	assert.Len(t, result.Suite.Tests, 1)
+}
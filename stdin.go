@@ -0,0 +1,52 @@
+package suite
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// stdinMu serializes the replacement of os.Stdin across suites.
+var stdinMu sync.Mutex
+
+// WithStdin calls fn with os.Stdin replaced by a pipe from which the
+// contents of r can be read, e.g. to test interactive command-line tools.
+// Reading from os.Stdin returns io.EOF once r is exhausted. os.Stdin is
+// restored when fn returns, also if it fails the test or panics.
+//
+// Since os.Stdin is global, concurrent calls of WithStdin wait for each
+// other.
+func (suite *Suite) WithStdin(r io.Reader, fn func()) {
+	suite.T().Helper()
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		suite.T().Fatalf("suite: failed to replace stdin: %v", err)
+	}
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// Writing fails once the read end is closed, if fn didn't read
+		// all of r.
+		io.Copy(pw, r)
+		pw.Close()
+	}()
+
+	stdin := os.Stdin
+	os.Stdin = pr
+	defer func() {
+		os.Stdin = stdin
+		pr.Close()
+		<-copied
+	}()
+	fn()
+}
+
+// WithStdinString is like WithStdin, with os.Stdin reading input.
+func (suite *Suite) WithStdinString(input string, fn func()) {
+	suite.T().Helper()
+	suite.WithStdin(strings.NewReader(input), fn)
+}
@@ -0,0 +1,38 @@
+package suite
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteStdinTester struct {
+	Suite
+}
+
+func (s *SuiteStdinTester) TestReadAll() {
+	stdin := os.Stdin
+	s.WithStdinString("hello\nworld\n", func() {
+		input, err := io.ReadAll(os.Stdin)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), "hello\nworld\n", string(input))
+	})
+	assert.Same(s.T(), stdin, os.Stdin)
+}
+
+func (s *SuiteStdinTester) TestPartialRead() {
+	input := strings.Repeat("y\n", 1<<16)
+	s.WithStdin(strings.NewReader(input), func() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), "y\n", line)
+	})
+}
+
+func TestStdin(t *testing.T) {
+	Run(t, new(SuiteStdinTester))
+}
@@ -0,0 +1,82 @@
+package suite
+
+import (
+	"sync"
+	"time"
+
+	stretchr "github.com/stretchr/testify/suite"
+)
+
+// setS calls the SetS method of a suite written against
+// github.com/stretchr/testify/suite with the suite itself, so that the Run
+// method of stretchr's Suite calls SetupSubTest and TearDownSubTest.
+//
+// Such suites otherwise run unchanged, as their TestingSuite and hook
+// interfaces match those of this package. Importing stretchr's package
+// also makes it register the -testify.m flag before this package looks it
+// up, so that suites of both packages can be linked into one test binary.
+func setS(suite TestingSuite) {
+	if s, ok := suite.(stretchr.TestingSuite); ok {
+		s.SetS(s)
+	}
+}
+
+// handleStats returns the function passing the statistics of the run to
+// the HandleStats method of a suite written against stretchr's package.
+func (r *suiteRun) handleStats() func() {
+	withStats, ok := r.suite.(stretchr.WithStats)
+	if !ok {
+		return func() {}
+	}
+	stats := &statsReporter{start: time.Now(), tests: make(map[string]*testStats)}
+	r.opts.reporters = append(r.opts.reporters, stats)
+	return func() {
+		stats.mu.Lock()
+		defer stats.mu.Unlock()
+		info := &stretchr.SuiteInformation{
+			Start:     stats.start,
+			End:       time.Now(),
+			TestStats: make(map[string]*stretchr.TestInformation),
+		}
+		for name, test := range stats.tests {
+			info.TestStats[name] = &stretchr.TestInformation{
+				TestName: name,
+				Start:    test.start,
+				End:      test.end,
+				Passed:   test.passed,
+			}
+		}
+		withStats.HandleStats(r.suiteName, info)
+	}
+}
+
+// statsReporter records the statistics stretchr's HandleStats expects.
+type statsReporter struct {
+	mu    sync.Mutex
+	start time.Time
+	tests map[string]*testStats
+}
+
+type testStats struct {
+	start, end time.Time
+	passed     bool
+}
+
+func (r *statsReporter) SuiteStarted(suiteName string) {}
+
+func (r *statsReporter) TestStarted(suiteName, testName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tests[testName] = &testStats{start: time.Now()}
+}
+
+func (r *statsReporter) TestFinished(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if test, ok := r.tests[result.Name]; ok {
+		test.end = time.Now()
+		test.passed = result.Status != StatusFailed
+	}
+}
+
+func (r *statsReporter) SuiteFinished(result SuiteResult) {}
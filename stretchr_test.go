@@ -0,0 +1,57 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	stretchr "github.com/stretchr/testify/suite"
+)
+
+type SuiteStretchrTester struct {
+	stretchr.Suite
+
+	setups    int
+	subSetups int
+	suiteName string
+	stats     *stretchr.SuiteInformation
+}
+
+func (s *SuiteStretchrTester) SetupTest() {
+	s.setups++
+}
+
+func (s *SuiteStretchrTester) SetupSubTest() {
+	s.subSetups++
+}
+
+func (s *SuiteStretchrTester) HandleStats(suiteName string, stats *stretchr.SuiteInformation) {
+	s.suiteName, s.stats = suiteName, stats
+}
+
+func (s *SuiteStretchrTester) TestPass() {
+	s.Run("sub", func() {
+		s.Equal(1, 1)
+	})
+}
+
+func (s *SuiteStretchrTester) TestFail() {
+	s.T().Fail()
+}
+
+func TestStretchrSuite(t *testing.T) {
+	s := new(SuiteStretchrTester)
+	ok, _, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, 1, s.subSetups, "SetS must be called with the suite")
+	assert.Equal(t, 2, s.setups)
+	assert.Equal(t, "SuiteStretchrTester", s.suiteName)
+	require.NotNil(t, s.stats)
+	assert.False(t, s.stats.End.Before(s.stats.Start))
+	require.Len(t, s.stats.TestStats, 2)
+	assert.Equal(t, "TestPass", s.stats.TestStats["TestPass"].TestName)
+	assert.True(t, s.stats.TestStats["TestPass"].Passed)
+	assert.False(t, s.stats.TestStats["TestFail"].Passed)
+}
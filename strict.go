@@ -0,0 +1,126 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var strict = flag.Bool("testify.strict", false, "fail testify suites with misspelled hooks or hooks of the wrong signature")
+
+// WithStrict fails the suite before SetupSuite if it has methods that look
+// like hooks but would silently never be called: methods whose name is
+// close to that of a hook, e.g. SetUpTest or BeforeTests, and methods
+// named after a hook but with a different signature, e.g.
+// SetupTest(t *testing.T). Strict mode is also enabled for all suites by
+// the -testify.strict flag.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// hookInterfaces are the interfaces the methods of suites checked by
+// strict mode must implement, by method name.
+var hookInterfaces = map[string]reflect.Type{
+	"SetupSuite":             reflect.TypeOf((*SetupAllSuite)(nil)).Elem(),
+	"TearDownSuite":          reflect.TypeOf((*TearDownAllSuite)(nil)).Elem(),
+	"TearDownSuiteOnFailure": reflect.TypeOf((*TearDownOnFailureSuite)(nil)).Elem(),
+	"SetupTest":              reflect.TypeOf((*SetupTestSuite)(nil)).Elem(),
+	"TearDownTest":           reflect.TypeOf((*TearDownTestSuite)(nil)).Elem(),
+	"TearDownTestContext":    reflect.TypeOf((*TearDownTestContextSuite)(nil)).Elem(),
+	"TearDownSuiteContext":   reflect.TypeOf((*TearDownSuiteContextSuite)(nil)).Elem(),
+	"BeforeTest":             reflect.TypeOf((*BeforeTest)(nil)).Elem(),
+	"AfterTest":              reflect.TypeOf((*AfterTest)(nil)).Elem(),
+	"ShouldRun":              reflect.TypeOf((*ConditionalSuite)(nil)).Elem(),
+	"Preflight":              reflect.TypeOf((*PreflightSuite)(nil)).Elem(),
+	"Groups":                 reflect.TypeOf((*GroupedSuite)(nil)).Elem(),
+	"Requires":               reflect.TypeOf((*ResourceSuite)(nil)).Elem(),
+	"SetupRetryPolicy":       reflect.TypeOf((*RetryableSetup)(nil)).Elem(),
+	"HandlePanic":            reflect.TypeOf((*PanicHandler)(nil)).Elem(),
+	"Metadata":               reflect.TypeOf((*MetadataSuite)(nil)).Elem(),
+	"AllocBudgets":           reflect.TypeOf((*AllocBudgetSuite)(nil)).Elem(),
+	"NeedsOSThread":          reflect.TypeOf((*OSThreadSuite)(nil)).Elem(),
+}
+
+// maxHookTypo is the largest edit distance between the name of a method
+// and that of a hook at which strict mode considers the method a
+// misspelled hook.
+const maxHookTypo = 2
+
+// checkStrict returns an error describing the methods of the suite that
+// look like hooks but wouldn't be called, if strict mode is enabled.
+func (r *suiteRun) checkStrict() error {
+	if !r.opts.strict {
+		return nil
+	}
+	hooks := make([]string, 0, len(hookInterfaces))
+	for name := range hookInterfaces {
+		hooks = append(hooks, name)
+	}
+	sort.Strings(hooks)
+
+	var problems []string
+	typ := reflect.TypeOf(r.suite)
+	base := reflect.TypeOf((*Suite)(nil))
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if iface, ok := hookInterfaces[method.Name]; ok {
+			if !typ.Implements(iface) {
+				want, _ := iface.MethodByName(method.Name)
+				problems = append(problems, fmt.Sprintf("%s has signature %s, so it is never called; want %s",
+					method.Name, signature(method.Type, 1), signature(want.Type, 0)))
+			}
+			continue
+		}
+		if _, ok := base.MethodByName(method.Name); ok {
+			continue
+		}
+		for _, hook := range hooks {
+			if strings.EqualFold(method.Name, hook) || editDistance(method.Name, hook) <= maxHookTypo {
+				problems = append(problems, fmt.Sprintf("%s looks like a misspelled %s, which it is never called as", method.Name, hook))
+				break
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: methods that are never called as hooks:\n\t%s", strings.Join(problems, "\n\t"))
+}
+
+// signature returns the signature of the function type typ without its
+// first skip parameters, e.g. without the receiver of a method.
+func signature(typ reflect.Type, skip int) string {
+	var in, out []reflect.Type
+	for i := skip; i < typ.NumIn(); i++ {
+		in = append(in, typ.In(i))
+	}
+	for i := 0; i < typ.NumOut(); i++ {
+		out = append(out, typ.Out(i))
+	}
+	return reflect.FuncOf(in, out, typ.IsVariadic()).String()
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,63 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteStrictTester struct {
+	Suite
+}
+
+func (s *SuiteStrictTester) SetUpTest() {}
+
+func (s *SuiteStrictTester) TeardownSuite() {}
+
+func (s *SuiteStrictTester) BeforeTests() {}
+
+func (s *SuiteStrictTester) TearDownTest(t *testing.T) {}
+
+func (s *SuiteStrictTester) SetupSuite() {}
+
+func (s *SuiteStrictTester) HandlePanic(recovered interface{}) {}
+
+func (s *SuiteStrictTester) Metadata() map[string]string { return nil }
+
+func (s *SuiteStrictTester) AllocBudget() map[string]AllocBudget { return nil }
+
+func (s *SuiteStrictTester) NeedsOSThread(test string) bool { return false }
+
+func (s *SuiteStrictTester) TestOne() {}
+
+func TestStrict(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteStrictTester))
+	require.NoError(t, err)
+	assert.True(t, ok, "misspelled hooks only fail in strict mode")
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteStrictTester), WithStrict())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: SuiteStrictTester: strict mode: methods that are never called as hooks:")
+	assert.Contains(t, output, "BeforeTests looks like a misspelled BeforeTest")
+	assert.Contains(t, output, "SetUpTest looks like a misspelled SetupTest")
+	assert.Contains(t, output, "TeardownSuite looks like a misspelled TearDownSuite")
+	assert.Contains(t, output, "TearDownTest has signature func(*testing.T), so it is never called; want func()")
+	assert.Contains(t, output, "HandlePanic has signature func(interface {}), so it is never called; want func(string, interface {}, []uint8)")
+	assert.Contains(t, output, "Metadata has signature func() map[string]string, so it is never called; want func() map[string]suite.Metadata")
+	assert.Contains(t, output, "AllocBudget looks like a misspelled AllocBudgets")
+	assert.Contains(t, output, "NeedsOSThread has signature func(string) bool, so it is never called; want func() bool")
+	assert.NotContains(t, output, "SetupSuite looks like")
+
+	ok, output, err = runDetachedSuiteWithOutputCapture(new(SuiteMetadataTester), WithStrict(), WithFilter(func(string) bool { return false }))
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("SetupTest", "SetupTest"))
+	assert.Equal(t, 1, editDistance("BeforeTests", "BeforeTest"))
+	assert.Equal(t, 2, editDistance("SetUpTest", "setupTest"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
@@ -0,0 +1,69 @@
+package suite
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Run runs subtest as a subtest of the current test named name, e.g. for
+// the cases of a table-driven test. T returns the subtest's T while
+// subtest runs, for the suite and its mixins.
+//
+// Names are sanitized like the names of test methods, and made unique
+// among the subtests of the current test by appending "_2", "_3" and so
+// on to repeated names, so that two cases never run as the same subtest
+// and `go test -run` patterns keep selecting them. The name a subtest was
+// renamed to is logged.
+func (suite *Suite) Run(name string, subtest func()) bool {
+	parent := suite.T()
+	parent.Helper()
+	unique := suite.test.subtestName(parent, name)
+	if unique != name {
+		parent.Logf("suite: running subtest %q as %q", name, unique)
+	}
+	return parent.Run(unique, func(t *testing.T) {
+		suite.setSubtestT(t)
+		defer suite.setSubtestT(parent)
+		subtest()
+	})
+}
+
+// setSubtestT sets the T of the suite and its mixins.
+func (suite *Suite) setSubtestT(t *testing.T) {
+	suite.SetT(t)
+	for _, mixin := range suite.mixins {
+		if s, ok := mixin.(TestingSuite); ok {
+			s.SetT(t)
+		}
+	}
+}
+
+// subtestName returns the sanitized name, unique among the subtests of
+// parent, of the subtest named name.
+func (state *testState) subtestName(parent *testing.T, name string) string {
+	if state.subtests == nil {
+		state.subtests = make(map[*testing.T]subtestNames)
+	}
+	if state.subtests[parent] == nil {
+		state.subtests[parent] = make(subtestNames)
+	}
+	return state.subtests[parent].unique(name)
+}
+
+// subtestNames holds the names of the subtests of a test.
+type subtestNames map[string]bool
+
+// unique returns name sanitized, with a suffix making it unique among the
+// names returned before.
+func (names subtestNames) unique(name string) string {
+	name = sanitizeName(name)
+	if name == "" {
+		name = "_"
+	}
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[unique] = true
+	return unique
+}
@@ -0,0 +1,41 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SuiteSubtestTester struct {
+	Suite
+	names []string
+}
+
+func (s *SuiteSubtestTester) TestTable() {
+	parent := s.T()
+	for _, name := range []string{"a/b", "a b", "a_b", "c", "c", ""} {
+		s.Run(name, func() {
+			assert.NotSame(s.T(), parent, s.T())
+			s.names = append(s.names, s.T().Name())
+		})
+	}
+	assert.Same(s.T(), parent, s.T())
+}
+
+func TestSuiteRunUniqueNames(t *testing.T) {
+	s := new(SuiteSubtestTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"DetachedSuite/TestTable/a_b",
+		"DetachedSuite/TestTable/a_b_2",
+		"DetachedSuite/TestTable/a_b_3",
+		"DetachedSuite/TestTable/c",
+		"DetachedSuite/TestTable/c_2",
+		"DetachedSuite/TestTable/_",
+	}, s.names)
+	if testing.Verbose() {
+		assert.Contains(t, output, `suite: running subtest "a b" as "a_b_2"`)
+	}
+}
@@ -1,91 +1,627 @@
 package suite
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"math/rand"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/spf13/afero"
 )
 
-var matchMethod = flag.String("testify.m", "", "regular expression to select tests of the testify suite to run")
+// matchMethod is the -testify.m flag, registered by stretchr's suite
+// package (see setS) and shared with it.
+var matchMethod = flag.Lookup("testify.m")
+
+const traceTimeFormat = "15:04:05.000000"
 
 // Suite is a basic testing suite with methods for storing and
 // retrieving the current *testing.T context.
 type Suite struct {
 	t *testing.T
+
+	seed  int64
+	rand  *rand.Rand
+	clock Clock
+	ctx   context.Context
+	test  testState
+
+	comparison   comparison
+	mixins       []interface{}
+	shared       map[string]bool
+	metadata     map[string]Metadata
+	allocBudgets map[string]AllocBudget
+	registered   registeredHooks
+	artifactDir  string
+	retention    Retention
+	// prefix is prepended to the failures and logs of the suite, see
+	// WithOutputPrefix.
+	prefix string
+	faults *FaultInjector
+	newFS  func(t *testing.T) afero.Fs
+	// newQueue creates the queue of every test, see WithQueue.
+	newQueue func(t *testing.T) QueueFixture
+	// noTimeouts disables the deadlines of the suite's helpers, see
+	// WithoutTimeouts.
+	noTimeouts bool
+	// focused lists the test methods focused with Focus.
+	focused []string
+	// stale tracks the goroutines outliving their test, see
+	// WithStaleTDetection.
+	stale *staleGoroutines
+	// benchGate compares the benchmarks of the suite against their
+	// baseline, see WithBenchmarkGate.
+	benchGate *BenchmarkGate
+	// suiteT is the test of the whole suite.
+	suiteT    *testing.T
+	mailSink  *MailSink
+	blobStore *MemBlobStore
+	// goroutineDump dumps the goroutines of failed tests, see
+	// WithGoroutineDump.
+	goroutineDump *goroutineDumper
+	// reaperDir is the directory recording the resources registered with
+	// Reap, see WithReaperDir.
+	reaperDir string
+	// flags tracks the feature flags overridden by the tests, see WithFlag.
+	flags *featureFlags
+	// secretsDir is the directory secrets are loaded from, see
+	// WithSecretsDir.
+	secretsDir string
+	// secrets are redacted from the output of the suite, see Redact.
+	secrets *redactor
+	// extracted maps the archives unpacked once per suite to their
+	// directory, see ExtractTestdata.
+	extracted *extractCache
+	// commandLog keeps the commands run by the tests until they finish,
+	// see Command.
+	commandLog *commandLog
+	// parallel is set if the tests run in parallel, see WithParallel.
+	parallel bool
 }
 
 // T retrieves the current *testing.T context.
 func (suite *Suite) T() *testing.T {
+	suite.stale.check(suite.t)
 	return suite.t
 }
 
 // SetT sets the current *testing.T context.
 func (suite *Suite) SetT(t *testing.T) {
 	suite.t = t
+	suite.rand = nil
+}
+
+// testState is the state of the embedded Suite that is reset before each
+// test.
+type testState struct {
+	randUsed     bool
+	skipReason   string
+	skipCategory SkipCategory
+	assertions   int
+	// failures are the messages of the failed assertions of the test.
+	failures []string
+	fs       afero.Fs
+	queue    QueueFixture
+	factory  *Factory
+	// goroutinesDumped is set once the goroutines of a failed test have
+	// been dumped, see WithGoroutineDump.
+	goroutinesDumped bool
+	// log buffers the output of a test running in parallel, see testLog.
+	log *testLog
+	// subtests holds the names of the subtests started by Run, by their
+	// parent.
+	subtests map[*testing.T]subtestNames
+	// setupAttempt collects the failures of the current attempt of a
+	// retried setup hook, see RetryableSetup.
+	setupAttempt *setupAttempt
+	// fixturesLocked is set once a test running in parallel has locked
+	// the fixtures shared by the tests, and blobStoreLocked and
+	// mailSinkLocked once it has prepared them, see lockSharedFixture.
+	fixturesLocked  bool
+	blobStoreLocked bool
+	mailSinkLocked  bool
+}
+
+// base gives the runner access to the state of the embedded Suite.
+func (suite *Suite) base() *Suite {
+	return suite
+}
+
+// suiteBase is implemented by suites that embed Suite.
+type suiteBase interface {
+	base() *Suite
 }
 
 // Run takes a testing suite and runs all of the tests attached
-// to it.
-func Run(suiteT *testing.T, suite TestingSuite) {
-	suite.SetT(suiteT)
+// to it. It is a shorthand for running the suite with a Runner of its own.
+func Run(suiteT *testing.T, suite TestingSuite, opts ...Option) {
+	suiteT.Helper()
+	runner := NewRunner(opts...)
+	defer runner.Close()
+	runner.Run(suiteT, suite)
+}
+
+// RunOne runs the single test method methodName of suite with all the
+// hooks of the suite. It is a shorthand for Runner.RunOne with a Runner of
+// its own.
+func RunOne(suiteT *testing.T, suite TestingSuite, methodName string, opts ...Option) {
+	suiteT.Helper()
+	runner := NewRunner(opts...)
+	defer runner.Close()
+	runner.RunOne(suiteT, suite, methodName)
+}
 
-	if setupAllSuite, ok := suite.(SetupAllSuite); ok {
-		setupAllSuite.SetupSuite()
+// suiteRun holds the state of a single run of a suite. Tests running in
+// parallel use copies of the suiteRun, sharing the result of the run.
+type suiteRun struct {
+	owner     *Runner
+	opts      *options
+	suite     TestingSuite
+	suiteT    *testing.T
+	suiteName string
+	result    *SuiteResult
+	mu        *sync.Mutex
+	ctx       context.Context
+	// test is the name of the running test method, if any.
+	test string
+	// groups are the locks of the serial groups of the tests, see
+	// GroupedSuite.
+	groups map[string]*sync.Mutex
+	// slots limit the number of tests running at the same time, see
+	// WithMaxParallel.
+	slots chan struct{}
+	// log buffers the output of the running test, see testLog.
+	log *testLog
+	// stale tracks the goroutines outliving their test, see
+	// WithStaleTDetection.
+	stale *staleGoroutines
+	// state is the snapshot of the fields of the suite, see
+	// WithMutationDetection.
+	state map[string]string
+	// runFilter selects the test methods matching go test's -run flag.
+	runFilter *runFilter
+	// memory tracks the memory usage of the suite, see WithMemoryGate.
+	memory *memoryWatch
+}
+
+func (r *suiteRun) run() {
+	suite, suiteT := r.suite, r.suiteT
+	suiteT.Helper()
+	r.lockOSThread("")
+	// The suite is finished once all of its tests are, which is only after
+	// run returns when they run in parallel.
+	finish := &deferredCalls{}
+	if r.opts.parallel {
+		suiteT.Cleanup(finish.run)
+	} else {
+		defer finish.run()
 	}
-	defer func() {
-		suite.SetT(suiteT)
-		if tearDownAllSuite, ok := suite.(TearDownAllSuite); ok {
-			tearDownAllSuite.TearDownSuite()
+	suiteCtx, cancel := newContext(r.parentContext(), suiteT)
+	finish.push(cancel)
+	suiteCtx = r.attachTestValues(suiteCtx, suiteT)
+	r.ctx = suiteCtx
+	r.stale = r.newStaleGoroutines()
+	if b, ok := suite.(suiteBase); ok {
+		b.base().seed = r.opts.seed
+		b.base().artifactDir = r.opts.artifactDir
+		b.base().retention = r.opts.retention
+		b.base().newFS = r.opts.newFS
+		b.base().newQueue = r.opts.newQueue
+		b.base().noTimeouts = r.opts.noTimeouts
+		b.base().ctx = suiteCtx
+		b.base().prefix = r.outputPrefix("")
+		b.base().stale = r.stale
+		b.base().benchGate = r.opts.benchGate
+		b.base().suiteT = suiteT
+		b.base().secretsDir = r.opts.secretsDir
+		b.base().reaperDir = r.opts.reaperDir
+		b.base().parallel = r.opts.parallel
+		if r.opts.goroutineDump {
+			b.base().goroutineDump = &goroutineDumper{ignore: r.opts.goroutineDumpIgnore}
 		}
-	}()
+		b.base().secrets = &redactor{}
+		b.base().flags = &featureFlags{provider: r.opts.flagProvider}
+		b.base().commandLog = &commandLog{runs: make(map[*testing.T][]string)}
+		b.base().extracted = &extractCache{dirs: make(map[string]string)}
+	}
+	setS(suite)
+	r.setT(suiteT)
+	if r.opts.noTimeouts {
+		r.infof(suiteT, "timeouts of %s are disabled", r.suiteName)
+	}
+
+	clock := r.clock()
+	start := clock.Now()
+	r.result.Name = r.suiteName
+	r.result.Race = raceEnabled
+	r.result.Environment = r.environment()
+	for _, reporter := range r.opts.reporters {
+		reporter.SuiteStarted(r.suiteName)
+	}
+	finish.push(func() {
+		r.result.Duration = clock.Since(start)
+		sort.SliceStable(r.result.Tests, func(i, j int) bool {
+			return r.result.Tests[i].Name < r.result.Tests[j].Name
+		})
+		for _, reporter := range r.opts.reporters {
+			reporter.SuiteFinished(*r.result)
+		}
+		r.owner.record(*r.result)
+	})
+	finish.push(r.handleStats())
+
+	if conditionalSuite, ok := suite.(ConditionalSuite); ok {
+		if ok, reason := conditionalSuite.ShouldRun(); !ok {
+			r.skipSuite(SkipCondition, reason)
+		}
+	}
+	if v := reflect.ValueOf(suite); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		if err := loadConfig(v.Elem()); err != nil {
+			suiteT.Fatalf("suite: %s: %v", r.suiteName, err)
+		}
+	}
+	missing, err := r.loadSecrets()
+	if err != nil {
+		suiteT.Fatalf("suite: %s: %v", r.suiteName, err)
+	}
+	if len(missing) > 0 {
+		r.skipSuite(SkipEnvironment, "missing secrets: "+strings.Join(missing, ", "))
+	}
+
+	if err := r.checkHookResolution(); err != nil {
+		suiteT.Fatalf("suite: %s: %v", r.suiteName, err)
+	}
+	if err := r.checkStrict(); err != nil {
+		suiteT.Fatalf("suite: %s: %v", r.suiteName, err)
+	}
+	if r.opts.only != "" {
+		if _, ok := reflect.TypeOf(suite).MethodByName(r.opts.only); !ok {
+			suiteT.Fatalf("suite: %s has no method %s", r.suiteName, r.opts.only)
+		}
+	}
+
+	r.runFilter = newRunFilter(suiteT)
+	if r.runExcludesAll() {
+		r.infof(suiteT, "no test methods of %s match -run, skipping its hooks", r.suiteName)
+		return
+	}
+
+	r.preflight()
+
+	finish.push(r.watchMemory())
+	finish.push(r.lockRequired())
+	finish.push(r.releaseShared)
+	r.callHooks(suiteT, "SetupSuite", false, func(target interface{}) func() {
+		if setupAllSuite, ok := target.(SetupAllSuite); ok {
+			return r.retrySetup(suiteT, suiteCtx, "SetupSuite", setupAllSuite.SetupSuite)
+		}
+		return nil
+	})
+	r.snapshotState()
+	finish.push(func() {
+		r.setT(suiteT)
+		if suiteT.Failed() || r.failed() {
+			r.callHooks(suiteT, "TearDownSuiteOnFailure", true, func(target interface{}) func() {
+				if onFailure, ok := target.(TearDownOnFailureSuite); ok {
+					return onFailure.TearDownSuiteOnFailure
+				}
+				return nil
+			})
+		}
+		r.callContextHooks(suiteT, suiteCtx, "TearDownSuiteContext", func(target interface{}) func(context.Context) {
+			if tearDownSuite, ok := target.(TearDownSuiteContextSuite); ok {
+				return tearDownSuite.TearDownSuiteContext
+			}
+			return nil
+		})
+		r.callHooks(suiteT, "TearDownSuite", true, func(target interface{}) func() {
+			if tearDownAllSuite, ok := target.(TearDownAllSuite); ok {
+				return tearDownAllSuite.TearDownSuite
+			}
+			return nil
+		})
+	})
+
+	if b, ok := suite.(suiteBase); ok && r.opts.parallel && len(b.base().mixins) > 0 {
+		suiteT.Fatalf("suite: %s: WithParallel can't be used with mixins, which would be shared by all tests", r.suiteName)
+	}
+	if err := r.checkIDs(); err != nil {
+		suiteT.Errorf("suite: %s: %v", r.suiteName, err)
+	}
+	groups, err := r.serialGroups()
+	if err != nil {
+		suiteT.Fatalf("suite: %s: %v", r.suiteName, err)
+	}
+	r.groups = groups
+	r.slots = r.parallelSlots()
+
+	r.callRegistered(suiteT, "BeforeAllTests", false, func(registered registeredHooks) []func() {
+		return registered.beforeAll
+	})
+
+	methods, err := r.focus(r.testMethods())
+	if err != nil {
+		suiteT.Errorf("suite: %s: %v", r.suiteName, err)
+	}
+	methods, invalid := r.validMethods(methods)
+	if len(invalid) > 0 {
+		r.reportInvalidMethods(invalid)
+	}
+	if r.opts.shuffle != nil {
+		rand.New(rand.NewSource(*r.opts.shuffle)).Shuffle(len(methods), func(i, j int) {
+			methods[i], methods[j] = methods[j], methods[i]
+		})
+		r.infof(suiteT, "shuffled the tests of %s with seed %d", r.suiteName, *r.opts.shuffle)
+	}
+	for _, method := range methods {
+		r.runTest(method)
+		r.setT(suiteT)
+	}
+	if len(methods) == 0 {
+		r.infof(suiteT, "no test methods of %s matched", r.suiteName)
+	}
+}
 
-	methodFinder := reflect.TypeOf(suite)
+// testMethods returns the test methods of the suite, in the order they are
+// run.
+func (r *suiteRun) testMethods() []reflect.Method {
+	if r.opts.only != "" {
+		method, ok := reflect.TypeOf(r.suite).MethodByName(r.opts.only)
+		if !ok || !r.runSelected(method.Name) {
+			return nil
+		}
+		return []reflect.Method{method}
+	}
+	var methods []reflect.Method
+	methodFinder := reflect.TypeOf(r.suite)
 	for index := 0; index < methodFinder.NumMethod(); index++ {
 		method := methodFinder.Method(index)
-		ok, err := methodFilter(method.Name)
+		prefixes := append(focusPrefixes(r.opts.prefixes), r.opts.prefixes...)
+		prefixes = append(prefixes, pendingTestPrefixes(r.opts.prefixes)...)
+		if _, ok := r.propertyChecker(method); ok {
+			prefixes = []string{propertyPrefix}
+		}
+		ok, err := methodFilter(method.Name, prefixes)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "testify: invalid regexp for -m: %s\n", err)
 			os.Exit(1)
 		}
-		if ok {
-			suiteT.Run(method.Name, func(testT *testing.T){
-				suite.SetT(testT)
-				if setupTestSuite, ok := suite.(SetupTestSuite); ok {
-					setupTestSuite.SetupTest()
-				}
-				if beforeTestSuite, ok := suite.(BeforeTest); ok {
-					// This is legacy behaviour that calls the test by the struct name and not the test name.
-					beforeTestSuite.BeforeTest(methodFinder.Elem().Name(), method.Name)
-				}
-				defer func() {
-					if afterTestSuite, ok := suite.(AfterTest); ok {
-						afterTestSuite.AfterTest(methodFinder.Elem().Name(), method.Name)
+		if ok && r.selected(method.Name) && r.runSelected(method.Name) {
+			methods = append(methods, method)
+		}
+	}
+	if r.opts.order != nil {
+		methods = inOrder(methods, r.opts.order)
+	}
+	return methods
+}
+
+// runTest runs a single test method as a subtest of the suite's test,
+// wrapped in the per-test hooks.
+//
+// The runner's frames are marked as helpers, so that the failures and logs
+// of the runner are attributed to the call of Run.
+func (r *suiteRun) runTest(method reflect.Method) {
+	r.suiteT.Helper()
+	name := sanitizeName(r.opts.namer(r.suiteName, method.Name))
+	r.suiteT.Run(name, func(testT *testing.T) {
+		testT.Helper()
+		r.lockOSThread(method.Name)
+		r := r
+		if r.opts.parallel {
+			testT.Parallel()
+			r = r.parallelCopy()
+		}
+		r.log = r.newTestLog(testT)
+		defer r.lockGroup(method.Name)()
+		defer r.acquireSlot()()
+		r.test = method.Name
+		defer func() { r.test = "" }()
+		suite := r.suite
+		clock := r.clock()
+		start := clock.Now()
+		for _, reporter := range r.opts.reporters {
+			reporter.TestStarted(r.suiteName, method.Name)
+		}
+		result := TestResult{Suite: r.suiteName, Name: method.Name, FullName: testT.Name()}
+		races := raceErrors()
+		stopCPU := r.trackCPU(&result.CPU)
+		defer func() {
+			stopCPU()
+			result.Duration = clock.Since(start)
+			result.RaceDetected = raceErrors() > races
+			r.finishTest(testT, result)
+		}()
+
+		ctx, cancel := newContext(r.ctx, testT)
+		defer cancel()
+		if r.opts.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, r.opts.timeout)
+			defer cancel()
+		}
+		ctx = r.attachTestValues(ctx, testT)
+		if b, ok := suite.(suiteBase); ok {
+			b.base().test = testState{log: r.log}
+			b.base().ctx = ctx
+			b.base().prefix = r.outputPrefix(method.Name)
+			b.base().setTestFaults(r.metadata(method.Name).Faults, testT.Name())
+		}
+		r.setT(testT)
+		if r.pending(method.Name) {
+			r.skipTest(testT, SkipPending, pendingReason)
+		}
+		if raceEnabled && r.metadata(method.Name).hasTag("norace") {
+			r.skipTest(testT, SkipPlatform, "test is tagged norace and the race detector is enabled")
+		}
+		if r.opts.failFast && r.failed() {
+			r.skipTest(testT, SkipFailFast, "an earlier test of the suite failed")
+		}
+		defer r.checkLeaks(testT)()
+		defer r.stale.track(method.Name)()
+		logs, stopCapture := r.captureLogs()
+		defer stopCapture()
+		r.callHooks(testT, "SetupTest", false, func(target interface{}) func() {
+			if setupTestSuite, ok := target.(SetupTestSuite); ok {
+				return r.retrySetup(testT, ctx, "SetupTest", setupTestSuite.SetupTest)
+			}
+			return nil
+		})
+		r.callHooks(testT, "BeforeTest", false, func(target interface{}) func() {
+			if beforeTestSuite, ok := target.(BeforeTest); ok {
+				// This is legacy behaviour that calls the test by the struct name and not the test name.
+				return func() { beforeTestSuite.BeforeTest(r.suiteName, method.Name) }
+			}
+			return nil
+		})
+		r.callRegistered(testT, "BeforeEach", false, func(registered registeredHooks) []func() {
+			return registered.beforeEach
+		})
+		// returned is false if the method timed out and is still running,
+		// in which case the teardown hooks would run concurrently with it.
+		returned := true
+		defer func() {
+			if returned {
+				r.callRegistered(testT, "AfterEach", true, func(registered registeredHooks) []func() {
+					return registered.afterEach
+				})
+				r.callHooks(testT, "AfterTest", true, func(target interface{}) func() {
+					if afterTestSuite, ok := target.(AfterTest); ok {
+						return func() { afterTestSuite.AfterTest(r.suiteName, method.Name) }
+					}
+					return nil
+				})
+				r.callContextHooks(testT, ctx, "TearDownTestContext", func(target interface{}) func(context.Context) {
+					if tearDownTest, ok := target.(TearDownTestContextSuite); ok {
+						return tearDownTest.TearDownTestContext
 					}
-					if tearDownTestSuite, ok := suite.(TearDownTestSuite); ok {
+					return nil
+				})
+				r.callHooks(testT, "TearDownTest", true, func(target interface{}) func() {
+					if tearDownTestSuite, ok := target.(TearDownTestSuite); ok {
 						// This is legacy behaviour that calls the test by the struct name and not the test name.
-						tearDownTestSuite.TearDownTest()
+						return tearDownTestSuite.TearDownTest
 					}
-					suite.SetT(suiteT)
-				}()
-				if method.Type.NumIn() == 1 {
-					method.Func.Call([]reflect.Value{reflect.ValueOf(suite)})
-				} else {
-					testT.Fatalf("suite: too many arguments to method %v", method.Name)
-				}
-			})
-			suite.SetT(suiteT)
+					return nil
+				})
+				r.checkState(testT, method.Name)
+			}
+			r.setT(r.suiteT)
+			if b, ok := suite.(suiteBase); ok {
+				b.base().ctx = r.ctx
+				b.base().prefix = r.outputPrefix("")
+				b.base().setTestFaults(nil, r.suiteT.Name())
+			}
+		}()
+		defer r.memory.sample()
+		defer r.trackAllocs(testT, method.Name, &result.Allocs)()
+		defer r.writeDiagnostics(testT, logs)
+		defer r.dumpGoroutinesOnFailure(testT)
+		returned = r.callMethod(testT, ctx, method)
+	})
+}
+
+// callHook calls the hook name of owner, logging its invocation to t when
+// hook tracing is enabled.
+func (r *suiteRun) callHook(t *testing.T, owner, name string, hook func()) {
+	t.Helper()
+	if r.opts.verbosity < VerbosityTrace {
+		if p := catchPanic(hook); p != nil {
+			r.failOnPanic(t, owner+"."+name, p)
+		}
+		return
+	}
+	clock := r.clock()
+	start := clock.Now()
+	r.logf(t, "suite: %s %s.%s started", start.Format(traceTimeFormat), owner, name)
+	completed := false
+	defer func() {
+		if !completed {
+			r.logf(t, "suite: %s %s.%s did not complete", clock.Now().Format(traceTimeFormat), owner, name)
+		}
+	}()
+	if p := catchPanic(hook); p != nil {
+		r.failOnPanic(t, owner+"."+name, p)
+	}
+	completed = true
+	r.logf(t, "suite: %s %s.%s finished in %v", clock.Now().Format(traceTimeFormat), owner, name, clock.Since(start))
+}
+
+// finishTest records the outcome of a test and notifies the reporters.
+func (r *suiteRun) finishTest(testT *testing.T, result TestResult) {
+	testT.Helper()
+	result.Status = StatusPassed
+	result.Metadata = r.metadata(result.Name)
+	if b, ok := r.suite.(suiteBase); ok {
+		result.Assertions = b.base().test.assertions
+		result.Failures = b.base().test.failures
+	}
+	if testT.Failed() {
+		result.Status = StatusFailed
+		if !result.Metadata.isZero() {
+			r.logf(testT, "suite: failed test metadata: %s", result.Metadata)
+		}
+		if b, ok := r.suite.(suiteBase); ok && b.base().test.randUsed {
+			if r.opts.seedOption {
+				r.logf(testT, "suite: test used random seed %d set with WithSeed, change the WithSeed call to run it with another seed", r.opts.seed)
+			} else {
+				r.logf(testT, "suite: test used random seed %d, rerun with -testify.seed=%d", r.opts.seed, r.opts.seed)
+			}
+		}
+		r.infof(testT, "reproduce with: %s", r.reproCommand(testT, result.Name))
+	} else if testT.Skipped() {
+		result.Status = StatusSkipped
+		if r.pending(result.Name) {
+			result.Status = StatusPending
+		}
+		result.SkipCategory = SkipExplicit
+		if b, ok := r.suite.(suiteBase); ok && b.base().test.skipCategory != "" {
+			result.SkipCategory = b.base().test.skipCategory
+			result.SkipReason = b.base().test.skipReason
+		}
+	} else if r.opts.warnNoAssertions && result.Assertions == 0 {
+		r.logf(testT, "suite: warning: %s made no assertions", result.Name)
+	}
+	r.log.flush()
+	r.mu.Lock()
+	r.result.Tests = append(r.result.Tests, result)
+	r.mu.Unlock()
+	for _, reporter := range r.opts.reporters {
+		reporter.TestFinished(result)
+	}
+}
+
+// selected reports whether the filters set with WithFilter select the
+// test method name.
+func (r *suiteRun) selected(name string) bool {
+	for _, filter := range r.opts.filters {
+		if !filter(name) {
+			return false
 		}
 	}
+	return true
 }
 
-// Filtering method according to set regular expression
-// specified command-line argument -m
-func methodFilter(name string) (bool, error) {
-	if ok, _ := regexp.MatchString("^Test", name); !ok {
+// Filtering method according to its prefix and the set regular
+// expression specified command-line argument -m
+func methodFilter(name string, prefixes []string) (bool, error) {
+	if !hasAnyPrefix(name, prefixes) {
 		return false, nil
 	}
-	return regexp.MatchString(*matchMethod, name)
+	return regexp.MatchString(matchMethod.Value.String(), name)
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
 }
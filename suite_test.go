@@ -1,8 +1,6 @@
 package suite
 
 import (
-	"io/ioutil"
-	"os"
 	"testing"
 	"time"
 
@@ -203,26 +201,22 @@ func (s *SuiteLoggingTester) TestLoggingFail() {
 	//assert.NotNil(s.T(), nil) // expected to fail
 }
 
-func runDetachedSuiteWithOutputCapture(s TestingSuite) (bool, string, error) {
-	oldStdout, oldStderr := os.Stdout, os.Stderr
-	defer func() {
-		os.Stdout, os.Stderr = oldStdout, oldStderr
-	}()
-	r, w, _ := os.Pipe()
-	os.Stdout, os.Stderr = w, w
+func runDetachedSuiteWithOutputCapture(s TestingSuite, opts ...Option) (bool, string, error) {
+	return runDetachedWithOutputCapture(func(t *testing.T) {
+		Run(t, s, opts...)
+	})
+}
+
+func runDetachedWithOutputCapture(f func(t *testing.T)) (bool, string, error) {
 	internalTest := testing.InternalTest{
 		Name: "DetachedSuite",
-		F: func(subT *testing.T) {
-			Run(subT, s)
-		},
-	}
-	ok := testing.RunTests(func(_, _ string) (bool, error) { return true, nil }, []testing.InternalTest{internalTest})
-	w.Close()
-	bytes, err := ioutil.ReadAll(r)
-	if err != nil {
-		return false, "", err
+		F:    f,
 	}
-	return ok, string(bytes), nil
+	var ok bool
+	output := redirectOutput(func() {
+		ok = testing.RunTests(func(_, _ string) (bool, error) { return true, nil }, []testing.InternalTest{internalTest})
+	})
+	return ok, output, nil
 }
 
 func TestSuiteLogging(t *testing.T) {
@@ -260,4 +254,175 @@ func TestSuiteErrorsOnBadInterfacesCleanly(t *testing.T) {
 	require.NotEmpty(t, output, "output content must not be empty")
 	assert.False(t, ok, "the suite should not complete as a whole")
 	assert.Contains(t, output, "suite: too many arguments to method TestSomethingWithBadSignature")
+	// The runner's failures point at the call of Run, not at the runner.
+	assert.Regexp(t, `suite_test.go:\d+: suite: too many arguments to method`, output)
+}
+
+type SuiteNamingTester struct {
+	Suite
+
+	TestNames []string
+}
+
+func (s *SuiteNamingTester) TestName() {
+	s.TestNames = append(s.TestNames, s.T().Name())
+}
+
+func TestSuiteNaming(t *testing.T) {
+	s := new(SuiteNamingTester)
+	Run(t, s)
+	Run(t, s, WithNamer(QualifiedNamer))
+	Run(t, s, WithNamer(func(suiteName, methodName string) string {
+		return "with spaces/and slashes"
+	}))
+
+	assert.Equal(t, []string{
+		"TestSuiteNaming/TestName",
+		"TestSuiteNaming/SuiteNamingTester.TestName",
+		"TestSuiteNaming/with_spaces_and_slashes",
+	}, s.TestNames)
+}
+
+type SuiteTracingTester struct {
+	Suite
+}
+
+func (s *SuiteTracingTester) SetupTest() {}
+
+func (s *SuiteTracingTester) TestFail() {
+	s.T().Fail()
+}
+
+func TestSuiteHookTracing(t *testing.T) {
+	_, output, err := runDetachedSuiteWithOutputCapture(new(SuiteTracingTester), WithHookTracing())
+	require.NoError(t, err)
+	assert.Contains(t, output, "SuiteTracingTester.SetupTest started")
+	assert.Contains(t, output, "SuiteTracingTester.SetupTest finished in")
+	assert.NotContains(t, output, "TearDownTest", "hooks the suite doesn't implement are not traced")
+
+	_, output, err = runDetachedSuiteWithOutputCapture(new(SuiteTracingTester), WithVerbosity(VerbosityTrace))
+	require.NoError(t, err)
+	assert.Contains(t, output, "SuiteTracingTester.SetupTest started")
+}
+
+// SuiteWithoutTests fails in SetupSuite so that the runner's output is
+// shown even when not running verbosely.
+type SuiteWithoutTests struct {
+	Suite
+}
+
+func (s *SuiteWithoutTests) SetupSuite() {
+	s.T().Fail()
+}
+
+func TestSuiteQuiet(t *testing.T) {
+	_, output, err := runDetachedSuiteWithOutputCapture(new(SuiteWithoutTests))
+	require.NoError(t, err)
+	assert.Contains(t, output, "no test methods of SuiteWithoutTests matched")
+
+	_, output, err = runDetachedSuiteWithOutputCapture(new(SuiteWithoutTests), WithVerbosity(VerbosityQuiet))
+	require.NoError(t, err)
+	assert.NotContains(t, output, "no test methods")
+}
+
+type SuiteConditionalTester struct {
+	Suite
+
+	SetupSuiteRunCount int
+	TestRunCount       int
+}
+
+func (s *SuiteConditionalTester) ShouldRun() (bool, string) {
+	return false, "database not available"
+}
+
+func (s *SuiteConditionalTester) SetupSuite() {
+	s.SetupSuiteRunCount++
+}
+
+func (s *SuiteConditionalTester) TestNothing() {
+	s.TestRunCount++
+}
+
+func TestConditionalSuite(t *testing.T) {
+	s := new(SuiteConditionalTester)
+	recorder := new(recordingReporter)
+	t.Run("Skipped", func(t *testing.T) {
+		Run(t, s, WithReporter(recorder))
+	})
+
+	assert.Equal(t, 0, s.SetupSuiteRunCount)
+	assert.Equal(t, 0, s.TestRunCount)
+	require.Len(t, recorder.Suites, 1)
+	assert.Equal(t, "database not available", recorder.Suites[0].SkipReason)
+}
+
+type SuitePrefixTester struct {
+	Suite
+
+	Ran []string
+}
+
+func (s *SuitePrefixTester) SpecRuns() {
+	s.Ran = append(s.Ran, "SpecRuns")
+}
+
+func (s *SuitePrefixTester) ShouldRun() (bool, string) {
+	return true, ""
+}
+
+func (s *SuitePrefixTester) TestIgnored() {
+	s.Ran = append(s.Ran, "TestIgnored")
+}
+
+func (s *SuitePrefixTester) ItRuns() {
+	s.Ran = append(s.Ran, "ItRuns")
+}
+
+func TestSuitePrefixes(t *testing.T) {
+	s := new(SuitePrefixTester)
+	Run(t, s, WithPrefix("Spec", "It"))
+	assert.Equal(t, []string{"ItRuns", "SpecRuns"}, s.Ran)
+}
+
+func TestSuiteFilters(t *testing.T) {
+	s := new(SuitePrefixTester)
+	Run(t, s, WithPrefix("Spec", "It", "Test"), WithFilter(func(methodName string) bool {
+		return methodName != "SpecRuns"
+	}), WithFilter(func(methodName string) bool {
+		return methodName != "TestIgnored"
+	}))
+	assert.Equal(t, []string{"ItRuns"}, s.Ran)
+}
+
+type SuiteOnFailureTester struct {
+	Suite
+	fail  bool
+	calls []string
+}
+
+func (s *SuiteOnFailureTester) TearDownSuiteOnFailure() {
+	s.calls = append(s.calls, "TearDownSuiteOnFailure")
+}
+
+func (s *SuiteOnFailureTester) TearDownSuite() {
+	s.calls = append(s.calls, "TearDownSuite")
+}
+
+func (s *SuiteOnFailureTester) TestMaybeFail() {
+	if s.fail {
+		s.T().Fail()
+	}
+}
+
+func TestSuiteTearDownOnFailure(t *testing.T) {
+	s := new(SuiteOnFailureTester)
+	Run(t, s)
+	assert.Equal(t, []string{"TearDownSuite"}, s.calls)
+
+	s = &SuiteOnFailureTester{fail: true}
+	ok, _, err := runDetachedSuiteWithOutputCapture(s)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"TearDownSuiteOnFailure", "TearDownSuite"}, s.calls)
 }
@@ -0,0 +1,171 @@
+package suite
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var summary = flag.Bool("testify.summary", false, "print a summary table of every testify suite to stdout")
+
+// summarySlowest is the number of slowest tests listed by a Summary.
+const summarySlowest = 3
+
+// Summary rolls up the results of the tests of a suite, or of all suites
+// of a package, see PackageResult.
+type Summary struct {
+	// Suite is the name of the suite type, or of the package.
+	Suite string
+	// Counts are the numbers of tests by status.
+	Counts   map[Status]int
+	Tests    int
+	Duration time.Duration
+	// Slowest are the slowest tests that ran, slowest first.
+	Slowest []TestResult
+	// Skipped are the skipped and pending tests.
+	Skipped []TestResult
+	// SkipCategories are the numbers of skipped and pending tests by the
+	// category of their skip reason, see SkipCategory.
+	SkipCategories map[SkipCategory]int
+}
+
+// Summary returns the rollup of the results of the suite's tests.
+func (r SuiteResult) Summary() Summary {
+	return summarizeTests(r.Name, r.Tests, r.Duration)
+}
+
+// summarizeTests rolls up the results of tests, which took duration to run.
+func summarizeTests(name string, tests []TestResult, duration time.Duration) Summary {
+	s := Summary{
+		Suite:          name,
+		Counts:         make(map[Status]int),
+		Tests:          len(tests),
+		Duration:       duration,
+		SkipCategories: make(map[SkipCategory]int),
+	}
+	for _, test := range tests {
+		s.Counts[test.Status]++
+		switch test.Status {
+		case StatusSkipped, StatusPending:
+			s.Skipped = append(s.Skipped, test)
+			if test.SkipCategory != "" {
+				s.SkipCategories[test.SkipCategory]++
+			}
+		default:
+			s.Slowest = append(s.Slowest, test)
+		}
+	}
+	sort.SliceStable(s.Slowest, func(i, j int) bool {
+		return s.Slowest[i].Duration > s.Slowest[j].Duration
+	})
+	if len(s.Slowest) > summarySlowest {
+		s.Slowest = s.Slowest[:summarySlowest]
+	}
+	return s
+}
+
+// String formats the summary as a table, e.g.
+//
+//	ExampleTestSuite: 4 tests in 1.2s: 2 passed, 1 failed, 1 skipped
+//	  slowest  TestImport   812ms
+//	           TestExport   120ms
+//	  skipped  TestUpload   requires S3 credentials [environment]
+//	  skips    environment  1
+func (s Summary) String() string {
+	var counts []string
+	for _, status := range []Status{StatusPassed, StatusFailed, StatusSkipped, StatusPending} {
+		if n := s.Counts[status]; n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, summaryStatuses[status]))
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d tests in %v", s.Suite, s.Tests, s.Duration.Round(time.Millisecond))
+	if len(counts) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(counts, ", "))
+	}
+	b.WriteString("\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for i, test := range s.Slowest {
+		fmt.Fprintf(w, "  %s\t%s\t%v\n", summaryHeading(i, "slowest"), s.testName(test), test.Duration.Round(time.Millisecond))
+	}
+	for i, test := range s.Skipped {
+		reason := test.SkipReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s%s\n", summaryHeading(i, "skipped"), s.testName(test), reason, skipCategorySuffix(test.SkipCategory))
+	}
+	categories := make([]string, 0, len(s.SkipCategories))
+	for category := range s.SkipCategories {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+	for i, category := range categories {
+		fmt.Fprintf(w, "  %s\t%s\t%d\n", summaryHeading(i, "skips"), category, s.SkipCategories[SkipCategory(category)])
+	}
+	w.Flush()
+	return b.String()
+}
+
+// testName returns the name of test in the summary, qualified by the name
+// of its suite when the summary covers several suites.
+func (s Summary) testName(test TestResult) string {
+	if test.Suite == "" || test.Suite == s.Suite {
+		return test.Name
+	}
+	return test.Suite + "." + test.Name
+}
+
+var summaryStatuses = map[Status]string{
+	StatusPassed:  "passed",
+	StatusFailed:  "failed",
+	StatusSkipped: "skipped",
+	StatusPending: "pending",
+}
+
+// skipCategorySuffix returns the category of a skip reason as shown after
+// the reason, or "" if it has none.
+func skipCategorySuffix(category SkipCategory) string {
+	if category == "" {
+		return ""
+	}
+	return " [" + string(category) + "]"
+}
+
+// summaryHeading returns the heading of the i-th row of a section of the
+// summary table, which is only shown on the first row.
+func summaryHeading(i int, heading string) string {
+	if i > 0 {
+		return ""
+	}
+	return heading
+}
+
+type summaryReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewSummaryReporter returns a Reporter that prints the Summary of every
+// suite to w once the suite has finished, after TearDownSuite.
+//
+// The reporter is enabled for all suites by the -testify.summary flag,
+// printing to stdout.
+func NewSummaryReporter(w io.Writer) Reporter {
+	return &summaryReporter{w: w}
+}
+
+func (r *summaryReporter) SuiteStarted(suiteName string)          {}
+func (r *summaryReporter) TestStarted(suiteName, testName string) {}
+func (r *summaryReporter) TestFinished(result TestResult)         {}
+
+func (r *summaryReporter) SuiteFinished(result SuiteResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprint(r.w, result.Summary())
+}
@@ -0,0 +1,65 @@
+package suite
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	result := SuiteResult{
+		Name:     "ExampleTestSuite",
+		Duration: 1234 * time.Millisecond,
+		Tests: []TestResult{
+			{Name: "TestExport", Status: StatusPassed, Duration: 120 * time.Millisecond},
+			{Name: "TestImport", Status: StatusFailed, Duration: 812 * time.Millisecond},
+			{Name: "TestList", Status: StatusPassed, Duration: 3 * time.Millisecond},
+			{Name: "TestSearch", Status: StatusPassed, Duration: 40 * time.Millisecond},
+			{Name: "TestUpload", Status: StatusSkipped, SkipReason: "requires S3 credentials", SkipCategory: SkipEnvironment},
+			{Name: "TestWatch", Status: StatusSkipped, SkipReason: "slow", SkipCategory: SkipShort},
+			{Name: "TestSync", Status: StatusSkipped, SkipReason: "requires NFS", SkipCategory: SkipEnvironment},
+			{Name: "XTestDelete", Status: StatusPending, SkipReason: "pending", SkipCategory: SkipPending},
+		},
+	}
+	summary := result.Summary()
+	assert.Equal(t, map[Status]int{StatusPassed: 3, StatusFailed: 1, StatusSkipped: 3, StatusPending: 1}, summary.Counts)
+	assert.Equal(t, map[SkipCategory]int{SkipEnvironment: 2, SkipShort: 1, SkipPending: 1}, summary.SkipCategories)
+	assert.Equal(t, 8, summary.Tests)
+	require.Len(t, summary.Slowest, 3)
+	assert.Equal(t, "TestSearch", summary.Slowest[2].Name)
+	assert.Equal(t, `ExampleTestSuite: 8 tests in 1.234s: 3 passed, 1 failed, 3 skipped, 1 pending
+  slowest  TestImport   812ms
+           TestExport   120ms
+           TestSearch   40ms
+  skipped  TestUpload   requires S3 credentials [environment]
+           TestWatch    slow [short]
+           TestSync     requires NFS [environment]
+           XTestDelete  pending [pending]
+  skips    environment  2
+           pending      1
+           short        1
+`, summary.String())
+}
+
+type SuiteSummaryTester struct {
+	Suite
+}
+
+func (s *SuiteSummaryTester) TestPasses() {}
+
+func (s *SuiteSummaryTester) TestSkips() {
+	s.SkipUnlessEnv("TESTIFY_SUMMARY_UNSET")
+}
+
+func TestSummaryReporter(t *testing.T) {
+	var buf bytes.Buffer
+	Run(t, new(SuiteSummaryTester), WithReporter(NewSummaryReporter(&buf)))
+	assert.Regexp(t, `^SuiteSummaryTester: 2 tests in [\d.]+m?s: 1 passed, 1 skipped
+  slowest  TestPasses   \d+m?s
+  skipped  TestSkips    environment variable TESTIFY_SUMMARY_UNSET is not set \[environment\]
+  skips    environment  1
+$`, buf.String())
+}
@@ -0,0 +1,111 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	teardownGrace    = flag.Duration("testify.teardown-grace", time.Minute, "time given to the context-aware teardown hooks of testify suites")
+	handleInterrupts = flag.Bool("testify.handle-interrupts", false, "cancel the contexts of testify suites on the first interrupt so that their teardown hooks run")
+)
+
+// ErrInterrupted is the cause of the cancellation of the contexts of
+// suites and tests when the test binary is interrupted, see Interrupted.
+var ErrInterrupted = errors.New("suite: interrupted")
+
+// WithTeardownGrace sets the time after which the context passed to
+// TearDownTestContext and TearDownSuiteContext expires. It defaults to the
+// -testify.teardown-grace flag, one minute by default.
+func WithTeardownGrace(d time.Duration) Option {
+	return func(o *options) {
+		o.teardownGrace = d
+	}
+}
+
+// WithInterruptHandling cancels the contexts of the suite and its tests
+// when the test binary is first interrupted, e.g. by Ctrl-C, so that tests
+// waiting on them return and the teardown hooks run, see Interrupted. A
+// second interrupt terminates the test binary immediately. It defaults to
+// the -testify.handle-interrupts flag, as it takes over os.Interrupt for
+// the whole process, which the code under test may want to handle itself.
+func WithInterruptHandling() Option {
+	return func(o *options) {
+		o.handleInterrupts = true
+	}
+}
+
+// Interrupted reports whether the test binary was interrupted, e.g. by
+// Ctrl-C, before ctx, the context of a test or of a teardown hook, was
+// done. Teardown hooks can use it to skip slow cleanup that a later run
+// or cmd/suitereap takes care of. Interrupts are only noticed by suites
+// handling them, see WithInterruptHandling.
+func Interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInterrupted) || ctx.Value(interruptedKey{}) != nil
+}
+
+// interruptedKey marks the contexts of teardown hooks run after an
+// interrupt, which aren't canceled by it.
+type interruptedKey struct{}
+
+// interruptContext returns the context canceled with ErrInterrupted when
+// the test binary is first interrupted. The interrupts are handled from
+// its first call.
+var interruptContext = sync.OnceValue(func() context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		<-interrupts
+		// Restore the default behavior, terminating the process, for the
+		// next interrupt.
+		signal.Stop(interrupts)
+		cancel(ErrInterrupted)
+	}()
+	return ctx
+})
+
+// parentContext returns the context the context of the suite derives
+// from.
+func (r *suiteRun) parentContext() context.Context {
+	if r.opts.handleInterrupts {
+		return interruptContext()
+	}
+	return context.Background()
+}
+
+// teardownContext returns the context of a teardown hook following a test
+// or suite whose context is ctx.
+func (r *suiteRun) teardownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	teardown := context.WithoutCancel(ctx)
+	if Interrupted(ctx) {
+		teardown = context.WithValue(teardown, interruptedKey{}, true)
+	}
+	return context.WithTimeout(teardown, r.opts.teardownGrace)
+}
+
+// callContextHooks calls the context-aware teardown hook name of the suite
+// and its mixins with a context derived from ctx, see teardownContext.
+func (r *suiteRun) callContextHooks(t *testing.T, ctx context.Context, name string, hook func(target interface{}) func(context.Context)) {
+	t.Helper()
+	teardown, cancel := r.teardownContext(ctx)
+	defer cancel()
+	r.callHooks(t, name, true, func(target interface{}) func() {
+		fn := hook(target)
+		if fn == nil {
+			return nil
+		}
+		return func() {
+			fn(teardown)
+			if teardown.Err() == context.DeadlineExceeded {
+				t.Errorf("suite: %s exceeded its grace period of %v", name, r.opts.teardownGrace)
+			}
+		}
+	})
+}
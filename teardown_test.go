@@ -0,0 +1,94 @@
+package suite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteTeardownContextTester struct {
+	Suite
+	calls     []string
+	deadlines []time.Duration
+	slow      bool
+}
+
+func (s *SuiteTeardownContextTester) TearDownTestContext(ctx context.Context) {
+	s.record("TearDownTestContext", ctx)
+	if s.slow {
+		<-ctx.Done()
+	}
+}
+
+func (s *SuiteTeardownContextTester) TearDownTest() {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *SuiteTeardownContextTester) TearDownSuiteContext(ctx context.Context) {
+	s.record("TearDownSuiteContext", ctx)
+}
+
+func (s *SuiteTeardownContextTester) TearDownSuite() {
+	s.calls = append(s.calls, "TearDownSuite")
+}
+
+func (s *SuiteTeardownContextTester) record(name string, ctx context.Context) {
+	s.calls = append(s.calls, name)
+	if ctx.Err() != nil || Interrupted(ctx) {
+		s.calls = append(s.calls, "done early")
+	}
+	deadline, _ := ctx.Deadline()
+	s.deadlines = append(s.deadlines, time.Until(deadline))
+}
+
+func (s *SuiteTeardownContextTester) TestExpiredContext() {
+	ctx, cancel := context.WithCancel(s.Context())
+	cancel()
+	<-ctx.Done()
+}
+
+func TestTeardownContext(t *testing.T) {
+	s := new(SuiteTeardownContextTester)
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithTeardownGrace(time.Hour), WithTimeout(time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok, output)
+	assert.Equal(t, []string{"TearDownTestContext", "TearDownTest", "TearDownSuiteContext", "TearDownSuite"}, s.calls)
+	for _, d := range s.deadlines {
+		assert.InDelta(t, time.Hour, d, float64(time.Minute))
+	}
+}
+
+func TestTeardownContextGrace(t *testing.T) {
+	s := &SuiteTeardownContextTester{slow: true}
+	ok, output, err := runDetachedSuiteWithOutputCapture(s, WithTeardownGrace(10*time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, output, "suite: TearDownTestContext exceeded its grace period of 10ms")
+	assert.Equal(t, []string{"TearDownTestContext", "TearDownTest", "TearDownSuiteContext", "TearDownSuite"}, s.calls)
+}
+
+func TestTeardownContextInterrupted(t *testing.T) {
+	r := &suiteRun{opts: &options{teardownGrace: time.Minute}}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrInterrupted)
+	assert.True(t, Interrupted(ctx))
+
+	teardown, stop := r.teardownContext(ctx)
+	defer stop()
+	assert.NoError(t, teardown.Err())
+	assert.True(t, Interrupted(teardown))
+
+	teardown, stop = r.teardownContext(context.Background())
+	defer stop()
+	assert.False(t, Interrupted(teardown))
+}
+
+func TestInterruptHandlingOptIn(t *testing.T) {
+	r := &suiteRun{opts: newOptions(nil)}
+	assert.Equal(t, context.Background(), r.parentContext(), "interrupts are left to the code under test by default")
+	r = &suiteRun{opts: newOptions([]Option{WithInterruptHandling()})}
+	assert.Equal(t, interruptContext(), r.parentContext())
+}
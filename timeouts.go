@@ -0,0 +1,47 @@
+package suite
+
+import (
+	"bufio"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var noTimeouts = flag.Bool("testify.no-timeouts", false, "disable the timeouts of testify suites, e.g. while debugging")
+
+// WithoutTimeouts disables the timeouts of the suite: the timeout set with
+// WithTimeout, and the deadlines of Eventually and EventuallyState, which
+// wait for their condition as long as the test runs. It keeps breakpoint
+// sessions from failing tests mid-inspection. Timeouts are also disabled
+// for all suites by the -testify.no-timeouts flag, and when a debugger
+// such as Delve is attached to the test binary.
+func WithoutTimeouts() Option {
+	return func(o *options) {
+		o.noTimeouts = true
+	}
+}
+
+// debuggerAttached reports whether a debugger is attached to the process.
+// It is only detected on Linux.
+var debuggerAttached = sync.OnceValue(func() bool {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return tracerPID(bufio.NewScanner(f)) != 0
+})
+
+// tracerPID returns the PID of the process tracing the process whose
+// /proc/<pid>/status is scanned, or 0 if it isn't traced.
+func tracerPID(status *bufio.Scanner) int {
+	for status.Scan() {
+		if value, ok := strings.CutPrefix(status.Text(), "TracerPid:"); ok {
+			pid, _ := strconv.Atoi(strings.TrimSpace(value))
+			return pid
+		}
+	}
+	return 0
+}
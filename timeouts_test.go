@@ -0,0 +1,43 @@
+package suite
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteTimeoutsTester struct {
+	Suite
+}
+
+func (s *SuiteTimeoutsTester) TestSlow() {
+	time.Sleep(50 * time.Millisecond)
+}
+
+func (s *SuiteTimeoutsTester) TestEventually() {
+	ready := time.Now().Add(50 * time.Millisecond)
+	s.Eventually(func() bool { return time.Now().After(ready) }, 10*time.Millisecond, time.Millisecond)
+}
+
+func TestWithoutTimeouts(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteTimeoutsTester), WithTimeout(10*time.Millisecond), WithoutTimeouts())
+	require.NoError(t, err)
+	assert.True(t, ok, output)
+
+	if !debuggerAttached() {
+		ok, _, err = runDetachedSuiteWithOutputCapture(new(SuiteTimeoutsTester), WithTimeout(10*time.Millisecond))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
+
+func TestTracerPID(t *testing.T) {
+	status := "Name:\tdlv\nState:\tS (sleeping)\nTracerPid:\t4242\nUid:\t0\n"
+	assert.Equal(t, 4242, tracerPID(bufio.NewScanner(strings.NewReader(status))))
+	assert.Equal(t, 0, tracerPID(bufio.NewScanner(strings.NewReader("TracerPid:\t0\n"))))
+	assert.Equal(t, 0, tracerPID(bufio.NewScanner(strings.NewReader(""))))
+}
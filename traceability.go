@@ -0,0 +1,92 @@
+package suite
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// traceabilityFromFlags is shared by all suites of the test binary, so
+// that the file written for -testify.traceability covers all of them.
+var traceabilityFromFlags = &traceabilityReporter{}
+
+// traceabilityReporter collects the requirements verified by tests.
+type traceabilityReporter struct {
+	mu   sync.Mutex
+	path string
+	rows []traceabilityRow
+}
+
+type traceabilityRow struct {
+	requirement, suite, test, id string
+	status                       Status
+}
+
+// NewTraceabilityReporter returns a Reporter writing a requirements
+// traceability matrix to the file at path: a CSV file with a row per
+// requirement and test verifying it, taken from Metadata.Requirements,
+// with the outcome of the test:
+//
+//	requirement,suite,test,id,status
+//	REQ-12,PaymentSuite,TestRefund,pay-3,pass
+//
+// The file is rewritten whenever a suite finishes, so that it covers all
+// suites that have used the reporter. Tests without requirements are not
+// listed.
+//
+// A reporter shared by all suites of the test binary is enabled by the
+// -testify.traceability flag.
+func NewTraceabilityReporter(path string) Reporter {
+	return &traceabilityReporter{path: path}
+}
+
+func (r *traceabilityReporter) SuiteStarted(suiteName string)          {}
+func (r *traceabilityReporter) TestStarted(suiteName, testName string) {}
+func (r *traceabilityReporter) TestFinished(result TestResult)         {}
+
+func (r *traceabilityReporter) SuiteFinished(result SuiteResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, test := range result.Tests {
+		for _, requirement := range test.Metadata.Requirements {
+			r.rows = append(r.rows, traceabilityRow{requirement, test.Suite, test.Name, test.Metadata.ID, test.Status})
+		}
+	}
+	sort.SliceStable(r.rows, func(i, j int) bool {
+		a, b := r.rows[i], r.rows[j]
+		if a.requirement != b.requirement {
+			return a.requirement < b.requirement
+		}
+		if a.suite != b.suite {
+			return a.suite < b.suite
+		}
+		return a.test < b.test
+	})
+	path := r.path
+	if path == "" {
+		path = *traceability
+	}
+	if err := r.write(path); err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to write traceability matrix: %v\n", err)
+	}
+}
+
+func (r *traceabilityReporter) write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"requirement", "suite", "test", "id", "status"})
+	for _, row := range r.rows {
+		w.Write([]string{row.requirement, row.suite, row.test, row.id, row.status.String()})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,42 @@
+package suite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteTraceabilityTester struct {
+	Suite
+}
+
+func (s *SuiteTraceabilityTester) Metadata() map[string]Metadata {
+	return map[string]Metadata{
+		"TestLogin":  {ID: "auth-1", Requirements: []string{"REQ-2", "REQ-1"}},
+		"TestLogout": {Requirements: []string{"REQ-2"}},
+	}
+}
+
+func (s *SuiteTraceabilityTester) TestLogin() {}
+
+func (s *SuiteTraceabilityTester) TestLogout() {
+	s.T().Fail()
+}
+
+func (s *SuiteTraceabilityTester) TestUntraced() {}
+
+func TestTraceabilityReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traceability.csv")
+	runDetachedSuiteWithOutputCapture(new(SuiteTraceabilityTester), WithReporter(NewTraceabilityReporter(path)))
+
+	matrix, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `requirement,suite,test,id,status
+REQ-1,SuiteTraceabilityTester,TestLogin,auth-1,pass
+REQ-2,SuiteTraceabilityTester,TestLogin,auth-1,pass
+REQ-2,SuiteTraceabilityTester,TestLogout,,fail
+`, string(matrix))
+}
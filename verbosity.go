@@ -0,0 +1,61 @@
+package suite
+
+import (
+	"flag"
+	"testing"
+)
+
+var quiet = flag.Bool("testify.quiet", false, "suppress informational output of the testify suite runner")
+var traceHooks = flag.Bool("testify.trace-hooks", false, "log every hook invocation of the testify suite")
+
+// Verbosity controls how much the runner itself logs, in addition to the
+// output of the tests.
+type Verbosity int
+
+const (
+	// VerbosityQuiet suppresses all informational output of the runner,
+	// so that only the output of the tests themselves appears.
+	VerbosityQuiet Verbosity = iota - 1
+	// VerbosityNormal is the default.
+	VerbosityNormal
+	// VerbosityTrace additionally logs every hook invocation with
+	// timestamps.
+	VerbosityTrace
+)
+
+// WithVerbosity sets how much the runner logs. It overrides the
+// -testify.quiet and -testify.trace-hooks flags.
+func WithVerbosity(v Verbosity) Option {
+	return func(o *options) {
+		o.verbosity = v
+	}
+}
+
+// WithHookTracing logs every invocation of a hook of the suite (SetupSuite,
+// BeforeTest, etc.) with timestamps through the *testing.T it runs under.
+// It is equivalent to WithVerbosity(VerbosityTrace) and to the
+// -testify.trace-hooks flag.
+func WithHookTracing() Option {
+	return WithVerbosity(VerbosityTrace)
+}
+
+// verbosityFromFlags returns the verbosity selected on the command line.
+func verbosityFromFlags() Verbosity {
+	switch {
+	case *traceHooks:
+		return VerbosityTrace
+	case *quiet:
+		return VerbosityQuiet
+	}
+	return VerbosityNormal
+}
+
+// infof logs an informational message of the runner to t, unless the
+// runner is quiet.
+func (r *suiteRun) infof(t *testing.T, format string, args ...interface{}) {
+	t.Helper()
+	if r.opts.verbosity < VerbosityNormal {
+		return
+	}
+	r.logf(t, "suite: "+format, args...)
+}
@@ -0,0 +1,149 @@
+package suite
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var webhook = flag.String("testify.webhook", "", "POST a summary of every failed testify suite to this URL")
+var webhookFormat = flag.String("testify.webhook-format", "json", "payload posted to -testify.webhook: json or slack")
+
+// webhookReporter posts a summary of failed suites to a URL.
+type webhookReporter struct {
+	url    string
+	slack  bool
+	client *http.Client
+}
+
+type webhookSummary struct {
+	Suite       string        `json:"suite"`
+	Passed      int           `json:"passed"`
+	Failed      int           `json:"failed"`
+	Skipped     int           `json:"skipped"`
+	DurationMS  float64       `json:"duration_ms"`
+	FailedTests []webhookTest `json:"failed_tests"`
+	Artifacts   []string      `json:"artifacts,omitempty"`
+}
+
+type webhookTest struct {
+	Name       string  `json:"name"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// NewWebhookReporter returns a Reporter posting a JSON summary of a suite
+// to url when the suite finishes with failures: the number of passed,
+// failed and skipped tests, the failed tests with their durations, and
+// the reports written for the -testify.json, -testify.junit-dir and
+// -testify.traceability flags. Suites without failures are not reported,
+// which makes the reporter suited for long suites run outside of CI.
+//
+// The reporter is enabled for all suites by the -testify.webhook flag.
+func NewWebhookReporter(url string) Reporter {
+	return &webhookReporter{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewSlackReporter is like NewWebhookReporter, but posts the summary as
+// a message to a Slack incoming webhook, or any service accepting the same
+// payload.
+//
+// The reporter is enabled for all suites by setting the
+// -testify.webhook-format flag to slack.
+func NewSlackReporter(url string) Reporter {
+	return &webhookReporter{url: url, slack: true, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (r *webhookReporter) SuiteStarted(suiteName string)          {}
+func (r *webhookReporter) TestStarted(suiteName, testName string) {}
+func (r *webhookReporter) TestFinished(result TestResult)         {}
+
+func (r *webhookReporter) SuiteFinished(result SuiteResult) {
+	if !result.Failed() {
+		return
+	}
+	summary := summarize(result)
+	var payload interface{} = summary
+	if r.slack {
+		payload = map[string]string{"text": summary.text()}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to encode webhook payload: %v\n", err)
+		return
+	}
+	resp, err := r.client.Post(r.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testify: failed to post to webhook: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		fmt.Fprintf(os.Stderr, "testify: webhook responded with %s\n", resp.Status)
+	}
+}
+
+func summarize(result SuiteResult) webhookSummary {
+	summary := webhookSummary{
+		Suite:       result.Name,
+		DurationMS:  milliseconds(result.Duration),
+		FailedTests: []webhookTest{},
+		Artifacts:   reportArtifacts(result.Name),
+	}
+	for _, test := range result.Tests {
+		switch test.Status {
+		case StatusPassed:
+			summary.Passed++
+		case StatusFailed:
+			summary.Failed++
+			summary.FailedTests = append(summary.FailedTests, webhookTest{Name: test.Name, DurationMS: milliseconds(test.Duration)})
+		case StatusSkipped, StatusPending:
+			summary.Skipped++
+		}
+	}
+	return summary
+}
+
+// text formats the summary as a chat message.
+func (s webhookSummary) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s* failed: %d passed, %d failed, %d skipped in %.1fs\n",
+		s.Suite, s.Passed, s.Failed, s.Skipped, s.DurationMS/1000)
+	for _, test := range s.FailedTests {
+		fmt.Fprintf(&b, "• %s (%.1fs)\n", test.Name, test.DurationMS/1000)
+	}
+	for _, artifact := range s.Artifacts {
+		fmt.Fprintf(&b, "%s\n", artifact)
+	}
+	return b.String()
+}
+
+// reportArtifacts returns the reports written for the suite by the
+// reporters enabled on the command line.
+func reportArtifacts(suiteName string) []string {
+	var artifacts []string
+	if *jsonReport != "" {
+		artifacts = append(artifacts, *jsonReport)
+	}
+	if *junitDir != "" {
+		artifacts = append(artifacts, filepath.Join(*junitDir, "TEST-"+suiteName+".xml"))
+	}
+	if *traceability != "" {
+		artifacts = append(artifacts, *traceability)
+	}
+	return artifacts
+}
+
+// webhookFromFlags returns the webhook reporter enabled on the command
+// line.
+func webhookFromFlags() Reporter {
+	if *webhookFormat == "slack" {
+		return NewSlackReporter(*webhook)
+	}
+	return NewWebhookReporter(*webhook)
+}
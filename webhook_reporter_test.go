@@ -0,0 +1,69 @@
+package suite
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteWebhookTester struct {
+	Suite
+	fail bool
+}
+
+func (s *SuiteWebhookTester) TestPass() {}
+
+func (s *SuiteWebhookTester) TestMaybeFail() {
+	if s.fail {
+		s.T().Fail()
+	}
+}
+
+func (s *SuiteWebhookTester) TestSkip() {
+	s.T().Skip()
+}
+
+func webhookServer(t *testing.T) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestWebhookReporter(t *testing.T) {
+	server, bodies := webhookServer(t)
+
+	runDetachedSuiteWithOutputCapture(&SuiteWebhookTester{}, WithReporter(NewWebhookReporter(server.URL)))
+	assert.Empty(t, *bodies, "suites without failures are not reported")
+
+	runDetachedSuiteWithOutputCapture(&SuiteWebhookTester{fail: true}, WithReporter(NewWebhookReporter(server.URL)))
+	require.Len(t, *bodies, 1)
+	var summary webhookSummary
+	require.NoError(t, json.Unmarshal([]byte((*bodies)[0]), &summary))
+	assert.Equal(t, "SuiteWebhookTester", summary.Suite)
+	assert.Equal(t, 1, summary.Passed)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 1, summary.Skipped)
+	require.Len(t, summary.FailedTests, 1)
+	assert.Equal(t, "TestMaybeFail", summary.FailedTests[0].Name)
+}
+
+func TestSlackReporter(t *testing.T) {
+	server, bodies := webhookServer(t)
+
+	runDetachedSuiteWithOutputCapture(&SuiteWebhookTester{fail: true}, WithReporter(NewSlackReporter(server.URL)))
+	require.Len(t, *bodies, 1)
+	var message map[string]string
+	require.NoError(t, json.Unmarshal([]byte((*bodies)[0]), &message))
+	assert.Contains(t, message["text"], "*SuiteWebhookTester* failed: 1 passed, 1 failed, 1 skipped")
+	assert.Contains(t, message["text"], "• TestMaybeFail")
+}
@@ -0,0 +1,326 @@
+package suite
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webSocketGUID is the GUID of the WebSocket protocol, see RFC 6455.
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xa
+)
+
+// WebSocketConn is a minimal WebSocket connection, exchanging text and
+// binary messages. Its methods can be called concurrently with each other.
+type WebSocketConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	client bool
+
+	readMu  sync.Mutex
+	writeMu sync.Mutex
+}
+
+// ReadMessage returns the next text or binary message of the connection,
+// answering pings meanwhile. It returns io.EOF once the peer closed the
+// connection.
+func (c *WebSocketConn) ReadMessage() ([]byte, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wsText, wsBinary, wsContinuation:
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return nil, err
+			}
+		case wsClose:
+			c.writeFrame(wsClose, payload)
+			return nil, io.EOF
+		}
+	}
+}
+
+// WriteMessage sends a text message.
+func (c *WebSocketConn) WriteMessage(message []byte) error {
+	return c.writeFrame(wsText, message)
+}
+
+// Close closes the connection, notifying the peer.
+func (c *WebSocketConn) Close() error {
+	c.writeFrame(wsClose, []byte{0x03, 0xe8}) // 1000, normal closure
+	return c.conn.Close()
+}
+
+func (c *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var n [2]byte
+		if _, err := io.ReadFull(c.r, n[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(n[:]))
+	case 127:
+		var n [8]byte
+		if _, err := io.ReadFull(c.r, n[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(n[:])
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame sends payload in a single frame. Frames sent by clients are
+// masked, as the protocol requires.
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	frame := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n < 126:
+		frame[1] = byte(n)
+	case n <= 0xffff:
+		frame[1] = 126
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame[1] = 127
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if !c.client {
+		_, err := c.conn.Write(append(frame, payload...))
+		return err
+	}
+	frame[1] |= 0x80
+	var mask [4]byte
+	rand.Read(mask[:])
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// webSocketAccept returns the Sec-WebSocket-Accept header answering the
+// Sec-WebSocket-Key header key.
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// NewWebSocketServer starts a server accepting WebSocket connections and
+// serving each of them with handler in its own goroutine, e.g. to stand
+// in for the backend of the client under test. Connections are closed
+// when handler returns. The server is closed when the current test
+// finishes, or the suite when called in SetupSuite. Its URL uses the ws
+// scheme.
+func (suite *Suite) NewWebSocketServer(handler func(conn *WebSocketConn)) *httptest.Server {
+	t := suite.T()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+			http.Error(w, "not a WebSocket handshake", http.StatusBadRequest)
+			return
+		}
+		netConn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer netConn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", webSocketAccept(key))
+		if err := rw.Flush(); err != nil {
+			return
+		}
+		conn := &WebSocketConn{conn: netConn, r: rw.Reader}
+		handler(conn)
+		conn.Close()
+	}))
+	server.URL = "ws" + strings.TrimPrefix(server.URL, "http")
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dialWebSocket opens a WebSocket connection to rawURL.
+func dialWebSocket(ctx context.Context, rawURL string, header http.Header) (*WebSocketConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("unsupported scheme %q, want ws", u.Scheme)
+	}
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	u.Scheme = "http"
+	req := &http.Request{Method: http.MethodGet, URL: u, Host: u.Host, Header: http.Header{}}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(netConn); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		netConn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %s", resp.Status)
+	}
+	return &WebSocketConn{conn: netConn, r: r, client: true}, nil
+}
+
+// WebSocket is a WebSocket connection of a test, see Suite.DialWebSocket.
+// Its methods fail the test when the connection fails, and stop waiting
+// for messages when the context of the test is done.
+type WebSocket struct {
+	*WebSocketConn
+	suite *Suite
+	ctx   context.Context
+}
+
+// DialWebSocket opens a WebSocket connection to url, a ws URL, with the
+// headers of the handshake request, failing the current test if it can't.
+// The connection is closed when the test finishes, or the suite when
+// called in SetupSuite.
+func (suite *Suite) DialWebSocket(url string, header http.Header) *WebSocket {
+	t := suite.T()
+	t.Helper()
+	ctx := suite.Context()
+	conn, err := dialWebSocket(ctx, url, header)
+	if err != nil {
+		t.Fatalf("suite: failed to dial WebSocket %s: %v", url, err)
+	}
+	// Interrupt reads and writes once the test is done.
+	stop := context.AfterFunc(ctx, func() {
+		conn.conn.SetDeadline(time.Now())
+	})
+	t.Cleanup(func() {
+		stop()
+		conn.Close()
+	})
+	return &WebSocket{WebSocketConn: conn, suite: suite, ctx: ctx}
+}
+
+// Send sends the text message.
+func (ws *WebSocket) Send(message string) {
+	t := ws.suite.T()
+	t.Helper()
+	if err := ws.WriteMessage([]byte(message)); err != nil {
+		t.Fatalf("suite: failed to send WebSocket message: %v", err)
+	}
+}
+
+// SendJSON sends v encoded as JSON.
+func (ws *WebSocket) SendJSON(v interface{}) {
+	t := ws.suite.T()
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("suite: failed to encode WebSocket message: %v", err)
+	}
+	ws.Send(string(data))
+}
+
+// Receive returns the next message.
+func (ws *WebSocket) Receive() string {
+	t := ws.suite.T()
+	t.Helper()
+	message, err := ws.ReadMessage()
+	if errors.Is(err, io.EOF) {
+		t.Fatalf("suite: WebSocket closed while waiting for a message")
+	}
+	if err != nil {
+		if ctxErr := ws.ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		t.Fatalf("suite: failed to receive WebSocket message: %v", err)
+	}
+	return string(message)
+}
+
+// Expect asserts that the next message is expected.
+func (ws *WebSocket) Expect(expected string, msgAndArgs ...interface{}) bool {
+	ws.suite.T().Helper()
+	ws.suite.countAssertion()
+	if actual := ws.Receive(); actual != expected {
+		ws.suite.errorf(msgAndArgs, "WebSocket message is %q, expected %q", actual, expected)
+		return false
+	}
+	return true
+}
+
+// ExpectJSON asserts that the next message is the JSON document expected,
+// as compared by Suite.JSONEq.
+func (ws *WebSocket) ExpectJSON(expected string, msgAndArgs ...interface{}) bool {
+	ws.suite.T().Helper()
+	return ws.suite.JSONEq(expected, ws.Receive(), msgAndArgs...)
+}
@@ -0,0 +1,68 @@
+package suite
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SuiteWebSocketTester struct {
+	Suite
+	url string
+}
+
+func (s *SuiteWebSocketTester) SetupSuite() {
+	s.url = s.NewWebSocketServer(func(conn *WebSocketConn) {
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(message) == "silence" {
+				continue
+			}
+			conn.WriteMessage([]byte(strings.ToUpper(string(message))))
+		}
+	}).URL
+}
+
+func (s *SuiteWebSocketTester) TestEcho() {
+	ws := s.DialWebSocket(s.url, http.Header{"Authorization": {"Bearer token"}})
+	ws.Send("hello")
+	ws.Expect("HELLO")
+	ws.SendJSON(map[string]int{"n": 1})
+	ws.ExpectJSON(`{"N": 1}`)
+	long := strings.Repeat("a", 70000)
+	ws.Send(long)
+	ws.Expect(strings.ToUpper(long))
+}
+
+func (s *SuiteWebSocketTester) TestMismatch() {
+	ws := s.DialWebSocket(s.url, nil)
+	ws.Send("hello")
+	ws.Expect("hello")
+}
+
+func (s *SuiteWebSocketTester) TestTimeout() {
+	ws := s.DialWebSocket(s.url, nil)
+	ws.Send("silence")
+	ws.Receive()
+}
+
+func TestWebSocket(t *testing.T) {
+	ok, output, err := runDetachedSuiteWithOutputCapture(new(SuiteWebSocketTester), WithTimeout(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NotContains(t, output, "FAIL: DetachedSuite/TestEcho")
+	assert.Contains(t, output, `WebSocket message is "HELLO", expected "hello"`)
+	assert.Contains(t, output, "suite: failed to receive WebSocket message: context ")
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	// The example of RFC 6455.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", webSocketAccept("dGhlIHNhbXBsZSBub25jZQ=="))
+}